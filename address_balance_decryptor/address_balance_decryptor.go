@@ -92,5 +92,7 @@ func NewAddressBalanceDecryptor(useStore bool) (*AddressBalanceDecryptor, error)
 		go addressBalanceDecryptor.saveToStore()
 	}
 
+	addressBalanceDecryptor.initMetrics()
+
 	return addressBalanceDecryptor, nil
 }
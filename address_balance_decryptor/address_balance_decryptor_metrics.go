@@ -0,0 +1,19 @@
+package address_balance_decryptor
+
+import (
+	"pandora-pay/helpers/metrics"
+	"sync/atomic"
+)
+
+func (decryptor *AddressBalanceDecryptor) initMetrics() {
+	metrics.RegisterGauge("pandora_balance_decryptor_pending", "Balances waiting to be decrypted", func() float64 {
+		count := 0
+		decryptor.all.Range(func(key string, work *addressBalanceDecryptorWork) bool {
+			if atomic.LoadInt32(&work.status) == ADDRESS_BALANCE_DECRYPTED_INIT {
+				count++
+			}
+			return true
+		})
+		return float64(count)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/forging"
+	"pandora-pay/explorer"
 	"pandora-pay/gui"
 	"pandora-pay/mempool"
 	"pandora-pay/settings"
@@ -18,9 +19,11 @@ var (
 	Mempool                 *mempool.Mempool
 	AddressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor
 	Chain                   *blockchain.Blockchain
+	Explorer                *explorer.Explorer
 )
 
 func Close() {
+	Mempool.Close()
 	store.DBClose()
 	gui.GUI.Close()
 	Forging.Close()
@@ -11,7 +11,6 @@ import (
 	"pandora-pay/blockchain/blocks/block/difficulty"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/data_storage"
-	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/forging/forging_block_work"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
@@ -73,6 +72,42 @@ func (chain *Blockchain) validateBlocks(blocksComplete []*block_complete.BlockCo
 	return
 }
 
+// addBlockReward increases the native supply with the block reward and returns the new supply
+func addBlockReward(dataStorage *data_storage.DataStorage, reward uint64) (uint64, error) {
+
+	ast, err := dataStorage.Asts.Get(string(config_coins.NATIVE_ASSET_FULL))
+	if err != nil {
+		return 0, err
+	}
+
+	if err = ast.AddNativeSupply(true, reward); err != nil {
+		return 0, err
+	}
+	if err = dataStorage.Asts.Update(string(config_coins.NATIVE_ASSET_FULL), ast); err != nil {
+		return 0, err
+	}
+
+	return ast.Supply, nil
+}
+
+// includeBlockComplete includes the block txs and processes the pending stakes and the conditional payments of the block height
+func includeBlockComplete(blkComplete *block_complete.BlockComplete, dataStorage *data_storage.DataStorage) error {
+
+	if err := blkComplete.IncludeBlockComplete(dataStorage); err != nil {
+		return fmt.Errorf("Error including block %d into Blockchain: %s", blkComplete.Height, err.Error())
+	}
+
+	if err := dataStorage.ProcessPendingStakes(blkComplete.Height); err != nil {
+		return errors.New("Error Processing Pending Stakes: " + err.Error())
+	}
+
+	if err := dataStorage.ProcessConditionalPayments(blkComplete.Height); err != nil {
+		return errors.New("Error Processing Pending Future: " + err.Error())
+	}
+
+	return nil
+}
+
 func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplete, calledByForging bool, exceptSocketUUID advanced_connection_types.UUID) (kernelHash []byte, err error) {
 
 	if err = chain.validateBlocks(blocksComplete); err != nil {
@@ -166,6 +201,16 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 			firstBlockComplete := blocksComplete[0]
 			if firstBlockComplete.Block.Height < newChainData.Height {
 
+				if config.NODE_PRUNE > 0 {
+					var prunedHeight uint64
+					if prunedHeight, err = chain.LoadPrunedHeight(writer); err != nil {
+						return
+					}
+					if firstBlockComplete.Block.Height < prunedHeight {
+						return errors.New("Fork is older than the pruned blocks")
+					}
+				}
+
 				index := newChainData.Height - 1
 				for {
 
@@ -277,19 +322,9 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 					}
 
 					//increase supply
-					var ast *asset.Asset
-					if ast, err = dataStorage.Asts.Get(string(config_coins.NATIVE_ASSET_FULL)); err != nil {
-						return
-					}
-
-					if err = ast.AddNativeSupply(true, reward); err != nil {
+					if newChainData.Supply, err = addBlockReward(dataStorage, reward); err != nil {
 						return
 					}
-					if err = dataStorage.Asts.Update(string(config_coins.NATIVE_ASSET_FULL), ast); err != nil {
-						return
-					}
-
-					newChainData.Supply = ast.Supply
 
 					if difficulty.CheckKernelHashBig(blkComplete.Block.Bloom.KernelHashStaked, newChainData.Target) != true {
 						return errors.New("KernelHash Difficulty is not met")
@@ -311,16 +346,8 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Timestamp is too much into the future")
 					}
 
-					if err = blkComplete.IncludeBlockComplete(dataStorage); err != nil {
-						return fmt.Errorf("Error including block %d into Blockchain: %s", blkComplete.Height, err.Error())
-					}
-
-					if err = dataStorage.ProcessPendingStakes(blkComplete.Height); err != nil {
-						return errors.New("Error Processing Pending Stakes: " + err.Error())
-					}
-
-					if err = dataStorage.ProcessConditionalPayments(blkComplete.Height); err != nil {
-						return errors.New("Error Processing Pending Future: " + err.Error())
+					if err = includeBlockComplete(blkComplete, dataStorage); err != nil {
+						return
 					}
 
 					//to detect if the savedBlock was done correctly
@@ -407,6 +434,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 					}
 				}
 
+				if err = chain.pruneBlocksComplete(writer, newChainData.Height, dataStorage); err != nil {
+					panic(err)
+				}
+
 				//let's keep the order as well
 				var removedCount, insertedCount int
 				for _, change := range allTransactionsChanges {
@@ -519,6 +550,9 @@ func CreateBlockchain(mempool *mempool.Mempool) (*Blockchain, error) {
 	chain.updatesQueue.processBlockchainUpdateMempool()
 	chain.updatesQueue.processBlockchainUpdateNotifications()
 
+	chain.initCLI()
+	chain.initMetrics()
+
 	return chain, nil
 }
 
@@ -539,6 +573,11 @@ func (chain *Blockchain) InitializeChain() (err error) {
 	chainData := chain.GetChainData()
 	chainData.updateChainInfo()
 
+	if err = chain.loadFeeEstimator(); err != nil {
+		gui.GUI.Error("Fee estimator couldn't be seeded", err)
+		err = nil
+	}
+
 	return
 }
 
@@ -0,0 +1,47 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+)
+
+func (chain *Blockchain) initCLI() {
+
+	cliExportSnapshot := func(cmd string, ctx context.Context) (err error) {
+
+		filename := gui.GUI.OutputReadFilename("Path to export Snapshot", "snapshot", false)
+
+		snapshot, err := chain.ExportSnapshot(filename)
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Snapshot exported successfully to: %s", filename))
+		gui.GUI.OutputWrite(fmt.Sprintf("Height: %d", snapshot.Height))
+		gui.GUI.OutputWrite(fmt.Sprintf("Hash: %s", hex.EncodeToString(snapshot.Hash)))
+		return
+	}
+
+	cliVerifyChain := func(cmd string, ctx context.Context) (err error) {
+
+		verification, err := chain.VerifyChain()
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Blocks replayed: %d", verification.Height))
+		gui.GUI.OutputWrite(fmt.Sprintf("State entries compared: %d", verification.Keys))
+		if verification.Diverged {
+			gui.GUI.OutputWrite(fmt.Sprintf("Diverged at height %d: %s", verification.DivergedHeight, verification.Reason))
+		} else {
+			gui.GUI.OutputWrite("State matches the replayed blocks")
+		}
+		return
+	}
+
+	gui.GUI.CommandDefineCallback("Export Chain Snapshot", cliExportSnapshot, config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL)
+	gui.GUI.CommandDefineCallback("Verify Chain", cliVerifyChain, config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL)
+}
@@ -109,6 +109,8 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 		false,
 		false,
 		false,
+		false,
+		false,
 		byte(config_coins.DECIMAL_SEPARATOR),
 		config_coins.MAX_SUPPLY_COINS_UNITS,
 		supply,
@@ -0,0 +1,19 @@
+package blockchain
+
+import (
+	"math/big"
+	"pandora-pay/helpers/metrics"
+)
+
+func (chain *Blockchain) initMetrics() {
+
+	metrics.RegisterGauge("pandora_chain_height", "Height of the blockchain", func() float64 {
+		return float64(chain.GetChainData().Height)
+	})
+
+	metrics.RegisterGauge("pandora_chain_total_difficulty", "Total difficulty of the blockchain", func() float64 {
+		out, _ := new(big.Float).SetInt(chain.GetChainData().BigTotalDifficulty).Float64()
+		return out
+	})
+
+}
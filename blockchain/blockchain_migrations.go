@@ -0,0 +1,104 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+// ChainMigrations changes the layout of an existing StoreBlockchain without resyncing. A migration with the next version is appended
+// whenever the DataStorage hashmaps, the chain keys or the serialized versions of the stored objects change. The new stores skip them
+var ChainMigrations = []*store.StoreMigration{
+	{1, "Prepend the version to the plain accounts", migratePlainAccountsVersion},
+}
+
+// same layout as the hash_map transitions
+type migrationTransitionChange struct {
+	Key        []byte
+	Transition []byte
+}
+
+type migrationTransitionChanges struct {
+	List []*migrationTransitionChange
+}
+
+// migrateHashMap re-encodes the stored elements and the transitions of the hashmap
+func migrateHashMap(tx store_db_interface.StoreDBTransactionInterface, name string, reencode func(key, data []byte) ([]byte, error), progress func(done, total uint64)) (err error) {
+
+	changed := make(map[string][]byte)
+
+	prefix := name + ":map:"
+
+	var total, done uint64
+	tx.IteratePrefix(prefix, "", func(key string, value []byte) bool {
+		total += 1
+		return true
+	})
+
+	tx.IteratePrefix(prefix, "", func(key string, value []byte) bool {
+		if changed[key], err = reencode([]byte(key[len(prefix):]), value); err != nil {
+			return false
+		}
+		done += 1
+		progress(done, total)
+		return true
+	})
+	if err != nil {
+		return
+	}
+
+	tx.IteratePrefix(name+":transitions:", "", func(key string, value []byte) bool {
+
+		changes := &migrationTransitionChanges{}
+		if err = msgpack.Unmarshal(value, changes); err != nil {
+			return false
+		}
+
+		//a nil transition means that the element didn't exist
+		for _, change := range changes.List {
+			if change.Transition != nil {
+				if change.Transition, err = reencode(change.Key, change.Transition); err != nil {
+					return false
+				}
+			}
+		}
+
+		if changed[key], err = msgpack.Marshal(changes); err != nil {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return
+	}
+
+	for key, data := range changed {
+		tx.Put(key, data)
+	}
+
+	return
+}
+
+// migratePlainAccountsVersion prepends the simple version to the plain accounts which were stored without a version
+func migratePlainAccountsVersion(tx store_db_interface.StoreDBTransactionInterface, progress func(done, total uint64)) error {
+	return migrateHashMap(tx, "plainAccs", func(key, data []byte) ([]byte, error) {
+
+		out := binary.AppendUvarint(nil, plain_account.PLAIN_ACCOUNT_VERSION_SIMPLE)
+		out = append(out, data...)
+
+		plainAcc := plain_account.NewPlainAccount(key, 0)
+		r := advanced_buffers.NewBufferReader(out)
+		if err := plainAcc.Deserialize(r); err != nil {
+			return nil, err
+		}
+		if r.Position != len(out) {
+			return nil, errors.New("Plain Account has extra bytes")
+		}
+
+		return out, nil
+	}, progress)
+}
@@ -0,0 +1,61 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestMigratePlainAccountsVersion(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("test")
+	assert.NoError(t, err)
+
+	publicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+	plainAcc := plain_account.NewPlainAccount(publicKey, 0)
+	plainAcc.Nonce = 5
+	plainAcc.Unclaimed = 100
+
+	//the layout without the version
+	data := helpers.SerializeToBytes(plainAcc)[1:]
+
+	transitions, err := msgpack.Marshal(&migrationTransitionChanges{[]*migrationTransitionChange{{publicKey, data}, {[]byte("missing"), nil}}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("plainAccs:map:"+string(publicKey), data)
+		tx.Put("plainAccs:transitions:1", transitions)
+		return migratePlainAccountsVersion(tx, func(done, total uint64) {})
+	}))
+
+	decode := func(data []byte) *plain_account.PlainAccount {
+		plainAcc2 := plain_account.NewPlainAccount(publicKey, 0)
+		assert.NoError(t, plainAcc2.Deserialize(advanced_buffers.NewBufferReader(data)))
+		return plainAcc2
+	}
+
+	assert.NoError(t, db.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+
+		assert.Equal(t, plainAcc, decode(tx.Get("plainAccs:map:"+string(publicKey))))
+
+		changes := &migrationTransitionChanges{}
+		assert.NoError(t, msgpack.Unmarshal(tx.Get("plainAccs:transitions:1"), changes))
+		assert.Equal(t, 2, len(changes.List))
+		assert.Equal(t, plainAcc, decode(changes.List[0].Transition))
+		assert.Nil(t, changes.List[1].Transition)
+
+		return nil
+	}))
+
+	assert.NoError(t, db.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("plainAccs:map:"+string(publicKey), append(data, 0))
+		assert.Error(t, migratePlainAccountsVersion(tx, func(done, total uint64) {}), "extra bytes should fail")
+		return nil
+	}))
+}
@@ -0,0 +1,426 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"os"
+	"pandora-pay/config"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store"
+	"pandora-pay/store/min_max_heap"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+)
+
+const SNAPSHOT_VERSION = uint64(0)
+
+// Snapshot is a dump of the DataStorage hashmaps at a given height. Keys and Values are the raw entries of the StoreBlockchain
+type Snapshot struct {
+	Version uint64   `json:"version" msgpack:"version"`
+	Network uint64   `json:"network" msgpack:"network"`
+	Height  uint64   `json:"height" msgpack:"height"`
+	Hash    []byte   `json:"hash" msgpack:"hash"`
+	Keys    [][]byte `json:"keys" msgpack:"keys"`
+	Values  [][]byte `json:"values" msgpack:"values"`
+}
+
+// ComputeHash returns the content hash. Keys must be sorted
+func (snapshot *Snapshot) ComputeHash() []byte {
+	w := advanced_buffers.NewBufferWriter()
+	w.WriteUvarint(snapshot.Version)
+	w.WriteUvarint(snapshot.Network)
+	w.WriteUvarint(snapshot.Height)
+	w.WriteUvarint(uint64(len(snapshot.Keys)))
+	for i := range snapshot.Keys {
+		w.WriteVariableBytes(snapshot.Keys[i])
+		w.WriteVariableBytes(snapshot.Values[i])
+	}
+	return cryptography.SHA3(w.Bytes())
+}
+
+type snapshotExport struct {
+	reader store_db_interface.StoreDBTransactionInterface
+	data   map[string][]byte
+}
+
+func (export *snapshotExport) add(key string) []byte {
+	data := export.reader.Get(key)
+	if data != nil {
+		export.data[key] = data
+	}
+	return data
+}
+
+func (export *snapshotExport) addRequired(key string) ([]byte, error) {
+	data := export.add(key)
+	if data == nil {
+		return nil, errors.New("Snapshot key was not found " + key)
+	}
+	return data, nil
+}
+
+func (export *snapshotExport) readCount(name string) (uint64, error) {
+	data := export.add(name + ":count")
+	if data == nil {
+		return 0, nil
+	}
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, errors.New("Invalid count for " + name)
+	}
+	return count, nil
+}
+
+func (export *snapshotExport) addHashMapElement(name, key string, indexable bool) ([]byte, error) {
+
+	data, err := export.addRequired(name + ":map:" + key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = export.addRequired(name + ":exists:" + key); err != nil {
+		return nil, err
+	}
+
+	if indexable {
+		var index []byte
+		if index, err = export.addRequired(name + ":listKeys:" + key); err != nil {
+			return nil, err
+		}
+		if _, err = export.addRequired(name + ":list:" + string(index)); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// addIndexableHashMap walks the hashmap using the index and returns the keys
+func (export *snapshotExport) addIndexableHashMap(name string) ([]string, error) {
+
+	count, err := export.readCount(name)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, count)
+	for i := uint64(0); i < count; i++ {
+
+		key := export.reader.Get(name + ":list:" + strconv.FormatUint(i, 10))
+		if key == nil {
+			return nil, errors.New("Snapshot index was not found for " + name)
+		}
+
+		keys[i] = string(key)
+		if _, err = export.addHashMapElement(name, keys[i], true); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// addHashMap walks the elements of a hashmap which is not indexable and checks them against its count
+func (export *snapshotExport) addHashMap(name string) error {
+
+	count, err := export.readCount(name)
+	if err != nil {
+		return err
+	}
+
+	prefix := name + ":map:"
+
+	keys := make([]string, 0, count)
+	export.reader.IteratePrefix(prefix, "", func(key string, value []byte) bool {
+		keys = append(keys, key[len(prefix):])
+		return true
+	})
+
+	if uint64(len(keys)) != count {
+		return errors.New("Snapshot count doesn't match for " + name)
+	}
+
+	for _, key := range keys {
+		if _, err = export.addHashMapElement(name, key, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (chain *Blockchain) createSnapshot(reader store_db_interface.StoreDBTransactionInterface) (*Snapshot, error) {
+
+	chainInfoData := reader.Get("blockchainInfo")
+	if chainInfoData == nil {
+		return nil, errors.New("Chain not found")
+	}
+
+	chainData := &BlockchainData{}
+	if err := msgpack.Unmarshal(chainInfoData, chainData); err != nil {
+		return nil, err
+	}
+
+	export := &snapshotExport{reader, make(map[string][]byte)}
+
+	//chain
+	for _, key := range []string{"blockchainInfo", "chainHeight", "chainHash", "chainPrevHash", "chainKernelHash", "chainPrevKernelHash"} {
+		if _, err := export.addRequired(key); err != nil {
+			return nil, err
+		}
+	}
+
+	//latest blocks required for the difficulty and the forks
+	tail := config.FORK_MAX_UNCLE_ALLOWED + config.DIFFICULTY_BLOCK_WINDOW
+	start := uint64(0)
+	if chainData.Height > tail {
+		start = chainData.Height - tail
+	}
+
+	for height := start; height < chainData.Height; height++ {
+
+		heightStr := strconv.FormatUint(height, 10)
+
+		hash, err := export.addRequired("blockHash_ByHeight" + heightStr)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = export.addRequired("blockKernelHash_ByHeight" + heightStr); err != nil {
+			return nil, err
+		}
+		if _, err = export.addRequired("block_ByHash" + string(hash)); err != nil {
+			return nil, err
+		}
+		if _, err = export.addRequired("blockHeight_ByHash" + string(hash)); err != nil {
+			return nil, err
+		}
+		export.add("blockInfo_ByHash" + string(hash))
+
+		heightStr = strconv.FormatUint(height+1, 10)
+		if _, err = export.addRequired("totalDifficulty" + heightStr); err != nil {
+			return nil, err
+		}
+		if _, err = export.addRequired("blockchainInfo_" + heightStr); err != nil {
+			return nil, err
+		}
+	}
+
+	//registrations
+	if _, err := export.addIndexableHashMap("registrations"); err != nil {
+		return nil, err
+	}
+
+	//assets and accounts per asset
+	assetsKeys, err := export.addIndexableHashMap("assets")
+	if err != nil {
+		return nil, err
+	}
+
+	accountsKeys := make(map[string]bool)
+	for _, assetKey := range assetsKeys {
+
+		if ticker := export.add("assets:tickers:by:" + assetKey); ticker != nil {
+			export.add("assets:tickers:used:" + string(ticker))
+		}
+		export.add("assetInfo_ByHash:" + assetKey)
+
+		var keys []string
+		if keys, err = export.addIndexableHashMap("accounts_" + assetKey); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			accountsKeys[key] = true
+		}
+
+		//assets fee liquidity max heap
+		var count uint64
+		if count, err = export.readCount(assetKey); err != nil {
+			return nil, err
+		}
+		if _, err = export.readCount(assetKey + "_dict"); err != nil {
+			return nil, err
+		}
+
+		for i := uint64(0); i < count; i++ {
+
+			var data []byte
+			if data, err = export.addHashMapElement(assetKey, strconv.FormatUint(i, 10), false); err != nil {
+				return nil, err
+			}
+
+			element := &min_max_heap.HeapElement{}
+			if err = element.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+				return nil, err
+			}
+
+			if _, err = export.addHashMapElement(assetKey+"_dict", string(element.Key), false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for key := range accountsKeys {
+
+		data, err := export.addRequired("accounts:assetsCount:" + key)
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := advanced_buffers.NewBufferReader(data).ReadUvarint()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint64(0); i < count; i++ {
+			if _, err = export.addRequired("accounts:assetByIndex:" + key + ":" + strconv.FormatUint(i, 10)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	//plain accounts
+	if err = export.addHashMap("plainAccs"); err != nil {
+		return nil, err
+	}
+
+	//pending stakes
+	if _, err = export.readCount("pendingStakes"); err != nil {
+		return nil, err
+	}
+	for height := chainData.Height; height <= chainData.Height+config_stake.GetPendingStakeWindow(chainData.Height); height++ {
+		if key := strconv.FormatUint(height, 10); reader.Exists("pendingStakes:exists:" + key) {
+			if _, err = export.addHashMapElement("pendingStakes", key, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	//conditional payments by deadline
+	for height := chainData.Height; height < chainData.Height+config.CONDITIONAL_PAYMENT_DEADLINE_MAX; height++ {
+
+		var keys []string
+		if keys, err = export.addIndexableHashMap("conditionalPayments_" + strconv.FormatUint(height, 10)); err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			if _, err = export.addRequired("conditionalPayments:all:" + key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	snapshot := &Snapshot{
+		SNAPSHOT_VERSION,
+		config.NETWORK_SELECTED,
+		chainData.Height,
+		nil,
+		make([][]byte, 0, len(export.data)),
+		make([][]byte, 0, len(export.data)),
+	}
+
+	keys := make([]string, 0, len(export.data))
+	for key := range export.data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		snapshot.Keys = append(snapshot.Keys, []byte(key))
+		snapshot.Values = append(snapshot.Values, export.data[key])
+	}
+
+	snapshot.Hash = snapshot.ComputeHash()
+
+	return snapshot, nil
+}
+
+// ExportSnapshot writes a consistent snapshot of the state at the current height into a file
+func (chain *Blockchain) ExportSnapshot(path string) (snapshot *Snapshot, err error) {
+
+	if config.NODE_CONSENSUS != config.NODE_CONSENSUS_TYPE_FULL {
+		return nil, errors.New("Snapshots require a full node")
+	}
+
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		snapshot, err = chain.createSnapshot(reader)
+		return
+	}); err != nil {
+		return
+	}
+
+	var data []byte
+	if data, err = msgpack.Marshal(snapshot); err != nil {
+		return
+	}
+
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return
+	}
+
+	return
+}
+
+// ImportSnapshot stores the snapshot into an empty chain store. The chain will continue syncing from the snapshot height
+func (chain *Blockchain) ImportSnapshot(path string, hash []byte) error {
+
+	if config.NODE_CONSENSUS != config.NODE_CONSENSUS_TYPE_FULL {
+		return errors.New("Snapshots require a full node")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	snapshot := &Snapshot{}
+	if err = msgpack.Unmarshal(data, snapshot); err != nil {
+		return err
+	}
+
+	if snapshot.Version != SNAPSHOT_VERSION {
+		return errors.New("Snapshot version is not supported")
+	}
+	if snapshot.Network != config.NETWORK_SELECTED {
+		return errors.New("Snapshot network is different")
+	}
+	if len(snapshot.Keys) != len(snapshot.Values) {
+		return errors.New("Snapshot is invalid")
+	}
+	for i := 1; i < len(snapshot.Keys); i++ {
+		if bytes.Compare(snapshot.Keys[i-1], snapshot.Keys[i]) >= 0 {
+			return errors.New("Snapshot keys are not sorted")
+		}
+	}
+
+	if !bytes.Equal(snapshot.ComputeHash(), snapshot.Hash) {
+		return errors.New("Snapshot content doesn't match its hash")
+	}
+	if !bytes.Equal(snapshot.Hash, hash) {
+		return errors.New("Snapshot hash doesn't match the provided hash")
+	}
+
+	gui.GUI.Log("Importing snapshot at height " + strconv.FormatUint(snapshot.Height, 10))
+
+	return store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if writer.Exists("blockchainInfo") {
+			return errors.New("Snapshots can be imported only into an empty chain store")
+		}
+
+		for i, key := range snapshot.Keys {
+			writer.Put(string(key), snapshot.Values[i])
+		}
+
+		heightStr := []byte(strconv.FormatUint(snapshot.Height, 10))
+		//the blocks before the snapshot are missing, the same way as for a pruned node
+		writer.Put("blockchainPrunedHeight", heightStr)
+
+		return
+	})
+}
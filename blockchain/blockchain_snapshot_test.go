@@ -0,0 +1,102 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"math/big"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlockchainSnapshotExportImport(t *testing.T) {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive()
+
+	defer func(storeBlockchain *store.Store) {
+		store.StoreBlockchain = storeBlockchain
+	}(store.StoreBlockchain)
+
+	db, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+	assert.NoError(t, err)
+	store.StoreBlockchain = &store.Store{Name: "/blockchain", Opened: true, DB: db}
+
+	chain := &Blockchain{}
+
+	plainAccsKeys := make([][]byte, 5)
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		chainData := &BlockchainData{Hash: cryptography.SHA3([]byte("Hash")), Target: big.NewInt(1), BigTotalDifficulty: big.NewInt(1)}
+		assert.NoError(t, chainData.saveBlockchain(writer))
+		for _, key := range []string{"chainHeight", "chainHash", "chainPrevHash", "chainKernelHash", "chainPrevKernelHash"} {
+			writer.Put(key, []byte(key))
+		}
+
+		dataStorage := data_storage.NewDataStorage(writer)
+
+		_, err := dataStorage.CreateRegistration(addresses.GenerateNewPrivateKey().GeneratePublicKey(), false, nil)
+		assert.NoError(t, err)
+
+		for i := range plainAccsKeys {
+			plainAccsKeys[i] = addresses.GenerateNewPrivateKey().GeneratePublicKey()
+			plainAcc, err := dataStorage.CreatePlainAccount(plainAccsKeys[i], false)
+			assert.NoError(t, err)
+			plainAcc.Nonce = uint64(i + 1)
+			assert.NoError(t, dataStorage.PlainAccs.Update(string(plainAccsKeys[i]), plainAcc))
+		}
+
+		assert.NoError(t, dataStorage.CommitChanges())
+		return nil
+	}))
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+
+	snapshot, err := chain.ExportSnapshot(path)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.ComputeHash(), snapshot.Hash)
+
+	for _, key := range plainAccsKeys {
+		assert.Contains(t, snapshot.Keys, []byte("plainAccs:map:"+string(key)))
+	}
+
+	//the snapshot is imported only with its hash
+	db2, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+	assert.NoError(t, err)
+	store.StoreBlockchain = &store.Store{Name: "/blockchain", Opened: true, DB: db2}
+
+	assert.Error(t, chain.ImportSnapshot(path, cryptography.SHA3([]byte("other"))), "different hash should fail")
+
+	tampered := *snapshot
+	tampered.Values = append([][]byte{}, snapshot.Values...)
+	tampered.Values[0] = []byte("tampered")
+	data, err := msgpack.Marshal(&tampered)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path+"_tampered", data, 0644))
+	assert.Error(t, chain.ImportSnapshot(path+"_tampered", snapshot.Hash), "tampered content should fail")
+
+	assert.NoError(t, chain.ImportSnapshot(path, snapshot.Hash))
+
+	snapshot2, err := chain.ExportSnapshot(filepath.Join(t.TempDir(), "snapshot2"))
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.Hash, snapshot2.Hash)
+	assert.Equal(t, snapshot.Keys, snapshot2.Keys)
+
+	assert.NoError(t, db2.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		plainAccs := data_storage.NewDataStorage(reader).PlainAccs
+		for i, key := range plainAccsKeys {
+			plainAcc, err := plainAccs.Get(string(key))
+			assert.NoError(t, err)
+			assert.NotNil(t, plainAcc)
+			assert.Equal(t, uint64(i+1), plainAcc.Nonce)
+		}
+		return nil
+	}))
+}
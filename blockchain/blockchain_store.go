@@ -5,10 +5,14 @@ import (
 	"errors"
 	"github.com/vmihailenco/msgpack/v5"
 	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
+	"pandora-pay/config/config_fees"
 	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"strconv"
@@ -73,6 +77,63 @@ func (chain *Blockchain) deleteUnusedBlocksComplete(writer store_db_interface.St
 	return nil
 }
 
+// LoadPrunedHeight returns the height below which the transactions of the blocks were pruned
+func (chain *Blockchain) LoadPrunedHeight(reader store_db_interface.StoreDBTransactionInterface) (uint64, error) {
+	data := reader.Get("blockchainPrunedHeight")
+	if data == nil {
+		return 0, nil
+	}
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+// pruneBlocksComplete deletes the transactions and the transitional changes of the blocks older than config.NODE_PRUNE. The state and the block headers are kept
+func (chain *Blockchain) pruneBlocksComplete(writer store_db_interface.StoreDBTransactionInterface, chainHeight uint64, dataStorage *data_storage.DataStorage) error {
+
+	if config.NODE_PRUNE == 0 || chainHeight <= config.NODE_PRUNE {
+		return nil
+	}
+
+	prunedHeight, err := chain.LoadPrunedHeight(writer)
+	if err != nil {
+		return err
+	}
+
+	end := chainHeight - config.NODE_PRUNE
+	if end > prunedHeight+config.PRUNE_MAX_BLOCKS {
+		end = prunedHeight + config.PRUNE_MAX_BLOCKS
+	}
+
+	for ; prunedHeight < end; prunedHeight++ {
+
+		blockHeightStr := strconv.FormatUint(prunedHeight, 10)
+
+		if err = dataStorage.DeleteTransitionalChangesFromStore(blockHeightStr); err != nil {
+			return err
+		}
+
+		data := writer.Get("blockTxs" + blockHeightStr)
+		if data == nil {
+			continue
+		}
+
+		txHashes := [][]byte{}
+		if err = msgpack.Unmarshal(data, &txHashes); err != nil {
+			return err
+		}
+
+		//txHash: and txBlock: are kept to still reject the included txs
+		for _, txHash := range txHashes {
+			writer.Delete("tx:" + string(txHash))
+		}
+
+		writer.Delete("blockTxs" + blockHeightStr)
+	}
+
+	writer.Put("blockchainPrunedHeight", []byte(strconv.FormatUint(prunedHeight, 10)))
+
+	return nil
+}
+
 func (chain *Blockchain) removeBlockComplete(writer store_db_interface.StoreDBTransactionInterface, blockHeight uint64, removedTxHashes map[string][]byte, allTransactionsChanges []*blockchain_types.BlockchainTransactionUpdate, dataStorage *data_storage.DataStorage) (allTransactionsChanges2 []*blockchain_types.BlockchainTransactionUpdate, err error) {
 
 	allTransactionsChanges2 = allTransactionsChanges
@@ -233,3 +294,85 @@ func (chain *Blockchain) loadBlockchain() error {
 	})
 
 }
+
+// loadStoredBlockComplete loads the stored block and its transactions. The transactions are neither validated nor bloomed
+func (chain *Blockchain) loadStoredBlockComplete(reader store_db_interface.StoreDBTransactionInterface, height uint64) (*block_complete.BlockComplete, []byte, error) {
+
+	hash, err := chain.LoadBlockHash(reader, height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blockData := reader.Get("block_ByHash" + string(hash))
+	if blockData == nil {
+		return nil, nil, errors.New("Block was not found")
+	}
+
+	blk := block.CreateEmptyBlock()
+	if err = blk.Deserialize(advanced_buffers.NewBufferReader(blockData)); err != nil {
+		return nil, nil, err
+	}
+
+	data := reader.Get("blockTxs" + strconv.FormatUint(height, 10))
+	if data == nil {
+		return nil, nil, errors.New("blockTxs was not found")
+	}
+
+	txHashes := [][]byte{}
+	if err = msgpack.Unmarshal(data, &txHashes); err != nil {
+		return nil, nil, err
+	}
+
+	blkComplete := &block_complete.BlockComplete{
+		Block: blk,
+		Txs:   make([]*transaction.Transaction, len(txHashes)),
+	}
+
+	for i, txHash := range txHashes {
+		if data = reader.Get("tx:" + string(txHash)); data == nil {
+			return nil, nil, errors.New("tx was not found")
+		}
+		blkComplete.Txs[i] = &transaction.Transaction{}
+		if err = blkComplete.Txs[i].Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return blkComplete, hash, nil
+}
+
+// loadFeeEstimator seeds the fee estimator of the mempool with the recent blocks, as the estimator is not stored
+func (chain *Blockchain) loadFeeEstimator() error {
+
+	chainHeight := chain.GetChainData().Height
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var start uint64
+		if start, err = chain.LoadPrunedHeight(reader); err != nil {
+			return
+		}
+		if chainHeight > config_fees.FEE_ESTIMATOR_BLOCKS && chainHeight-config_fees.FEE_ESTIMATOR_BLOCKS > start {
+			start = chainHeight - config_fees.FEE_ESTIMATOR_BLOCKS
+		}
+
+		blocks := make([]*block_complete.BlockComplete, 0, config_fees.FEE_ESTIMATOR_BLOCKS)
+		for height := start; height < chainHeight; height++ {
+
+			var blkComplete *block_complete.BlockComplete
+			if blkComplete, _, err = chain.loadStoredBlockComplete(reader, height); err != nil {
+				return
+			}
+			for _, tx := range blkComplete.Txs {
+				if err = tx.BloomAll(); err != nil {
+					return
+				}
+			}
+
+			blocks = append(blocks, blkComplete)
+		}
+
+		chain.mempool.FeeEstimator.AddBlocks(blocks)
+		return
+	})
+}
@@ -0,0 +1,156 @@
+package blockchain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"strconv"
+	"testing"
+)
+
+// saveTestBlocks stores the blocks with a transaction each. Every block increments the nonce of the plain account
+func saveTestBlocks(t *testing.T, chain *Blockchain, writer store_db_interface.StoreDBTransactionInterface, dataStorage *data_storage.DataStorage, publicKey []byte, count uint64) [][]byte {
+
+	txHashes := make([][]byte, count)
+
+	for height := uint64(0); height < count; height++ {
+
+		plainAcc, err := dataStorage.GetOrCreatePlainAccount(publicKey, false)
+		assert.NoError(t, err)
+		assert.NoError(t, plainAcc.IncrementNonce(true))
+		assert.NoError(t, dataStorage.PlainAccs.Update(string(publicKey), plainAcc))
+
+		blk := &block.Block{
+			BlockHeader:    &block.BlockHeader{Version: 0, Height: height},
+			MerkleHash:     cryptography.SHA3([]byte("MerkleHash")),
+			PrevHash:       cryptography.SHA3([]byte("PrevHash")),
+			PrevKernelHash: cryptography.SHA3([]byte("PrevKernelHash")),
+		}
+		blk.Bloom = &block.BlockBloom{
+			Hash:       cryptography.SHA3([]byte("Block" + strconv.FormatUint(height, 10))),
+			KernelHash: cryptography.SHA3([]byte("Kernel" + strconv.FormatUint(height, 10))),
+		}
+
+		txHashes[height] = cryptography.SHA3([]byte("Tx" + strconv.FormatUint(height, 10)))
+		tx := &transaction.Transaction{Bloom: &transaction.TransactionBloom{Serialized: []byte{1, 2, 3}, Hash: txHashes[height], HashStr: string(txHashes[height])}}
+
+		_, err = chain.saveBlockComplete(writer, &block_complete.BlockComplete{Block: blk, Txs: []*transaction.Transaction{tx}}, height, map[string][]byte{}, []*blockchain_types.BlockchainTransactionUpdate{}, dataStorage)
+		assert.NoError(t, err)
+	}
+
+	return txHashes
+}
+
+func TestBlockchainPruneBlocksComplete(t *testing.T) {
+
+	defer func(prune uint64) {
+		config.NODE_PRUNE = prune
+	}(config.NODE_PRUNE)
+	config.NODE_PRUNE = 3
+
+	db, err := store_db_memory.CreateStoreDBMemory("test")
+	assert.NoError(t, err)
+
+	chain := &Blockchain{}
+	publicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+
+	var txHashes [][]byte
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		dataStorage := data_storage.NewDataStorage(writer)
+		txHashes = saveTestBlocks(t, chain, writer, dataStorage, publicKey, 10)
+
+		assert.NoError(t, chain.pruneBlocksComplete(writer, 10, dataStorage))
+		//pruning again doesn't change anything
+		assert.NoError(t, chain.pruneBlocksComplete(writer, 10, dataStorage))
+		return nil
+	}))
+
+	assert.NoError(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		prunedHeight, err := chain.LoadPrunedHeight(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(7), prunedHeight)
+
+		for height := uint64(0); height < 10; height++ {
+
+			heightStr := strconv.FormatUint(height, 10)
+			txHashStr := string(txHashes[height])
+
+			//the block headers are kept
+			hash, err := chain.LoadBlockHash(reader, height)
+			assert.NoError(t, err)
+			assert.True(t, reader.Exists("block_ByHash"+string(hash)))
+			assert.True(t, reader.Exists("blockKernelHash_ByHeight"+heightStr))
+
+			//the included txs are still rejected
+			assert.True(t, reader.Exists("txHash:"+txHashStr))
+			assert.True(t, reader.Exists("txBlock:"+txHashStr))
+
+			if height < prunedHeight {
+				_, _, err = chain.loadStoredBlockComplete(reader, height)
+				assert.Error(t, err, "pruned block complete should fail")
+				assert.Nil(t, reader.Get("tx:"+txHashStr))
+				assert.False(t, reader.Exists("plainAccs:transitions:"+heightStr))
+			} else {
+				assert.True(t, reader.Exists("blockTxs"+heightStr))
+				assert.Equal(t, []byte{1, 2, 3}, reader.Get("tx:"+txHashStr))
+				assert.True(t, reader.Exists("plainAccs:transitions:"+heightStr))
+			}
+		}
+
+		//the state is kept
+		plainAcc, err := data_storage.NewDataStorage(reader).PlainAccs.Get(string(publicKey))
+		assert.NoError(t, err)
+		assert.NotNil(t, plainAcc)
+		assert.Equal(t, uint64(10), plainAcc.Nonce)
+
+		return nil
+	}))
+}
+
+func TestBlockchainPruneBlocksCompleteMaxBlocks(t *testing.T) {
+
+	defer func(prune uint64) {
+		config.NODE_PRUNE = prune
+	}(config.NODE_PRUNE)
+	config.NODE_PRUNE = 3
+
+	db, err := store_db_memory.CreateStoreDBMemory("test")
+	assert.NoError(t, err)
+
+	chain := &Blockchain{}
+	count := config.PRUNE_MAX_BLOCKS + 10
+
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		dataStorage := data_storage.NewDataStorage(writer)
+		saveTestBlocks(t, chain, writer, dataStorage, addresses.GenerateNewPrivateKey().GeneratePublicKey(), count)
+
+		//nothing is pruned while the chain is not longer than NODE_PRUNE
+		assert.NoError(t, chain.pruneBlocksComplete(writer, config.NODE_PRUNE, dataStorage))
+		prunedHeight, err := chain.LoadPrunedHeight(writer)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), prunedHeight)
+
+		assert.NoError(t, chain.pruneBlocksComplete(writer, count, dataStorage))
+		prunedHeight, err = chain.LoadPrunedHeight(writer)
+		assert.NoError(t, err)
+		assert.Equal(t, config.PRUNE_MAX_BLOCKS, prunedHeight)
+
+		assert.NoError(t, chain.pruneBlocksComplete(writer, count, dataStorage))
+		prunedHeight, err = chain.LoadPrunedHeight(writer)
+		assert.NoError(t, err)
+		assert.Equal(t, count-config.NODE_PRUNE, prunedHeight)
+
+		return nil
+	}))
+}
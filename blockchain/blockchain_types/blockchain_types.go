@@ -35,12 +35,14 @@ type MempoolTransactionUpdate struct {
 }
 
 type BlockchainUpdates struct {
-	AccsCollection *accounts.AccountsCollection
-	PlainAccounts  *plain_accounts.PlainAccounts
-	Assets         *assets.Assets
-	Registrations  *registrations.Registrations
-	BlockHeight    uint64
-	BlockHash      []byte
+	AccsCollection  *accounts.AccountsCollection
+	PlainAccounts   *plain_accounts.PlainAccounts
+	Assets          *assets.Assets
+	Registrations   *registrations.Registrations
+	BlockHeight     uint64
+	BlockHash       []byte
+	InsertedBlocks  []*block_complete.BlockComplete
+	RemovedTxHashes map[string][]byte
 }
 
 type BlockchainSolutionAnswer struct {
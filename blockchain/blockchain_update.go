@@ -83,6 +83,8 @@ func (queue *BlockchainUpdatesQueue) executeUpdate(update *BlockchainUpdate) (er
 		update.dataStorage.Regs,
 		update.newChainData.Height,
 		update.newChainData.Hash,
+		update.insertedBlocks,
+		update.removedTxHashes,
 	})
 
 	chainSyncData := queue.chain.Sync.AddBlocksChanged(uint32(len(update.insertedBlocks)), true)
@@ -36,6 +36,8 @@ func (queue *BlockchainUpdatesQueue) processBlockchainUpdateMempool() {
 
 			update := <-updatesMempoolCn
 
+			queue.chain.mempool.FeeEstimator.AddBlocks(update.insertedBlocks)
+
 			//let's remove the transactions from the mempool
 			if len(update.insertedTxsList) > 0 {
 				hashes := make([]string, len(update.insertedTxsList))
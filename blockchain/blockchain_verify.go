@@ -0,0 +1,307 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/txs_validator"
+	"strconv"
+	"strings"
+)
+
+// ChainVerification is the result of replaying the stored blocks from genesis into a fresh in memory state
+type ChainVerification struct {
+	Height         uint64 `json:"height" msgpack:"height"` //blocks replayed
+	Keys           uint64 `json:"keys" msgpack:"keys"`     //state entries compared with the chain store
+	Diverged       bool   `json:"diverged" msgpack:"diverged"`
+	DivergedHeight uint64 `json:"divergedHeight" msgpack:"divergedHeight"`
+	Reason         string `json:"reason" msgpack:"reason"`
+}
+
+func (verification *ChainVerification) diverged(height uint64, reason string) {
+	if !verification.Diverged || height < verification.DivergedHeight {
+		verification.Diverged = true
+		verification.DivergedHeight = height
+		verification.Reason = reason
+	}
+}
+
+// chainVerifyWriter records the height at which every state entry was written for the last time by the replay
+type chainVerifyWriter struct {
+	store_db_interface.StoreDBTransactionInterface
+	height  uint64
+	touched map[string]uint64
+}
+
+func (writer *chainVerifyWriter) Put(key string, value []byte) {
+	writer.touched[key] = writer.height
+	writer.StoreDBTransactionInterface.Put(key, value)
+}
+
+func (writer *chainVerifyWriter) Delete(key string) {
+	writer.touched[key] = writer.height
+	writer.StoreDBTransactionInterface.Delete(key)
+}
+
+func (chain *Blockchain) loadBlockComplete(reader store_db_interface.StoreDBTransactionInterface, height uint64) (*block_complete.BlockComplete, error) {
+
+	blkComplete, hash, err := chain.loadStoredBlockComplete(reader, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = txs_validator.TxsValidator.ValidateTxs(blkComplete.Txs); err != nil {
+		return nil, err
+	}
+	if err = blkComplete.BloomAll(); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(blkComplete.Block.Bloom.Hash, hash) {
+		return nil, errors.New("Stored block hash doesn't match")
+	}
+
+	return blkComplete, nil
+}
+
+// replayBlock includes the block into the replayed state and returns the new supply
+func (chain *Blockchain) replayBlock(writer store_db_interface.StoreDBTransactionInterface, blkComplete *block_complete.BlockComplete) (supply uint64, err error) {
+
+	defer func() {
+		if errReturned := recover(); errReturned != nil {
+			err = fmt.Errorf("%v", errReturned)
+		}
+	}()
+
+	dataStorage := data_storage.NewDataStorage(writer)
+
+	var reward uint64
+	if reward, _, err = blockchain_types.ComputeBlockReward(blkComplete.Height, blkComplete.Txs); err != nil {
+		return
+	}
+	if supply, err = addBlockReward(dataStorage, reward); err != nil {
+		return
+	}
+	if err = includeBlockComplete(blkComplete, dataStorage); err != nil {
+		return
+	}
+
+	err = dataStorage.CommitChanges()
+	return
+}
+
+// replayChunk replays the stored blocks from the height of the verification until end in a single read transaction.
+// It returns false when the replay diverged
+func (chain *Blockchain) replayChunk(memory *store_db_memory.StoreDBMemory, verification *ChainVerification, touched map[string]uint64, lastHash []byte, end uint64) (hash []byte, ok bool, err error) {
+
+	hash = lastHash
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		//the replay error is kept separately as the memory store doesn't return the errors of the callback
+		var replayErr error
+		var supply uint64
+
+		for height := verification.Height; height < end; height++ {
+
+			var blkComplete *block_complete.BlockComplete
+			if blkComplete, err = chain.loadBlockComplete(reader, height); err != nil {
+				return fmt.Errorf("Block %d couldn't be loaded: %s", height, err.Error())
+			}
+			if height > 0 && !bytes.Equal(blkComplete.Block.PrevHash, hash) {
+				return errors.New("Chain was changed by a fork during the verification. Try again")
+			}
+
+			if err = memory.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+				supply, replayErr = chain.replayBlock(&chainVerifyWriter{writer, height, touched}, blkComplete)
+				return replayErr
+			}); err != nil {
+				return
+			}
+
+			verification.Height = height + 1
+			hash = blkComplete.Block.Bloom.Hash
+
+			if replayErr != nil {
+				verification.diverged(height, "Block can not be replayed: "+replayErr.Error())
+				return
+			}
+
+			info := &BlockchainData{}
+			if err = info.loadBlockchainInfo(reader, height+1); err != nil {
+				return
+			}
+			if info.Supply != supply {
+				verification.diverged(height, fmt.Sprintf("Supply %d is different than the replayed supply %d", info.Supply, supply))
+				return
+			}
+		}
+
+		return
+	})
+
+	return hash, err == nil && !verification.Diverged, err
+}
+
+// replayToChainHeight replays the stored blocks in chunks until it reaches the current height of the chain
+func (chain *Blockchain) replayToChainHeight(memory *store_db_memory.StoreDBMemory, verification *ChainVerification, touched map[string]uint64, hash []byte) ([]byte, bool, error) {
+
+	for {
+
+		chainHeight := chain.GetChainData().Height
+		if verification.Height >= chainHeight {
+			return hash, true, nil
+		}
+
+		end := verification.Height + config.VERIFY_CHAIN_CHUNK_BLOCKS
+		if end > chainHeight {
+			end = chainHeight
+		}
+
+		var ok bool
+		var err error
+		if hash, ok, err = chain.replayChunk(memory, verification, touched, hash, end); err != nil || !ok {
+			return hash, ok, err
+		}
+
+		gui.GUI.Info2Update("Verify", strconv.FormatUint(verification.Height, 10)+" / "+strconv.FormatUint(chainHeight, 10))
+	}
+}
+
+// compareState compares the key sets of the replayed state with the chain store. The keys are grouped by the prefix up to their last separator,
+// so the entries which exist only in the chain store are found as well. The transitions are not compared as their encoding is not deterministic
+func (chain *Blockchain) compareState(memory *store_db_memory.StoreDBMemory, verification *ChainVerification, touched map[string]uint64) error {
+
+	lastHeight := verification.Height - 1
+
+	prefixes := make(map[string][]string)
+	singles := []string{}
+	for key := range touched {
+		if index := strings.LastIndexByte(key, ':'); index > 0 && strings.IndexByte(key, ':') < index {
+			prefixes[key[:index+1]] = nil
+		} else {
+			singles = append(singles, key)
+		}
+	}
+
+	compare := func(reader, memoryReader store_db_interface.StoreDBTransactionInterface, key string) {
+		verification.Keys += 1
+		if !bytes.Equal(memoryReader.Get(key), reader.Get(key)) {
+			if height, ok := touched[key]; ok {
+				verification.diverged(height, "State entry is different "+strconv.QuoteToASCII(key))
+			} else {
+				verification.diverged(lastHeight, "State entry is missing in the replay "+strconv.QuoteToASCII(key))
+			}
+		}
+	}
+
+	//every prefix is compared in its own read transaction
+	for prefix := range prefixes {
+		if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+			return memory.View(func(memoryReader store_db_interface.StoreDBTransactionInterface) error {
+
+				keys := make(map[string]bool)
+				reader.IteratePrefix(prefix, "", func(key string, value []byte) bool {
+					keys[key] = true
+					return true
+				})
+				memoryReader.IteratePrefix(prefix, "", func(key string, value []byte) bool {
+					keys[key] = true
+					return true
+				})
+
+				for key := range keys {
+					compare(reader, memoryReader, key)
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		return memory.View(func(memoryReader store_db_interface.StoreDBTransactionInterface) error {
+			for _, key := range singles {
+				compare(reader, memoryReader, key)
+			}
+			return nil
+		})
+	})
+}
+
+// VerifyChain replays every stored block from genesis into an in memory store and compares the resulting state with the chain store.
+// The blocks are replayed in chunks, each in its own read transaction, and the replay stops at the first block which diverged.
+// The chain store only keeps the latest state, so a diverging entry is reported at the height it was written for the last time by the replay.
+// The new blocks are blocked only while the last blocks are replayed and the state is compared
+func (chain *Blockchain) VerifyChain() (*ChainVerification, error) {
+
+	if config.NODE_CONSENSUS != config.NODE_CONSENSUS_TYPE_FULL {
+		return nil, errors.New("Chain verification requires a full node")
+	}
+
+	memory, err := store_db_memory.CreateStoreDBMemory("verify")
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &ChainVerification{}
+	touched := make(map[string]uint64)
+
+	var replayErr error
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		var prunedHeight uint64
+		if prunedHeight, err = chain.LoadPrunedHeight(reader); err != nil {
+			return
+		}
+		if prunedHeight > 0 {
+			return fmt.Errorf("Chain can not be verified as the blocks before %d are missing", prunedHeight)
+		}
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = memory.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		replayErr = chain.initializeNewChain(chain.createGenesisBlockchainData(), data_storage.NewDataStorage(&chainVerifyWriter{writer, 0, touched}))
+		return replayErr
+	}); err != nil {
+		return nil, err
+	}
+	if replayErr != nil {
+		return nil, replayErr
+	}
+
+	hash, ok, err := chain.replayToChainHeight(memory, verification, touched, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+
+		//the blocks added meanwhile are replayed while the chain is not changed
+		chain.mutex.Lock()
+		defer chain.mutex.Unlock()
+
+		if _, ok, err = chain.replayToChainHeight(memory, verification, touched, hash); err != nil {
+			return nil, err
+		}
+
+		if ok && verification.Height > 0 {
+			if err = chain.compareState(memory, verification, touched); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	gui.GUI.Info2Update("Verify", "Done")
+
+	return verification, nil
+}
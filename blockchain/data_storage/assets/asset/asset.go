@@ -15,6 +15,11 @@ import (
 	"strings"
 )
 
+const (
+	ASSET_VERSION_SIMPLE uint64 = 0
+	ASSET_VERSION_STATUS uint64 = 1 //the paused and frozen status are serialized
+)
+
 var regexAssetName = regexp.MustCompile("^([a-zA-Z0-9]+ )+[a-zA-Z0-9]+$|^[a-zA-Z0-9]+")
 var regexAssetTicker = regexp.MustCompile("^[A-Z0-9]+$") // only lowercase ascii is allowed. No space allowed
 var regexAssetDescription = regexp.MustCompile("[\\w|\\W]+")
@@ -30,6 +35,8 @@ type Asset struct {
 	CanChangeSupplyPublicKey bool   `json:"canChangeSupplyPublicKey,omitempty" msgpack:"canChangeSupplyPublicKey,omitempty"` //can change supply key
 	CanPause                 bool   `json:"canPause,omitempty" msgpack:"canPause,omitempty"`                                 //can pause (suspend transactions)
 	CanFreeze                bool   `json:"canFreeze,omitempty" msgpack:"canFreeze,omitempty"`                               //freeze supply changes
+	Paused                   bool   `json:"paused,omitempty" msgpack:"paused,omitempty"`                                     //transactions are suspended
+	Frozen                   bool   `json:"frozen,omitempty" msgpack:"frozen,omitempty"`                                     //supply can not be changed anymore
 	DecimalSeparator         byte   `json:"decimalSeparator,omitempty" msgpack:"decimalSeparator,omitempty"`
 	MaxSupply                uint64 `json:"maxSupply,omitempty" msgpack:"maxSupply,omitempty"`
 	Supply                   uint64 `json:"supply,omitempty" msgpack:"supply,omitempty"`
@@ -62,6 +69,12 @@ func (asset *Asset) GetIndex() uint64 {
 }
 
 func (asset *Asset) Validate() error {
+	if asset.Version > ASSET_VERSION_STATUS {
+		return errors.New("asset version is invalid")
+	}
+	if asset.Version == ASSET_VERSION_SIMPLE && (asset.Paused || asset.Frozen) {
+		return errors.New("asset status requires the status version")
+	}
 	if asset.DecimalSeparator > config_assets.ASSETS_DECIMAL_SEPARATOR_MAX_BYTE {
 		return errors.New("asset decimal separator is invalid")
 	}
@@ -153,6 +166,10 @@ func (asset *Asset) AddSupply(sign bool, amount uint64) error {
 		return errors.New("BURN PUBLIC KEY")
 	}
 
+	if asset.Frozen {
+		return errors.New("Asset supply is frozen")
+	}
+
 	if sign {
 		if !asset.CanMint {
 			return errors.New("Can't mint")
@@ -172,6 +189,43 @@ func (asset *Asset) AddSupply(sign bool, amount uint64) error {
 	return helpers.SafeUint64Sub(&asset.Supply, amount)
 }
 
+// setStatusVersion upgrades the asset to the version which serializes the paused and frozen status
+func (asset *Asset) setStatusVersion() error {
+	if asset.Version > ASSET_VERSION_STATUS {
+		return errors.New("Asset version doesn't support the status")
+	}
+	asset.Version = ASSET_VERSION_STATUS
+	return nil
+}
+
+func (asset *Asset) SetPaused(paused bool) error {
+	if !asset.CanPause {
+		return errors.New("Can't pause")
+	}
+	if asset.Paused == paused {
+		return errors.New("Asset pause status is not changed")
+	}
+	if err := asset.setStatusVersion(); err != nil {
+		return err
+	}
+	asset.Paused = paused
+	return nil
+}
+
+func (asset *Asset) Freeze() error {
+	if !asset.CanFreeze {
+		return errors.New("Can't freeze")
+	}
+	if asset.Frozen {
+		return errors.New("Asset is already frozen")
+	}
+	if err := asset.setStatusVersion(); err != nil {
+		return err
+	}
+	asset.Frozen = true
+	return nil
+}
+
 func (asset *Asset) Serialize(w *advanced_buffers.BufferWriter) {
 
 	w.WriteUvarint(asset.Version)
@@ -183,6 +237,10 @@ func (asset *Asset) Serialize(w *advanced_buffers.BufferWriter) {
 	w.WriteBool(asset.CanChangeSupplyPublicKey)
 	w.WriteBool(asset.CanPause)
 	w.WriteBool(asset.CanFreeze)
+	if asset.Version == ASSET_VERSION_STATUS {
+		w.WriteBool(asset.Paused)
+		w.WriteBool(asset.Frozen)
+	}
 	w.WriteByte(asset.DecimalSeparator)
 
 	w.WriteUvarint(asset.MaxSupply)
@@ -231,6 +289,14 @@ func (asset *Asset) Deserialize(r *advanced_buffers.BufferReader) (err error) {
 	if asset.CanFreeze, err = r.ReadBool(); err != nil {
 		return
 	}
+	if asset.Version == ASSET_VERSION_STATUS {
+		if asset.Paused, err = r.ReadBool(); err != nil {
+			return
+		}
+		if asset.Frozen, err = r.ReadBool(); err != nil {
+			return
+		}
+	}
 	if asset.DecimalSeparator, err = r.ReadByte(); err != nil {
 		return
 	}
@@ -0,0 +1,81 @@
+package asset
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+	"testing"
+)
+
+func createTestAsset() *Asset {
+	ast := &Asset{
+		CanMint:         true,
+		CanBurn:         true,
+		CanPause:        true,
+		CanFreeze:       true,
+		MaxSupply:       1000,
+		Supply:          500,
+		UpdatePublicKey: addresses.GenerateNewPrivateKey().GeneratePublicKey(),
+		SupplyPublicKey: addresses.GenerateNewPrivateKey().GeneratePublicKey(),
+		Name:            "Test",
+		Ticker:          "TEST",
+		Description:     "Test asset",
+		Data:            []byte("data"),
+	}
+	ast.SetKey(cryptography.RIPEMD([]byte("AssetId")))
+	return ast
+}
+
+func testAssetSerialization(t *testing.T, ast *Asset) {
+
+	buf := serializeTestAsset(ast)
+
+	ast2 := NewAsset(ast.PublicKeyHash, 0)
+	assert.NoError(t, ast2.Deserialize(advanced_buffers.NewBufferReader(buf)))
+	assert.Equal(t, ast, ast2)
+	assert.NoError(t, ast2.Validate())
+}
+
+func serializeTestAsset(ast *Asset) []byte {
+	w := advanced_buffers.NewBufferWriter()
+	ast.Serialize(w)
+	return w.Bytes()
+}
+
+func TestAsset_Serialize(t *testing.T) {
+
+	ast := createTestAsset()
+	testAssetSerialization(t, ast)
+	simple := serializeTestAsset(ast)
+
+	assert.NoError(t, ast.SetPaused(true))
+	assert.NoError(t, ast.Freeze())
+	assert.Equal(t, ASSET_VERSION_STATUS, ast.Version)
+	testAssetSerialization(t, ast)
+	assert.Equal(t, len(simple)+2, len(serializeTestAsset(ast)))
+
+	ast.Version = ASSET_VERSION_SIMPLE
+	assert.Error(t, ast.Validate(), "status without the status version should fail")
+}
+
+func TestAsset_Status(t *testing.T) {
+
+	ast := createTestAsset()
+
+	assert.Error(t, ast.SetPaused(false), "unchanged status should fail")
+	assert.NoError(t, ast.AddSupply(true, 10))
+
+	assert.NoError(t, ast.Freeze())
+	assert.Error(t, ast.Freeze(), "freezing twice should fail")
+	assert.Error(t, ast.AddSupply(true, 10), "minting a frozen asset should fail")
+	assert.Error(t, ast.AddSupply(false, 10), "burning a frozen asset should fail")
+	assert.Equal(t, uint64(510), ast.Supply)
+
+	ast = createTestAsset()
+	ast.CanPause = false
+	ast.CanFreeze = false
+	assert.Error(t, ast.SetPaused(true), "pausing without CanPause should fail")
+	assert.Error(t, ast.Freeze(), "freezing without CanFreeze should fail")
+	assert.Equal(t, ASSET_VERSION_SIMPLE, ast.Version)
+}
@@ -1,21 +1,30 @@
 package plain_account
 
 import (
+	"errors"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/plain_account_multisig"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 )
 
+const (
+	PLAIN_ACCOUNT_VERSION_SIMPLE   uint64 = 0
+	PLAIN_ACCOUNT_VERSION_MULTISIG uint64 = 1 //the multisig is serialized
+)
+
 type PlainAccount struct {
-	Key                 []byte                                   `json:"-" msgpack:"-"` //hashMap key
-	Index               uint64                                   `json:"-" msgpack:"-"` //hashMap index
-	Nonce               uint64                                   `json:"nonce" msgpack:"nonce"`
-	Unclaimed           uint64                                   `json:"unclaimed" msgpack:"unclaimed"`
-	AssetFeeLiquidities *asset_fee_liquidity.AssetFeeLiquidities `json:"assetFeeLiquidities" msgpack:"assetFeeLiquidities"`
+	Key                 []byte                                       `json:"-" msgpack:"-"` //hashMap key
+	Index               uint64                                       `json:"-" msgpack:"-"` //hashMap index
+	Version             uint64                                       `json:"version,omitempty" msgpack:"version,omitempty"`
+	Nonce               uint64                                       `json:"nonce" msgpack:"nonce"`
+	Unclaimed           uint64                                       `json:"unclaimed" msgpack:"unclaimed"`
+	AssetFeeLiquidities *asset_fee_liquidity.AssetFeeLiquidities     `json:"assetFeeLiquidities" msgpack:"assetFeeLiquidities"`
+	Multisig            *plain_account_multisig.PlainAccountMultisig `json:"multisig" msgpack:"multisig"`
 }
 
 func (plainAccount *PlainAccount) IsDeletable() bool {
-	if plainAccount.Unclaimed == 0 && plainAccount.Nonce == 0 && !plainAccount.AssetFeeLiquidities.HasAssetFeeLiquidities() {
+	if plainAccount.Unclaimed == 0 && plainAccount.Nonce == 0 && !plainAccount.AssetFeeLiquidities.HasAssetFeeLiquidities() && !plainAccount.Multisig.HasMultisig() {
 		return true
 	}
 	return false
@@ -34,9 +43,18 @@ func (plainAccount *PlainAccount) GetIndex() uint64 {
 }
 
 func (plainAccount *PlainAccount) Validate() error {
+	if plainAccount.Version > PLAIN_ACCOUNT_VERSION_MULTISIG {
+		return errors.New("Plain Account version is invalid")
+	}
+	if plainAccount.Version == PLAIN_ACCOUNT_VERSION_SIMPLE && plainAccount.Multisig.HasMultisig() {
+		return errors.New("Plain Account multisig requires the multisig version")
+	}
 	if err := plainAccount.AssetFeeLiquidities.Validate(); err != nil {
 		return err
 	}
+	if err := plainAccount.Multisig.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -48,14 +66,31 @@ func (plainAccount *PlainAccount) AddUnclaimed(sign bool, amount uint64) error {
 	return helpers.SafeUint64Update(sign, &plainAccount.Unclaimed, amount)
 }
 
+// SetMultisig upgrades the plain account to the version which serializes the multisig. A zero threshold removes the multisig
+func (plainAccount *PlainAccount) SetMultisig(threshold byte, publicKeys [][]byte) error {
+	if plainAccount.Version > PLAIN_ACCOUNT_VERSION_MULTISIG {
+		return errors.New("Plain Account version doesn't support the multisig")
+	}
+	plainAccount.Version = PLAIN_ACCOUNT_VERSION_MULTISIG
+	plainAccount.Multisig.Set(threshold, publicKeys)
+	return nil
+}
+
 func (plainAccount *PlainAccount) Serialize(w *advanced_buffers.BufferWriter) {
+	w.WriteUvarint(plainAccount.Version)
 	w.WriteUvarint(plainAccount.Nonce)
 	w.WriteUvarint(plainAccount.Unclaimed)
 	plainAccount.AssetFeeLiquidities.Serialize(w)
+	if plainAccount.Version == PLAIN_ACCOUNT_VERSION_MULTISIG {
+		plainAccount.Multisig.Serialize(w)
+	}
 }
 
 func (plainAccount *PlainAccount) Deserialize(r *advanced_buffers.BufferReader) (err error) {
 
+	if plainAccount.Version, err = r.ReadUvarint(); err != nil {
+		return
+	}
 	if plainAccount.Nonce, err = r.ReadUvarint(); err != nil {
 		return
 	}
@@ -65,6 +100,11 @@ func (plainAccount *PlainAccount) Deserialize(r *advanced_buffers.BufferReader)
 	if err = plainAccount.AssetFeeLiquidities.Deserialize(r); err != nil {
 		return
 	}
+	if plainAccount.Version == PLAIN_ACCOUNT_VERSION_MULTISIG {
+		if err = plainAccount.Multisig.Deserialize(r); err != nil {
+			return
+		}
+	}
 
 	return
 }
@@ -74,5 +114,6 @@ func NewPlainAccount(key []byte, index uint64) *PlainAccount {
 		Key:                 key,
 		Index:               index,
 		AssetFeeLiquidities: &asset_fee_liquidity.AssetFeeLiquidities{},
+		Multisig:            &plain_account_multisig.PlainAccountMultisig{},
 	}
 }
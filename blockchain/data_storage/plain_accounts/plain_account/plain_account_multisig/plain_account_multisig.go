@@ -0,0 +1,150 @@
+package plain_account_multisig
+
+import (
+	"errors"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+type PlainAccountMultisig struct {
+	Version    PlainAccountMultisigVersion `json:"version" msgpack:"version"`
+	Threshold  byte                        `json:"threshold" msgpack:"threshold"`
+	PublicKeys [][]byte                    `json:"publicKeys" msgpack:"publicKeys"`
+}
+
+func (self *PlainAccountMultisig) HasMultisig() bool {
+	return self.Version == SIMPLE
+}
+
+func (self *PlainAccountMultisig) Clear() {
+	self.Version = NONE
+	self.Threshold = 0
+	self.PublicKeys = nil
+}
+
+func (self *PlainAccountMultisig) Set(threshold byte, publicKeys [][]byte) {
+	if threshold == 0 {
+		self.Clear()
+		return
+	}
+	self.Version = SIMPLE
+	self.Threshold = threshold
+	self.PublicKeys = publicKeys
+}
+
+// VerifySigners checks that the signers are part of the policy and there are enough of them
+func (self *PlainAccountMultisig) VerifySigners(signers [][]byte) error {
+
+	if int(self.Threshold) > len(signers) {
+		return errors.New("Threshold not met")
+	}
+
+	unique := make(map[string]bool)
+	for i := range self.PublicKeys {
+		unique[string(self.PublicKeys[i])] = true
+	}
+
+	for i := range signers {
+		if !unique[string(signers[i])] {
+			return errors.New("Invalid multisig public key")
+		}
+	}
+
+	return nil
+}
+
+func Validate(threshold byte, publicKeys [][]byte) error {
+
+	if threshold == 0 {
+		if len(publicKeys) != 0 {
+			return errors.New("Public Keys can not be set when there is no threshold")
+		}
+		return nil
+	}
+
+	if len(publicKeys) == 0 || len(publicKeys) > config.TRANSACTIONS_MULTISIG_MAX {
+		return errors.New("Invalid number of Public Keys")
+	}
+	if int(threshold) > len(publicKeys) {
+		return errors.New("Threshold is greater than the number of Public Keys")
+	}
+
+	unique := make(map[string]bool)
+	for i := range publicKeys {
+		if len(publicKeys[i]) != cryptography.PublicKeySize {
+			return errors.New("Public Key length is invalid")
+		}
+		unique[string(publicKeys[i])] = true
+	}
+	if len(unique) != len(publicKeys) {
+		return errors.New("public Keys contain duplicates")
+	}
+
+	return nil
+}
+
+func (self *PlainAccountMultisig) Validate() error {
+	switch self.Version {
+	case NONE:
+		if self.Threshold != 0 || len(self.PublicKeys) != 0 {
+			return errors.New("Multisig can not have keys while it is not set")
+		}
+	case SIMPLE:
+		if self.Threshold == 0 {
+			return errors.New("Threshold should be positive")
+		}
+		return Validate(self.Threshold, self.PublicKeys)
+	default:
+		return errors.New("Invalid Version")
+	}
+	return nil
+}
+
+func (self *PlainAccountMultisig) Serialize(w *advanced_buffers.BufferWriter) {
+
+	w.WriteUvarint(uint64(self.Version))
+
+	switch self.Version {
+	case SIMPLE:
+		w.WriteByte(self.Threshold)
+		w.WriteByte(byte(len(self.PublicKeys)))
+		for _, publicKey := range self.PublicKeys {
+			w.Write(publicKey)
+		}
+	}
+}
+
+func (self *PlainAccountMultisig) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+
+	var n uint64
+	if n, err = r.ReadUvarint(); err != nil {
+		return
+	}
+	self.Version = PlainAccountMultisigVersion(n)
+
+	switch self.Version {
+	case NONE:
+	case SIMPLE:
+		if self.Threshold, err = r.ReadByte(); err != nil {
+			return
+		}
+		var count byte
+		if count, err = r.ReadByte(); err != nil {
+			return
+		}
+		if int(count) > config.TRANSACTIONS_MULTISIG_MAX {
+			return errors.New("Invalid number of Public Keys")
+		}
+		self.PublicKeys = make([][]byte, count)
+		for i := range self.PublicKeys {
+			if self.PublicKeys[i], err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+				return
+			}
+		}
+	default:
+		return errors.New("Invalid Version")
+	}
+
+	return
+}
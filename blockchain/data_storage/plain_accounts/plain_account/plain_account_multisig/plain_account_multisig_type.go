@@ -0,0 +1,19 @@
+package plain_account_multisig
+
+type PlainAccountMultisigVersion uint64
+
+const (
+	NONE PlainAccountMultisigVersion = iota
+	SIMPLE
+)
+
+func (t PlainAccountMultisigVersion) String() string {
+	switch t {
+	case NONE:
+		return "NONE"
+	case SIMPLE:
+		return "SIMPLE"
+	default:
+		return "Unknown PlainAccountMultisigVersion"
+	}
+}
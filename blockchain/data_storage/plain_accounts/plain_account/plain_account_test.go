@@ -0,0 +1,46 @@
+package plain_account
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"testing"
+)
+
+func testPlainAccountSerialization(t *testing.T, plainAcc *PlainAccount) {
+
+	buf := helpers.SerializeToBytes(plainAcc)
+
+	plainAcc2 := NewPlainAccount(plainAcc.Key, 0)
+	r := advanced_buffers.NewBufferReader(buf)
+	assert.NoError(t, plainAcc2.Deserialize(r))
+	assert.Equal(t, len(buf), r.Position)
+	assert.Equal(t, plainAcc, plainAcc2)
+	assert.NoError(t, plainAcc2.Validate())
+}
+
+func TestPlainAccount_Serialize(t *testing.T) {
+
+	plainAcc := NewPlainAccount(addresses.GenerateNewPrivateKey().GeneratePublicKey(), 0)
+	plainAcc.Nonce = 5
+	plainAcc.Unclaimed = 100
+	testPlainAccountSerialization(t, plainAcc)
+	simple := helpers.SerializeToBytes(plainAcc)
+
+	publicKeys := [][]byte{addresses.GenerateNewPrivateKey().GeneratePublicKey(), addresses.GenerateNewPrivateKey().GeneratePublicKey()}
+	assert.NoError(t, plainAcc.SetMultisig(2, publicKeys))
+	assert.Equal(t, PLAIN_ACCOUNT_VERSION_MULTISIG, plainAcc.Version)
+	testPlainAccountSerialization(t, plainAcc)
+
+	//removing the multisig keeps the multisig version
+	assert.NoError(t, plainAcc.SetMultisig(0, nil))
+	assert.False(t, plainAcc.Multisig.HasMultisig())
+	assert.Equal(t, PLAIN_ACCOUNT_VERSION_MULTISIG, plainAcc.Version)
+	testPlainAccountSerialization(t, plainAcc)
+	assert.Equal(t, len(simple)+1, len(helpers.SerializeToBytes(plainAcc)))
+
+	assert.NoError(t, plainAcc.SetMultisig(1, publicKeys))
+	plainAcc.Version = PLAIN_ACCOUNT_VERSION_SIMPLE
+	assert.Error(t, plainAcc.Validate(), "multisig without the multisig version should fail")
+}
@@ -0,0 +1,26 @@
+package forging
+
+import (
+	"pandora-pay/helpers/metrics"
+	"sync/atomic"
+)
+
+func (thread *ForgingThread) initMetrics() {
+
+	metrics.RegisterCounter("pandora_forging_attempts_total", "Kernel hashes computed while forging", func() float64 {
+		return float64(atomic.LoadUint64(&thread.hashesTotal))
+	})
+
+	metrics.RegisterGauge("pandora_forging_hashes_per_second", "Kernel hashes computed in the last second", func() float64 {
+		return float64(atomic.LoadUint64(&thread.hashesPerSecond))
+	})
+
+	metrics.RegisterCounter("pandora_forging_blocks_total", "Blocks forged", func() float64 {
+		return float64(atomic.LoadUint64(&thread.blocksForged))
+	})
+
+	metrics.RegisterCounter("pandora_forging_rejected_total", "Forged solutions rejected by the blockchain", func() float64 {
+		return float64(atomic.LoadUint64(&thread.solutionsRejected))
+	})
+
+}
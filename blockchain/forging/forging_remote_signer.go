@@ -0,0 +1,39 @@
+package forging
+
+import (
+	"context"
+	"math/big"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"strconv"
+)
+
+// ForgingRemoteSigner is the key holder of a delegated stake. The private key never leaves it, the node only asks for the staking data and the staking reward transaction
+type ForgingRemoteSigner interface {
+	GetStakingData(publicKey, prevKernelHash []byte, ctx context.Context) (*ForgingRemoteStakingData, error)
+	CreateForgingTransaction(publicKey []byte, blkComplete *block_complete.BlockComplete, decryptedStakingBalance uint64, ctx context.Context) (*transaction.Transaction, error)
+}
+
+type ForgingRemoteStakingData struct {
+	StakingNonce            []byte
+	DecryptedStakingBalance uint64
+}
+
+type forgingRemoteStakingAnswer struct {
+	publicKeyStr   string
+	prevKernelHash []byte
+	data           *ForgingRemoteStakingData
+}
+
+// ComputeStakingNonce is the staking nonce required for the block built on top of prevKernelHash
+func ComputeStakingNonce(privateKeyPoint *big.Int, prevKernelHash []byte) []byte {
+	uinput := append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), prevKernelHash[:]...)
+	uinput = append(uinput, config_coins.NATIVE_ASSET_FULL...)
+	uinput = append(uinput, strconv.Itoa(0)...)
+	u := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(uinput)), privateKeyPoint)
+	return cryptography.SHA3(u.EncodeCompressed())
+}
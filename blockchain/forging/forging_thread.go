@@ -2,12 +2,14 @@ package forging
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_forging"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/generics"
@@ -29,6 +31,10 @@ type ForgingThread struct {
 	workersDestroyedCn        chan struct{}
 	lastPrevKernelHash        *generics.Value[[]byte]
 	createForgingTransactions func(*block_complete.BlockComplete, []byte, uint64, []*transaction.Transaction) (*transaction.Transaction, error)
+	hashesTotal               uint64 //use atomic
+	hashesPerSecond           uint64 //use atomic
+	blocksForged              uint64 //use atomic
+	solutionsRejected         uint64 //use atomic
 }
 
 func (thread *ForgingThread) stopForging() {
@@ -53,20 +59,22 @@ func (thread *ForgingThread) startForging() {
 		for {
 
 			s := ""
+			total := uint64(0)
 			for i := 0; i < thread.threads; i++ {
 				hashesPerSecond := atomic.SwapUint32(&thread.workers[i].hashes, 0)
 				s += strconv.FormatUint(uint64(hashesPerSecond), 10) + " "
+				total += uint64(hashesPerSecond)
 			}
 			gui.GUI.InfoUpdate("Hashes/s", s)
 
+			atomic.AddUint64(&thread.hashesTotal, total)
+			atomic.StoreUint64(&thread.hashesPerSecond, total)
+
 			time.Sleep(time.Second)
 		}
 	})
 
 	recovery.SafeGo(func() {
-		var err error
-		var newKernelHash []byte
-
 		for {
 			solution, ok := <-forgingWorkerSolutionCn
 			if !ok {
@@ -78,11 +86,13 @@ func (thread *ForgingThread) startForging() {
 				continue
 			}
 
-			if newKernelHash, err = thread.publishSolution(solution); err != nil {
-				gui.GUI.Error(fmt.Errorf("Error publishing solution: %d error: %s ", solution.blkComplete.Height, err))
+			//an offline remote signer should not delay the other solutions
+			if solution.remoteSigner != nil {
+				recovery.SafeGo(func() {
+					thread.processSolution(solution)
+				})
 			} else {
-				gui.GUI.Info(fmt.Errorf("Block was forged! %d ", solution.blkComplete.Height))
-				thread.lastPrevKernelHash.Store(newKernelHash)
+				thread.processSolution(solution)
 			}
 
 		}
@@ -107,6 +117,17 @@ func (thread *ForgingThread) startForging() {
 
 }
 
+func (thread *ForgingThread) processSolution(solution *ForgingSolution) {
+	if newKernelHash, err := thread.publishSolution(solution); err != nil {
+		atomic.AddUint64(&thread.solutionsRejected, 1)
+		gui.GUI.Error(fmt.Errorf("Error publishing solution: %d error: %s ", solution.blkComplete.Height, err))
+	} else {
+		atomic.AddUint64(&thread.blocksForged, 1)
+		gui.GUI.Info(fmt.Errorf("Block was forged! %d ", solution.blkComplete.Height))
+		thread.lastPrevKernelHash.Store(newKernelHash)
+	}
+}
+
 func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte, error) {
 
 	newBlk := block_complete.CreateEmptyBlockComplete()
@@ -120,8 +141,19 @@ func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte,
 
 	txs, _ := thread.mempool.GetNextTransactionsToInclude(newBlk.Block.PrevHash)
 
-	txStakingReward, err := thread.createForgingTransactions(newBlk, solution.publicKey, solution.decryptedStakingBalance, txs)
-	if err != nil {
+	var txStakingReward *transaction.Transaction
+	var err error
+
+	if solution.remoteSigner != nil {
+		newBlk.Txs = txs
+
+		ctx, cancel := context.WithTimeout(context.Background(), config_forging.FORGING_REMOTE_SIGNER_TIMEOUT)
+		defer cancel()
+
+		if txStakingReward, err = solution.remoteSigner.CreateForgingTransaction(solution.publicKey, newBlk, solution.decryptedStakingBalance, ctx); err != nil {
+			return nil, fmt.Errorf("Remote signer failed: %s", err)
+		}
+	} else if txStakingReward, err = thread.createForgingTransactions(newBlk, solution.publicKey, solution.decryptedStakingBalance, txs); err != nil {
 		return nil, err
 	}
 
@@ -146,7 +178,7 @@ func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte,
 }
 
 func createForgingThread(threads int, createForgingTransactions func(*block_complete.BlockComplete, []byte, uint64, []*transaction.Transaction) (*transaction.Transaction, error), mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, solutionCn chan<- *blockchain_types.BlockchainSolution, nextBlockCreatedCn <-chan *forging_block_work.ForgingWork) *ForgingThread {
-	return &ForgingThread{
+	thread := &ForgingThread{
 		mempool,
 		addressBalanceDecryptor,
 		threads,
@@ -157,5 +189,10 @@ func createForgingThread(threads int, createForgingTransactions func(*block_comp
 		make(chan struct{}),
 		&generics.Value[[]byte]{},
 		createForgingTransactions,
+		0, 0, 0, 0,
 	}
+
+	thread.initMetrics()
+
+	return thread
 }
@@ -43,9 +43,19 @@ type ForgingWalletAddressUpdate struct {
 	sharedStaked *shared_staked.WalletAddressSharedStaked
 	account      *account.Account
 	registration *registration.Registration
+	remoteSigner ForgingRemoteSigner
 }
 
 func (w *ForgingWallet) AddWallet(publicKey []byte, sharedStaked *shared_staked.WalletAddressSharedStaked, hasAccount bool, account *account.Account, reg *registration.Registration, chainHeight uint64) (err error) {
+	return w.addWallet(publicKey, sharedStaked, nil, hasAccount, account, reg, chainHeight)
+}
+
+// AddRemoteWallet stakes a delegated address whose private key is kept by the remote signer
+func (w *ForgingWallet) AddRemoteWallet(publicKey []byte, remoteSigner ForgingRemoteSigner) error {
+	return w.addWallet(publicKey, nil, remoteSigner, false, nil, nil, 0)
+}
+
+func (w *ForgingWallet) addWallet(publicKey []byte, sharedStaked *shared_staked.WalletAddressSharedStaked, remoteSigner ForgingRemoteSigner, hasAccount bool, account *account.Account, reg *registration.Registration, chainHeight uint64) (err error) {
 
 	if !config_forging.FORGING_ENABLED || w.initialized.IsNotSet() {
 		return
@@ -84,6 +94,7 @@ func (w *ForgingWallet) AddWallet(publicKey []byte, sharedStaked *shared_staked.
 		sharedStaked,
 		account,
 		reg,
+		remoteSigner,
 	}
 	return
 }
@@ -108,8 +119,10 @@ func (w *ForgingWallet) runDecryptBalanceAndNotifyWorkers() {
 			time.Sleep(10 * time.Millisecond)
 			continue
 		} else {
-			stakingAmountEncryptedBalanceSerialized := addr.account.Balance.Amount.Serialize()
-			addr.decryptedStakingBalance, _ = w.addressBalanceDecryptor.DecryptBalance("staking", addr.publicKey, addr.privateKey.Key, stakingAmountEncryptedBalanceSerialized, config_coins.NATIVE_ASSET_FULL, false, 0, true, context.Background(), func(string) {})
+			if addr.remoteSigner == nil { //remote signers send the decrypted balance together with the staking nonce
+				stakingAmountEncryptedBalanceSerialized := addr.account.Balance.Amount.Serialize()
+				addr.decryptedStakingBalance, _ = w.addressBalanceDecryptor.DecryptBalance("staking", addr.publicKey, addr.privateKey.Key, stakingAmountEncryptedBalanceSerialized, config_coins.NATIVE_ASSET_FULL, false, 0, true, context.Background(), func(string) {})
+			}
 
 			w.workers[addr.workerIndex].addWalletAddressCn <- addr
 		}
@@ -188,8 +201,11 @@ func (w *ForgingWallet) runProcessUpdates() {
 			key := string(update.publicKey)
 
 			//let's delete it
-			if update.sharedStaked == nil || update.sharedStaked.PrivateKey == nil {
+			if (update.sharedStaked == nil || update.sharedStaked.PrivateKey == nil) && update.remoteSigner == nil {
 				w.removeAccountFromForgingWorkers(key)
+				if address := w.addressesMap[key]; address != nil && address.remoteSigner != nil {
+					delete(w.addressesMap, key)
+				}
 			} else {
 
 				if err = func() (err error) {
@@ -208,20 +224,40 @@ func (w *ForgingWallet) runProcessUpdates() {
 					address := w.addressesMap[key]
 					if address == nil {
 
-						keyPoint := new(crypto.BNRed).SetBytes(update.sharedStaked.PrivateKey.Key)
-
-						address = &ForgingWalletAddress{
-							update.sharedStaked.PrivateKey,
-							keyPoint.BigInt(),
-							update.publicKey,
-							string(update.publicKey),
-							update.account,
-							0,
-							-1,
-							chainHash,
+						if update.remoteSigner != nil {
+							address = &ForgingWalletAddress{
+								nil,
+								nil,
+								update.publicKey,
+								string(update.publicKey),
+								update.account,
+								0,
+								-1,
+								chainHash,
+								update.remoteSigner,
+							}
+						} else {
+							keyPoint := new(crypto.BNRed).SetBytes(update.sharedStaked.PrivateKey.Key)
+
+							address = &ForgingWalletAddress{
+								update.sharedStaked.PrivateKey,
+								keyPoint.BigInt(),
+								update.publicKey,
+								string(update.publicKey),
+								update.account,
+								0,
+								-1,
+								chainHash,
+								nil,
+							}
 						}
+
 						w.addressesMap[key] = address
 						w.updateAccountToForgingWorkers(address)
+					} else if update.remoteSigner != nil && address.remoteSigner != nil { //the remote signer reconnected
+						address.remoteSigner = update.remoteSigner
+						address.account = update.account
+						w.updateAccountToForgingWorkers(address)
 					}
 
 					return
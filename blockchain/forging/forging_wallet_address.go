@@ -15,6 +15,7 @@ type ForgingWalletAddress struct {
 	decryptedStakingBalance uint64
 	workerIndex             int
 	chainHash               []byte
+	remoteSigner            ForgingRemoteSigner //the private key is kept by the remote signer
 }
 
 func (walletAddr *ForgingWalletAddress) clone() *ForgingWalletAddress {
@@ -27,5 +28,6 @@ func (walletAddr *ForgingWalletAddress) clone() *ForgingWalletAddress {
 		walletAddr.decryptedStakingBalance,
 		walletAddr.workerIndex,
 		walletAddr.chainHash,
+		walletAddr.remoteSigner,
 	}
 }
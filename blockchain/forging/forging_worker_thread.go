@@ -2,20 +2,20 @@ package forging
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"math/big"
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
 	"pandora-pay/config"
-	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forging"
 	"pandora-pay/cryptography"
-	"pandora-pay/cryptography/bn256"
-	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
-	"strconv"
+	"pandora-pay/helpers/recovery"
 	"sync/atomic"
 	"time"
 )
@@ -27,6 +27,7 @@ type ForgingSolution struct {
 	blkComplete             *block_complete.BlockComplete
 	stakingAmount           uint64
 	stakingNonce            []byte
+	remoteSigner            ForgingRemoteSigner
 }
 
 type ForgingWorkerThread struct {
@@ -37,6 +38,7 @@ type ForgingWorkerThread struct {
 	workerSolutionCn        chan *ForgingSolution
 	addWalletAddressCn      chan *ForgingWalletAddress
 	removeWalletAddressCn   chan string //publicKey
+	remoteStakingDataCn     chan *forgingRemoteStakingAnswer
 }
 
 type ForgingWorkerThreadAddress struct {
@@ -44,20 +46,22 @@ type ForgingWorkerThreadAddress struct {
 	stakingAmount                   uint64
 	stakingNonce                    []byte
 	stakingNoncePrevChainKernelHash []byte
+	remoteStakingBalance            uint64
+	remoteRequestedPrevKernelHash   []byte
 }
 
 func (worker *ForgingWorkerThread) computeStakingAmount(threadAddr *ForgingWorkerThreadAddress, work *forging_block_work.ForgingWork) bool {
 
+	if threadAddr.walletAdr.remoteSigner != nil {
+		return worker.computeRemoteStakingAmount(threadAddr, work)
+	}
+
 	if threadAddr.walletAdr.account != nil && threadAddr.walletAdr.privateKey != nil {
 
 		if threadAddr.walletAdr.decryptedStakingBalance >= work.MinimumStake {
 
 			if !bytes.Equal(threadAddr.stakingNoncePrevChainKernelHash, work.BlkComplete.PrevKernelHash) {
-				uinput := append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), work.BlkComplete.PrevKernelHash[:]...)
-				uinput = append(uinput, config_coins.NATIVE_ASSET_FULL...)
-				uinput = append(uinput, strconv.Itoa(0)...)
-				u := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(uinput)), threadAddr.walletAdr.privateKeyPoint)
-				threadAddr.stakingNonce = cryptography.SHA3(u.EncodeCompressed())
+				threadAddr.stakingNonce = ComputeStakingNonce(threadAddr.walletAdr.privateKeyPoint, work.BlkComplete.PrevKernelHash)
 				threadAddr.stakingNoncePrevChainKernelHash = work.BlkComplete.PrevKernelHash
 			}
 
@@ -71,6 +75,55 @@ func (worker *ForgingWorkerThread) computeStakingAmount(threadAddr *ForgingWorke
 	return false
 }
 
+// computeRemoteStakingAmount uses the staking data answered by the remote signer. Until the answer arrives, the address is not staked
+func (worker *ForgingWorkerThread) computeRemoteStakingAmount(threadAddr *ForgingWorkerThreadAddress, work *forging_block_work.ForgingWork) bool {
+
+	threadAddr.stakingAmount = 0
+
+	if threadAddr.walletAdr.account == nil {
+		return false
+	}
+
+	if bytes.Equal(threadAddr.stakingNoncePrevChainKernelHash, work.BlkComplete.PrevKernelHash) {
+		threadAddr.walletAdr.decryptedStakingBalance = threadAddr.remoteStakingBalance
+		if threadAddr.remoteStakingBalance >= work.MinimumStake {
+			threadAddr.stakingAmount = threadAddr.remoteStakingBalance
+			return true
+		}
+		return false
+	}
+
+	if !bytes.Equal(threadAddr.remoteRequestedPrevKernelHash, work.BlkComplete.PrevKernelHash) {
+		threadAddr.remoteRequestedPrevKernelHash = work.BlkComplete.PrevKernelHash
+		worker.requestRemoteStakingData(threadAddr.walletAdr.remoteSigner, threadAddr.walletAdr.publicKey, work.BlkComplete.PrevKernelHash)
+	}
+
+	return false
+}
+
+// requestRemoteStakingData asks the remote signer in background. If the signer doesn't answer in time, the address is skipped for this block
+func (worker *ForgingWorkerThread) requestRemoteStakingData(remoteSigner ForgingRemoteSigner, publicKey, prevKernelHash []byte) {
+	recovery.SafeGo(func() {
+
+		ctx, cancel := context.WithTimeout(context.Background(), config_forging.FORGING_REMOTE_SIGNER_TIMEOUT)
+		defer cancel()
+
+		data, err := remoteSigner.GetStakingData(publicKey, prevKernelHash, ctx)
+		if err == nil && len(data.StakingNonce) != cryptography.HashSize {
+			err = errors.New("Invalid staking nonce")
+		}
+		if err != nil {
+			gui.GUI.Error("Remote signer didn't answer the staking data", err)
+			return
+		}
+
+		select {
+		case worker.remoteStakingDataCn <- &forgingRemoteStakingAnswer{string(publicKey), prevKernelHash, data}:
+		case <-ctx.Done():
+		}
+	})
+}
+
 /*
 *
 "Staking multiple wallets simultaneously"
@@ -140,6 +193,8 @@ func (worker *ForgingWorkerThread) forge() {
 				0,
 				nil,
 				nil,
+				0,
+				nil,
 			}
 			wallets[newWalletAddr.publicKeyStr] = walletAddr
 		} else {
@@ -164,6 +219,26 @@ func (worker *ForgingWorkerThread) forge() {
 		validateWork()
 	}
 
+	remoteStakingData := func(answer *forgingRemoteStakingAnswer) {
+		walletAddr := wallets[answer.publicKeyStr]
+		if walletAddr == nil || walletAddr.walletAdr.remoteSigner == nil {
+			return
+		}
+
+		walletAddr.stakingNonce = answer.data.StakingNonce
+		walletAddr.stakingNoncePrevChainKernelHash = answer.prevKernelHash
+		walletAddr.remoteStakingBalance = answer.data.DecryptedStakingBalance
+
+		if work != nil && bytes.Equal(answer.prevKernelHash, work.BlkComplete.PrevKernelHash) && !walletsStakedUsed[answer.publicKeyStr] {
+			if worker.computeStakingAmount(walletAddr, work) {
+				walletsStaked[answer.publicKeyStr] = walletAddr
+				walletsStakedTimestamp[answer.publicKeyStr] = timestamp
+			}
+		}
+
+		validateWork()
+	}
+
 	removeWalletAddr := func(publicKeyStr string) {
 		if wallets[publicKeyStr] != nil {
 			delete(wallets, publicKeyStr)
@@ -185,6 +260,9 @@ func (worker *ForgingWorkerThread) forge() {
 		case publicKeyStr := <-worker.removeWalletAddressCn:
 			removeWalletAddr(publicKeyStr)
 			continue
+		case answer := <-worker.remoteStakingDataCn:
+			remoteStakingData(answer)
+			continue
 		case <-waitCn:
 		}
 
@@ -218,6 +296,8 @@ func (worker *ForgingWorkerThread) forge() {
 						if key == publicKeyStr {
 							goto done
 						}
+					case answer := <-worker.remoteStakingDataCn:
+						remoteStakingData(answer)
 					default:
 					}
 
@@ -251,6 +331,7 @@ func (worker *ForgingWorkerThread) forge() {
 							work.BlkComplete,
 							generics.Max(generics.Min(requireStakingAmount.Uint64()+1, address.stakingAmount), work.MinimumStake),
 							address.stakingNonce,
+							address.walletAdr.remoteSigner,
 						}
 
 						select {
@@ -267,6 +348,8 @@ func (worker *ForgingWorkerThread) forge() {
 							if key == publicKeyStr { // in case it was deleted
 								goto done
 							}
+						case answer := <-worker.remoteStakingDataCn:
+							remoteStakingData(answer)
 						case worker.workerSolutionCn <- solution:
 							delete(walletsStaked, key)
 							walletsStakedUsed[key] = true
@@ -303,5 +386,6 @@ func createForgingWorkerThread(index int, workerSolutionCn chan *ForgingSolution
 		workerSolutionCn:        workerSolutionCn,
 		addWalletAddressCn:      make(chan *ForgingWalletAddress),
 		removeWalletAddressCn:   make(chan string),
+		remoteStakingDataCn:     make(chan *forgingRemoteStakingAnswer),
 	}
 }
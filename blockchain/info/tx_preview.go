@@ -56,6 +56,14 @@ func CreateTxPreviewFromTx(tx *transaction.Transaction) (*TxPreview, error) {
 				txBaseExtra.PayloadIndex,
 				txBaseExtra.Resolution,
 			}
+		case transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
+
+			txBaseExtra := txBase.Extra.(*transaction_simple_extra.TransactionSimpleExtraUpdatePlainAccountMultisig)
+
+			previewBase.Extra = &TxPreviewSimpleExtraUpdatePlainAccountMultisig{
+				txBaseExtra.Threshold,
+				txBaseExtra.PublicKeys,
+			}
 		}
 
 		base = previewBase
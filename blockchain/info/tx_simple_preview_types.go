@@ -11,6 +11,11 @@ type TxPreviewSimpleExtraResolutionConditionalPayment struct {
 	Resolution   bool   `json:"resolution" msgpack:"resolution"`
 }
 
+type TxPreviewSimpleExtraUpdatePlainAccountMultisig struct {
+	Threshold  byte     `json:"threshold" msgpack:"threshold"`
+	PublicKeys [][]byte `json:"publicKeys" msgpack:"publicKeys"`
+}
+
 type TxPreviewSimple struct {
 	TxScript    transaction_simple.ScriptType           `json:"txScript" msgpack:"txScript"`
 	DataVersion transaction_data.TransactionDataVersion `json:"dataVersion" msgpack:"dataVersion"`
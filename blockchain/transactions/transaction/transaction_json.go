@@ -51,8 +51,10 @@ type json_TransactionSimple struct {
 }
 
 type json_TransactionSimpleInput struct {
-	PublicKey []byte `json:"publicKey,omitempty" msgpack:"publicKey,omitempty"` //32
-	Signature []byte `json:"signature" msgpack:"signature"`                     //64
+	PublicKey          []byte   `json:"publicKey,omitempty" msgpack:"publicKey,omitempty"` //32
+	Signature          []byte   `json:"signature" msgpack:"signature"`                     //64
+	MultisigPublicKeys [][]byte `json:"multisigPublicKeys,omitempty" msgpack:"multisigPublicKeys,omitempty"`
+	MultisigSignatures [][]byte `json:"multisigSignatures,omitempty" msgpack:"multisigSignatures,omitempty"`
 }
 
 type json_Only_TransactionSimpleExtraUpdateAssetFeeLiquidity struct {
@@ -69,6 +71,11 @@ type json_Only_TransactionSimpleExtraResolutionConditionalPayment struct {
 	Signatures         [][]byte `json:"signatures"`
 }
 
+type json_Only_TransactionSimpleExtraUpdatePlainAccountMultisig struct {
+	Threshold  byte     `json:"threshold"`
+	PublicKeys [][]byte `json:"publicKeys"`
+}
+
 type json_Only_TransactionZether struct {
 	ChainHeight     uint64                          `json:"chainHeight"  msgpack:"chainHeight"`
 	ChainKernelHash []byte                          `json:"chainKernelHash"  msgpack:"chainKernelHash"`
@@ -100,6 +107,32 @@ type json_Only_TransactionZetherPayloadExtraAssetSupplyIncrease struct {
 	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
 }
 
+type json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease struct {
+	AssetSupplyPublicKey []byte `json:"assetSupplyPublicKey"  msgpack:"assetSupplyPublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetPause struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	Paused               bool   `json:"paused"  msgpack:"paused"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetFreeze struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
+type json_Only_TransactionZetherPayloadExtraAssetUpdateKeys struct {
+	AssetId              []byte `json:"assetId"  msgpack:"assetId"`
+	NewUpdatePublicKey   []byte `json:"newUpdatePublicKey,omitempty"  msgpack:"newUpdatePublicKey,omitempty"`
+	NewSupplyPublicKey   []byte `json:"newSupplyPublicKey,omitempty"  msgpack:"newSupplyPublicKey,omitempty"`
+	AssetUpdatePublicKey []byte `json:"assetUpdatePublicKey"  msgpack:"assetUpdatePublicKey"`
+	AssetSignature       []byte `json:"assetSignature"  msgpack:"assetSignature"`
+}
+
 type json_Only_TransactionZetherPayloadExtraPlainAccountFund struct {
 	PlainAccountPublicKey []byte `json:"plainAccountPublicKey"  msgpack:"plainAccountPublicKey"`
 }
@@ -162,6 +195,8 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 			vinJson = &json_TransactionSimpleInput{
 				base.Vin.PublicKey,
 				base.Vin.Signature,
+				base.Vin.MultisigPublicKeys,
+				base.Vin.MultisigSignatures,
 			}
 		}
 
@@ -193,6 +228,12 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 				extra.MultisigPublicKeys,
 				extra.Signatures,
 			}
+		case transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
+			extra := base.Extra.(*transaction_simple_extra.TransactionSimpleExtraUpdatePlainAccountMultisig)
+			simpleJson.Extra = json_Only_TransactionSimpleExtraUpdatePlainAccountMultisig{
+				extra.Threshold,
+				extra.PublicKeys,
+			}
 		default:
 			return nil, errors.New("Invalid simple.TxScript")
 		}
@@ -278,6 +319,36 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 					payloadExtra.MultisigThreshold,
 					payloadExtra.MultisigPublicKeys,
 				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease{
+					payloadExtra.AssetSupplyPublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetPause{
+					payloadExtra.AssetId,
+					payloadExtra.Paused,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetFreeze{
+					payloadExtra.AssetId,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE_KEYS:
+				payloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdateKeys)
+				extra = &json_Only_TransactionZetherPayloadExtraAssetUpdateKeys{
+					payloadExtra.AssetId,
+					payloadExtra.NewUpdatePublicKey,
+					payloadExtra.NewSupplyPublicKey,
+					payloadExtra.AssetUpdatePublicKey,
+					payloadExtra.AssetSignature,
+				}
 			default:
 				return nil, errors.New("Invalid zether.TxScript")
 			}
@@ -368,8 +439,10 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 		}
 
 		vin := &transaction_simple_parts.TransactionSimpleInput{
-			PublicKey: simpleJson.Vin.PublicKey,
-			Signature: simpleJson.Vin.Signature,
+			PublicKey:          simpleJson.Vin.PublicKey,
+			Signature:          simpleJson.Vin.Signature,
+			MultisigPublicKeys: simpleJson.Vin.MultisigPublicKeys,
+			MultisigSignatures: simpleJson.Vin.MultisigSignatures,
 		}
 
 		base := &transaction_simple.TransactionSimple{
@@ -410,6 +483,16 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 				extraJson.MultisigPublicKeys,
 				extraJson.Signatures,
 			}
+		case transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
+			extraJson := &json_Only_TransactionSimpleExtraUpdatePlainAccountMultisig{}
+			if err = json.Unmarshal(data, extraJson); err != nil {
+				return
+			}
+
+			base.Extra = &transaction_simple_extra.TransactionSimpleExtraUpdatePlainAccountMultisig{nil,
+				extraJson.Threshold,
+				extraJson.PublicKeys,
+			}
 		default:
 			return errors.New("Invalid json Simple TxScript")
 		}
@@ -568,6 +651,52 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 					extraJson.MultisigThreshold,
 					extraJson.MultisigPublicKeys,
 				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetSupplyDecrease{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease{
+					nil,
+					extraJson.AssetSupplyPublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetPause{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause{
+					nil,
+					extraJson.AssetId,
+					extraJson.Paused,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetFreeze{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze{
+					nil,
+					extraJson.AssetId,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE_KEYS:
+				extraJson := &json_Only_TransactionZetherPayloadExtraAssetUpdateKeys{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdateKeys{
+					nil,
+					extraJson.AssetId,
+					extraJson.NewUpdatePublicKey,
+					extraJson.NewSupplyPublicKey,
+					extraJson.AssetUpdatePublicKey,
+					extraJson.AssetSignature,
+				}
 			default:
 				return errors.New("Invalid Zether TxScript")
 			}
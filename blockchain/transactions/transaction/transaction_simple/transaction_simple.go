@@ -10,7 +10,6 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_parts"
 	"pandora-pay/config"
-	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers/advanced_buffers"
 )
 
@@ -38,6 +37,17 @@ func (tx *TransactionSimple) IncludeTransaction(blockHeight uint64, txHash []byt
 			return errors.New("Plain Account was not found")
 		}
 
+		if plainAcc.Multisig.HasMultisig() {
+			if !tx.Vin.IsMultisig() {
+				return errors.New("Plain Account requires multisig")
+			}
+			if err = plainAcc.Multisig.VerifySigners(tx.Vin.MultisigPublicKeys); err != nil {
+				return
+			}
+		} else if tx.Vin.IsMultisig() {
+			return errors.New("Plain Account is not multisig")
+		}
+
 		if plainAcc.Nonce != tx.Nonce {
 			return fmt.Errorf("Account nonce doesn't match %d %d", plainAcc.Nonce, tx.Nonce)
 		}
@@ -78,7 +88,7 @@ func (tx *TransactionSimple) ComputeAllKeys(out map[string]bool) {
 
 func (tx *TransactionSimple) VerifySignatureManually(hashForSignature []byte) bool {
 	if tx.HasVin() {
-		if !crypto.VerifySignature(hashForSignature, tx.Vin.Signature, tx.Vin.PublicKey) {
+		if !tx.Vin.VerifySignature(hashForSignature) {
 			return false
 		}
 	}
@@ -101,7 +111,7 @@ func (tx *TransactionSimple) Validate() (err error) {
 	}
 
 	switch tx.TxScript {
-	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
+	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT, SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
 		if tx.Extra == nil {
 			return errors.New("extra is not assigned")
 		}
@@ -117,7 +127,11 @@ func (tx *TransactionSimple) Validate() (err error) {
 
 func (tx *TransactionSimple) SerializeAdvanced(w *advanced_buffers.BufferWriter, inclSignature bool) {
 
-	w.WriteUvarint(uint64(tx.TxScript))
+	script := uint64(tx.TxScript)
+	if tx.HasVin() && tx.Vin.IsMultisig() {
+		script |= SCRIPT_FLAG_MULTISIG_VIN
+	}
+	w.WriteUvarint(script)
 
 	w.WriteByte(byte(tx.DataVersion))
 	if tx.DataVersion == transaction_data.TX_DATA_PLAIN_TEXT || tx.DataVersion == transaction_data.TX_DATA_ENCRYPTED {
@@ -146,16 +160,24 @@ func (tx *TransactionSimple) Deserialize(r *advanced_buffers.BufferReader) (err
 		return
 	}
 
-	tx.TxScript = ScriptType(n)
+	multisig := n&SCRIPT_FLAG_MULTISIG_VIN != 0
+
+	tx.TxScript = ScriptType(n &^ SCRIPT_FLAG_MULTISIG_VIN)
 	switch tx.TxScript {
 	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
 		tx.Extra = &transaction_simple_extra.TransactionSimpleExtraUpdateAssetFeeLiquidity{}
 	case SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 		tx.Extra = &transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPayment{}
+	case SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
+		tx.Extra = &transaction_simple_extra.TransactionSimpleExtraUpdatePlainAccountMultisig{}
 	default:
 		return errors.New("INVALID SCRIPT TYPE")
 	}
 
+	if multisig && !tx.HasVin() {
+		return errors.New("Multisig flag is set without Vin")
+	}
+
 	var dataVersion byte
 	if dataVersion, err = r.ReadByte(); err != nil {
 		return
@@ -180,7 +202,7 @@ func (tx *TransactionSimple) Deserialize(r *advanced_buffers.BufferReader) (err
 			return
 		}
 		tx.Vin = &transaction_simple_parts.TransactionSimpleInput{}
-		if err = tx.Vin.Deserialize(r); err != nil {
+		if err = tx.Vin.Deserialize(r, multisig); err != nil {
 			return
 		}
 	}
@@ -194,7 +216,7 @@ func (tx *TransactionSimple) Deserialize(r *advanced_buffers.BufferReader) (err
 
 func (tx *TransactionSimple) HasVin() bool {
 	switch tx.TxScript {
-	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
+	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
 		return true
 	default:
 		return false
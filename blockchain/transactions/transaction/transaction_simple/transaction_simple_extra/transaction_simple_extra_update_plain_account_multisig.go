@@ -0,0 +1,58 @@
+package transaction_simple_extra
+
+import (
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/plain_account_multisig"
+	"pandora-pay/config"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+// TransactionSimpleExtraUpdatePlainAccountMultisig sets the M-of-N policy of the Vin plain account. A zero threshold removes the policy
+type TransactionSimpleExtraUpdatePlainAccountMultisig struct {
+	TransactionSimpleExtraInterface
+	Threshold  byte
+	PublicKeys [][]byte
+}
+
+func (txExtra *TransactionSimpleExtraUpdatePlainAccountMultisig) IncludeTransactionVin0(blockHeight uint64, plainAcc *plain_account.PlainAccount, dataStorage *data_storage.DataStorage) (err error) {
+	return plainAcc.SetMultisig(txExtra.Threshold, txExtra.PublicKeys)
+}
+
+func (txExtra *TransactionSimpleExtraUpdatePlainAccountMultisig) Validate(fee uint64) error {
+	return plain_account_multisig.Validate(txExtra.Threshold, txExtra.PublicKeys)
+}
+
+func (txExtra *TransactionSimpleExtraUpdatePlainAccountMultisig) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
+	w.WriteByte(txExtra.Threshold)
+	w.WriteByte(byte(len(txExtra.PublicKeys)))
+	for _, publicKey := range txExtra.PublicKeys {
+		w.Write(publicKey)
+	}
+}
+
+func (txExtra *TransactionSimpleExtraUpdatePlainAccountMultisig) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+
+	if txExtra.Threshold, err = r.ReadByte(); err != nil {
+		return
+	}
+
+	var count byte
+	if count, err = r.ReadByte(); err != nil {
+		return
+	}
+	if int(count) > config.TRANSACTIONS_MULTISIG_MAX {
+		return errors.New("Invalid number of Public Keys")
+	}
+
+	txExtra.PublicKeys = make([][]byte, count)
+	for i := range txExtra.PublicKeys {
+		if txExtra.PublicKeys[i], err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+
+	return
+}
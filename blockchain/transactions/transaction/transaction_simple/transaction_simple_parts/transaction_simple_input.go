@@ -3,14 +3,34 @@ package transaction_simple_parts
 import (
 	"bytes"
 	"errors"
+	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers/advanced_buffers"
 )
 
 type TransactionSimpleInput struct {
-	PublicKey []byte //33
-	Signature []byte //64
+	PublicKey          []byte   //33
+	Signature          []byte   //64, not used by multisig
+	MultisigPublicKeys [][]byte //signers of a multisig plain account
+	MultisigSignatures [][]byte
+}
+
+func (vin *TransactionSimpleInput) IsMultisig() bool {
+	return len(vin.MultisigPublicKeys) > 0
+}
+
+func (vin *TransactionSimpleInput) VerifySignature(hashForSignature []byte) bool {
+	if !vin.IsMultisig() {
+		return crypto.VerifySignature(hashForSignature, vin.Signature, vin.PublicKey)
+	}
+	for i := range vin.MultisigPublicKeys {
+		if !crypto.VerifySignature(hashForSignature, vin.MultisigSignatures[i], vin.MultisigPublicKeys[i]) {
+			return false
+		}
+	}
+	return true
 }
 
 func (vin *TransactionSimpleInput) Validate() error {
@@ -22,25 +42,89 @@ func (vin *TransactionSimpleInput) Validate() error {
 	if len(vin.PublicKey) != cryptography.PublicKeySize {
 		return errors.New("Vin.PublicKey length is invalid")
 	}
-	if len(vin.Signature) != cryptography.SignatureSize {
-		return errors.New("Vin.Signature length is invalid")
+
+	if !vin.IsMultisig() {
+		if len(vin.Signature) != cryptography.SignatureSize {
+			return errors.New("Vin.Signature length is invalid")
+		}
+		return nil
+	}
+
+	if len(vin.Signature) != 0 {
+		return errors.New("Vin.Signature should be empty for multisig")
+	}
+	if len(vin.MultisigPublicKeys) > config.TRANSACTIONS_MULTISIG_MAX {
+		return errors.New("Invalid number of multisig Public Keys")
+	}
+	if len(vin.MultisigPublicKeys) != len(vin.MultisigSignatures) {
+		return errors.New("Signatures and Public Keys Mismatch")
+	}
+
+	unique := make(map[string]bool)
+	for i := range vin.MultisigPublicKeys {
+		if len(vin.MultisigPublicKeys[i]) != cryptography.PublicKeySize {
+			return errors.New("Vin.MultisigPublicKeys length is invalid")
+		}
+		if len(vin.MultisigSignatures[i]) != cryptography.SignatureSize {
+			return errors.New("Vin.MultisigSignatures length is invalid")
+		}
+		unique[string(vin.MultisigPublicKeys[i])] = true
 	}
+	if len(unique) != len(vin.MultisigPublicKeys) {
+		return errors.New("public Keys contain duplicates")
+	}
+
 	return nil
 }
 
+// Serialize writes the multisig signers only for the multisig Vin. The transaction flags the multisig Vin in its script
 func (vin *TransactionSimpleInput) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
 	w.Write(vin.PublicKey)
-	if inclSignature {
-		w.Write(vin.Signature)
+	if !vin.IsMultisig() {
+		if inclSignature {
+			w.Write(vin.Signature)
+		}
+		return
+	}
+	w.WriteByte(byte(len(vin.MultisigPublicKeys)))
+	for i := range vin.MultisigPublicKeys {
+		w.Write(vin.MultisigPublicKeys[i])
+		if inclSignature {
+			w.Write(vin.MultisigSignatures[i])
+		}
 	}
 }
 
-func (vin *TransactionSimpleInput) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+func (vin *TransactionSimpleInput) Deserialize(r *advanced_buffers.BufferReader, multisig bool) (err error) {
 	if vin.PublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
 		return
 	}
-	if vin.Signature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+
+	if !multisig {
+		if vin.Signature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+			return
+		}
+		return
+	}
+
+	var n byte
+	if n, err = r.ReadByte(); err != nil {
 		return
 	}
+
+	if n == 0 || int(n) > config.TRANSACTIONS_MULTISIG_MAX {
+		return errors.New("Invalid number of multisig Public Keys")
+	}
+
+	vin.MultisigPublicKeys = make([][]byte, n)
+	vin.MultisigSignatures = make([][]byte, n)
+	for i := range vin.MultisigPublicKeys {
+		if vin.MultisigPublicKeys[i], err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+		if vin.MultisigSignatures[i], err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+			return
+		}
+	}
 	return
 }
@@ -5,14 +5,20 @@ type ScriptType uint64
 const (
 	SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY ScriptType = iota
 	SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT
+	SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG
 )
 
+// SCRIPT_FLAG_MULTISIG_VIN is set in the serialized script when the Vin is signed by multisig signers. The single signature Vin keeps the original layout
+const SCRIPT_FLAG_MULTISIG_VIN uint64 = 1 << 6
+
 func (t ScriptType) String() string {
 	switch t {
 	case SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
 		return "SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY"
 	case SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 		return "SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT"
+	case SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
+		return "SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG"
 	default:
 		return "Unknown ScriptType"
 	}
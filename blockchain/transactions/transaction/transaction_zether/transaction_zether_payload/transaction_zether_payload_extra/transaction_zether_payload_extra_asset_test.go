@@ -0,0 +1,210 @@
+package transaction_zether_payload_extra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+var (
+	assetId        = cryptography.RIPEMD([]byte("AssetId"))
+	assetSignature = make([]byte, cryptography.SignatureSize)
+)
+
+// testAssetDataStorage creates an asset which can be paused, frozen, minted, burned and whose keys can be changed
+func testAssetDataStorage(t *testing.T, updatePublicKey, supplyPublicKey []byte, callback func(dataStorage *data_storage.DataStorage)) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("test")
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+
+		dataStorage := data_storage.NewDataStorage(dbTx)
+
+		ast := &asset.Asset{
+			CanMint:                  true,
+			CanBurn:                  true,
+			CanChangeUpdatePublicKey: true,
+			CanChangeSupplyPublicKey: true,
+			CanPause:                 true,
+			CanFreeze:                true,
+			MaxSupply:                1000,
+			Supply:                   500,
+			UpdatePublicKey:          updatePublicKey,
+			SupplyPublicKey:          supplyPublicKey,
+			Name:                     "Test",
+			Ticker:                   "TEST",
+			Description:              "Test asset",
+		}
+		assert.NoError(t, dataStorage.Asts.CreateAsset(assetId, ast))
+
+		callback(dataStorage)
+		return nil
+	}))
+}
+
+func getTestAsset(t *testing.T, dataStorage *data_storage.DataStorage) *asset.Asset {
+	ast, err := dataStorage.Asts.Get(string(assetId))
+	assert.NoError(t, err)
+	assert.NotNil(t, ast)
+	return ast
+}
+
+func testSerialization(t *testing.T, payloadExtra, payloadExtra2 TransactionZetherPayloadExtraInterface) {
+
+	buf := SerializeToBytes(payloadExtra, true)
+	assert.NoError(t, payloadExtra2.Deserialize(advanced_buffers.NewBufferReader(buf)))
+	assert.Equal(t, payloadExtra, payloadExtra2)
+	assert.Equal(t, buf, SerializeToBytes(payloadExtra2, true))
+}
+
+func TestTransactionZetherPayloadExtraAssetSupplyDecrease(t *testing.T) {
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	supplyPublicKey := privateKey.GeneratePublicKey()
+	otherPublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+
+	payloadExtra := &TransactionZetherPayloadExtraAssetSupplyDecrease{nil, supplyPublicKey, assetSignature}
+
+	assert.NoError(t, payloadExtra.Validate(nil, 0, assetId, 100, nil, false))
+	assert.Error(t, payloadExtra.Validate(nil, 0, assetId, 0, nil, false), "zero burn value should fail")
+	assert.Error(t, payloadExtra.Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 100, nil, false), "native asset should fail")
+	assert.Error(t, (&TransactionZetherPayloadExtraAssetSupplyDecrease{nil, supplyPublicKey[1:], assetSignature}).Validate(nil, 0, assetId, 100, nil, false), "invalid public key should fail")
+
+	hash := cryptography.SHA3([]byte("hash"))
+	signature, err := privateKey.Sign(hash)
+	assert.NoError(t, err)
+	payloadExtra.AssetSignature = signature
+	assert.True(t, payloadExtra.VerifyExtraSignature(hash, nil))
+	assert.False(t, (&TransactionZetherPayloadExtraAssetSupplyDecrease{nil, otherPublicKey, signature}).VerifyExtraSignature(hash, nil), "signature of another key should fail")
+
+	testSerialization(t, payloadExtra, &TransactionZetherPayloadExtraAssetSupplyDecrease{})
+
+	testAssetDataStorage(t, otherPublicKey, supplyPublicKey, func(dataStorage *data_storage.DataStorage) {
+
+		unauthorized := &TransactionZetherPayloadExtraAssetSupplyDecrease{nil, otherPublicKey, assetSignature}
+		assert.Error(t, unauthorized.AfterIncludeTxPayload(nil, nil, 0, assetId, 100, nil, nil, 0, dataStorage), "unauthorized key should fail")
+		assert.Equal(t, uint64(500), getTestAsset(t, dataStorage).Supply)
+
+		assert.NoError(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, assetId, 100, nil, nil, 0, dataStorage))
+		assert.Equal(t, uint64(400), getTestAsset(t, dataStorage).Supply)
+
+		assert.Error(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, assetId, 401, nil, nil, 0, dataStorage), "negative supply should fail")
+	})
+}
+
+func TestTransactionZetherPayloadExtraAssetPause(t *testing.T) {
+
+	updatePublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+	otherPublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+
+	payloadExtra := &TransactionZetherPayloadExtraAssetPause{nil, assetId, true, updatePublicKey, assetSignature}
+
+	assert.NoError(t, payloadExtra.Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, false))
+	assert.Error(t, payloadExtra.Validate(nil, 0, assetId, 0, nil, false), "non native payload asset should fail")
+	assert.Error(t, (&TransactionZetherPayloadExtraAssetPause{nil, config_coins.NATIVE_ASSET_FULL, true, updatePublicKey, assetSignature}).Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, false), "native asset should fail")
+
+	testSerialization(t, payloadExtra, &TransactionZetherPayloadExtraAssetPause{})
+
+	testAssetDataStorage(t, updatePublicKey, otherPublicKey, func(dataStorage *data_storage.DataStorage) {
+
+		unauthorized := &TransactionZetherPayloadExtraAssetPause{nil, assetId, true, otherPublicKey, assetSignature}
+		assert.Error(t, unauthorized.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage), "unauthorized key should fail")
+		assert.False(t, getTestAsset(t, dataStorage).Paused)
+
+		assert.NoError(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage))
+		ast := getTestAsset(t, dataStorage)
+		assert.True(t, ast.Paused)
+		assert.Equal(t, asset.ASSET_VERSION_STATUS, ast.Version)
+
+		assert.Error(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage), "pausing twice should fail")
+
+		resume := &TransactionZetherPayloadExtraAssetPause{nil, assetId, false, updatePublicKey, assetSignature}
+		assert.NoError(t, resume.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage))
+		assert.False(t, getTestAsset(t, dataStorage).Paused)
+	})
+}
+
+func TestTransactionZetherPayloadExtraAssetFreeze(t *testing.T) {
+
+	updatePublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+	supplyPublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+
+	payloadExtra := &TransactionZetherPayloadExtraAssetFreeze{nil, assetId, updatePublicKey, assetSignature}
+
+	assert.NoError(t, payloadExtra.Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, false))
+	assert.Error(t, payloadExtra.Validate(nil, 0, assetId, 0, nil, false), "non native payload asset should fail")
+	assert.Error(t, (&TransactionZetherPayloadExtraAssetFreeze{nil, assetId[1:], updatePublicKey, assetSignature}).Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, false), "invalid asset should fail")
+
+	testSerialization(t, payloadExtra, &TransactionZetherPayloadExtraAssetFreeze{})
+
+	testAssetDataStorage(t, updatePublicKey, supplyPublicKey, func(dataStorage *data_storage.DataStorage) {
+
+		receiverPublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+		_, err := dataStorage.CreateRegistration(receiverPublicKey, false, nil)
+		assert.NoError(t, err)
+
+		mint := &TransactionZetherPayloadExtraAssetSupplyIncrease{nil, assetId, receiverPublicKey, 10, supplyPublicKey, assetSignature}
+		assert.NoError(t, mint.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage))
+		assert.Equal(t, uint64(510), getTestAsset(t, dataStorage).Supply)
+
+		unauthorized := &TransactionZetherPayloadExtraAssetFreeze{nil, assetId, supplyPublicKey, assetSignature}
+		assert.Error(t, unauthorized.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage), "unauthorized key should fail")
+		assert.False(t, getTestAsset(t, dataStorage).Frozen)
+
+		assert.NoError(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage))
+		assert.True(t, getTestAsset(t, dataStorage).Frozen)
+
+		assert.Error(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage), "freezing twice should fail")
+
+		assert.Error(t, mint.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage), "minting a frozen asset should fail")
+
+		burn := &TransactionZetherPayloadExtraAssetSupplyDecrease{nil, supplyPublicKey, assetSignature}
+		assert.Error(t, burn.AfterIncludeTxPayload(nil, nil, 0, assetId, 100, nil, nil, 0, dataStorage), "burning a frozen asset should fail")
+		assert.Equal(t, uint64(510), getTestAsset(t, dataStorage).Supply)
+	})
+}
+
+func TestTransactionZetherPayloadExtraAssetUpdateKeys(t *testing.T) {
+
+	updatePublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+	supplyPublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+	newUpdatePublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+	newSupplyPublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+
+	payloadExtra := &TransactionZetherPayloadExtraAssetUpdateKeys{nil, assetId, newUpdatePublicKey, nil, updatePublicKey, assetSignature}
+
+	assert.NoError(t, payloadExtra.Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, false))
+	assert.Error(t, (&TransactionZetherPayloadExtraAssetUpdateKeys{nil, assetId, nil, nil, updatePublicKey, assetSignature}).Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, false), "no changed key should fail")
+	assert.Error(t, (&TransactionZetherPayloadExtraAssetUpdateKeys{nil, assetId, nil, newSupplyPublicKey[1:], updatePublicKey, assetSignature}).Validate(nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, false), "invalid new key should fail")
+
+	testSerialization(t, payloadExtra, &TransactionZetherPayloadExtraAssetUpdateKeys{})
+	testSerialization(t, &TransactionZetherPayloadExtraAssetUpdateKeys{nil, assetId, nil, newSupplyPublicKey, updatePublicKey, assetSignature}, &TransactionZetherPayloadExtraAssetUpdateKeys{})
+
+	testAssetDataStorage(t, updatePublicKey, supplyPublicKey, func(dataStorage *data_storage.DataStorage) {
+
+		unauthorized := &TransactionZetherPayloadExtraAssetUpdateKeys{nil, assetId, newUpdatePublicKey, nil, supplyPublicKey, assetSignature}
+		assert.Error(t, unauthorized.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage), "unauthorized key should fail")
+		assert.Equal(t, updatePublicKey, getTestAsset(t, dataStorage).UpdatePublicKey)
+
+		assert.NoError(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage))
+		ast := getTestAsset(t, dataStorage)
+		assert.Equal(t, newUpdatePublicKey, ast.UpdatePublicKey)
+		assert.Equal(t, supplyPublicKey, ast.SupplyPublicKey)
+
+		assert.Error(t, payloadExtra.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage), "the old update key should fail")
+
+		changeSupply := &TransactionZetherPayloadExtraAssetUpdateKeys{nil, assetId, nil, newSupplyPublicKey, newUpdatePublicKey, assetSignature}
+		assert.NoError(t, changeSupply.AfterIncludeTxPayload(nil, nil, 0, config_coins.NATIVE_ASSET_FULL, 0, nil, nil, 0, dataStorage))
+		ast = getTestAsset(t, dataStorage)
+		assert.Equal(t, newUpdatePublicKey, ast.UpdatePublicKey)
+		assert.Equal(t, newSupplyPublicKey, ast.SupplyPublicKey)
+	})
+}
@@ -0,0 +1,144 @@
+package transaction_zether_payload_extra
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+// an empty NewUpdatePublicKey or NewSupplyPublicKey means that the key is not changed
+type TransactionZetherPayloadExtraAssetUpdateKeys struct {
+	TransactionZetherPayloadExtraInterface
+	AssetId              []byte
+	NewUpdatePublicKey   []byte
+	NewSupplyPublicKey   []byte
+	AssetUpdatePublicKey []byte
+	AssetSignature       []byte
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) BeforeIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) AfterIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+
+	ast, err := dataStorage.Asts.Get(string(payloadExtra.AssetId))
+	if err != nil {
+		return
+	}
+
+	if ast == nil {
+		return errors.New("Asset was not found")
+	}
+
+	if !bytes.Equal(payloadExtra.AssetUpdatePublicKey, ast.UpdatePublicKey) {
+		return errors.New("Asset UpdatePublicKey is not matching")
+	}
+
+	if len(payloadExtra.NewUpdatePublicKey) > 0 {
+		if !ast.CanChangeUpdatePublicKey {
+			return errors.New("Can't change UpdatePublicKey")
+		}
+		ast.UpdatePublicKey = payloadExtra.NewUpdatePublicKey
+	}
+
+	if len(payloadExtra.NewSupplyPublicKey) > 0 {
+		if !ast.CanChangeSupplyPublicKey {
+			return errors.New("Can't change SupplyPublicKey")
+		}
+		ast.SupplyPublicKey = payloadExtra.NewSupplyPublicKey
+	}
+
+	return dataStorage.Asts.Update(string(payloadExtra.AssetId), ast)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) ComputeAllKeys(out map[string]bool) {
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) VerifyExtraSignature(hashForSignature []byte, payloadStatement *crypto.Statement) bool {
+	return crypto.VerifySignature(hashForSignature, payloadExtra.AssetSignature, payloadExtra.AssetUpdatePublicKey)
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) Validate(payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, payloadParity bool) error {
+	if !bytes.Equal(payloadAsset, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("payloadAsset must be NATIVE_ASSET_FULL")
+	}
+	if len(payloadExtra.AssetId) != config_coins.ASSET_LENGTH || bytes.Equal(payloadExtra.AssetId, config_coins.NATIVE_ASSET_FULL) {
+		return errors.New("Invalid AssetId")
+	}
+	if len(payloadExtra.NewUpdatePublicKey) == 0 && len(payloadExtra.NewSupplyPublicKey) == 0 {
+		return errors.New("No key is changed")
+	}
+	if len(payloadExtra.NewUpdatePublicKey) != 0 && len(payloadExtra.NewUpdatePublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid NewUpdatePublicKey")
+	}
+	if len(payloadExtra.NewSupplyPublicKey) != 0 && len(payloadExtra.NewSupplyPublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid NewSupplyPublicKey")
+	}
+	if len(payloadExtra.AssetUpdatePublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid Public Keys")
+	}
+	if len(payloadExtra.AssetSignature) != cryptography.SignatureSize {
+		return errors.New("Invalid Signature")
+	}
+	return nil
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
+	w.Write(payloadExtra.AssetId)
+	w.WriteBool(len(payloadExtra.NewUpdatePublicKey) > 0)
+	if len(payloadExtra.NewUpdatePublicKey) > 0 {
+		w.Write(payloadExtra.NewUpdatePublicKey)
+	}
+	w.WriteBool(len(payloadExtra.NewSupplyPublicKey) > 0)
+	if len(payloadExtra.NewSupplyPublicKey) > 0 {
+		w.Write(payloadExtra.NewSupplyPublicKey)
+	}
+	w.Write(payloadExtra.AssetUpdatePublicKey)
+	if inclSignature {
+		w.Write(payloadExtra.AssetSignature)
+	}
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+
+	if payloadExtra.AssetId, err = r.ReadBytes(config_coins.ASSET_LENGTH); err != nil {
+		return
+	}
+
+	var changed bool
+	if changed, err = r.ReadBool(); err != nil {
+		return
+	}
+	if changed {
+		if payloadExtra.NewUpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+
+	if changed, err = r.ReadBool(); err != nil {
+		return
+	}
+	if changed {
+		if payloadExtra.NewSupplyPublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+			return
+		}
+	}
+
+	if payloadExtra.AssetUpdatePublicKey, err = r.ReadBytes(cryptography.PublicKeySize); err != nil {
+		return
+	}
+	if payloadExtra.AssetSignature, err = r.ReadBytes(cryptography.SignatureSize); err != nil {
+		return
+	}
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraAssetUpdateKeys) UpdateStatement(payloadStatement *crypto.Statement) error {
+	return nil
+}
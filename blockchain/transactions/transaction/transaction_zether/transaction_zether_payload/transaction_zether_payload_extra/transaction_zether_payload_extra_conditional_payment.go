@@ -4,6 +4,7 @@ import (
 	"errors"
 	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations"
+	"pandora-pay/config"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers/advanced_buffers"
@@ -39,7 +40,7 @@ func (payloadExtra *TransactionZetherPayloadExtraConditionalPayment) VerifyExtra
 }
 
 func (payloadExtra *TransactionZetherPayloadExtraConditionalPayment) Validate(payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, payloadParity bool) error {
-	if payloadExtra.Deadline > 100000 {
+	if payloadExtra.Deadline > config.CONDITIONAL_PAYMENT_DEADLINE_MAX {
 		return errors.New("Deadline should be smaller than 100000")
 	}
 	if payloadExtra.Deadline < 10 {
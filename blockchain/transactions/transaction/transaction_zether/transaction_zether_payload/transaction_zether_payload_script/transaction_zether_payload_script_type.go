@@ -11,6 +11,10 @@ const (
 	SCRIPT_ASSET_SUPPLY_INCREASE
 	SCRIPT_PLAIN_ACCOUNT_FUND
 	SCRIPT_CONDITIONAL_PAYMENT
+	SCRIPT_ASSET_SUPPLY_DECREASE
+	SCRIPT_ASSET_PAUSE
+	SCRIPT_ASSET_FREEZE
+	SCRIPT_ASSET_UPDATE_KEYS
 )
 
 func (t PayloadScriptType) String() string {
@@ -31,6 +35,14 @@ func (t PayloadScriptType) String() string {
 		return "SCRIPT_PLAIN_ACCOUNT_FUND"
 	case SCRIPT_CONDITIONAL_PAYMENT:
 		return "SCRIPT_CONDITIONAL_PAYMENT"
+	case SCRIPT_ASSET_SUPPLY_DECREASE:
+		return "SCRIPT_ASSET_SUPPLY_DECREASE"
+	case SCRIPT_ASSET_PAUSE:
+		return "SCRIPT_ASSET_PAUSE"
+	case SCRIPT_ASSET_FREEZE:
+		return "SCRIPT_ASSET_FREEZE"
+	case SCRIPT_ASSET_UPDATE_KEYS:
+		return "SCRIPT_ASSET_UPDATE_KEYS"
 	default:
 		return "Unknown ScriptType"
 	}
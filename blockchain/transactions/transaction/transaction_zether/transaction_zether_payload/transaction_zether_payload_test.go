@@ -0,0 +1,42 @@
+package transaction_zether_payload
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/cryptography"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestTransactionZetherPayload_IncludePayloadPausedAsset(t *testing.T) {
+
+	assetId := cryptography.RIPEMD([]byte("AssetId"))
+
+	db, err := store_db_memory.CreateStoreDBMemory("test")
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Update(func(dbTx store_db_interface.StoreDBTransactionInterface) error {
+
+		dataStorage := data_storage.NewDataStorage(dbTx)
+
+		ast := &asset.Asset{
+			CanPause:        true,
+			MaxSupply:       1000,
+			UpdatePublicKey: addresses.GenerateNewPrivateKey().GeneratePublicKey(),
+			SupplyPublicKey: addresses.GenerateNewPrivateKey().GeneratePublicKey(),
+			Name:            "Test",
+			Ticker:          "TEST",
+			Description:     "Test asset",
+		}
+		assert.NoError(t, ast.SetPaused(true))
+		assert.NoError(t, dataStorage.Asts.CreateAsset(assetId, ast))
+
+		payload := &TransactionZetherPayload{Asset: assetId}
+		assert.EqualError(t, payload.IncludePayload(nil, 0, nil, 0, dataStorage), "Asset is paused")
+
+		return nil
+	}))
+}
@@ -48,6 +48,14 @@ func PrepareData(data []byte) (txData *TransactionsBuilderCreateZetherTxReq, tra
 			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraPlainAccountFund{}
 		case transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT:
 			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraConditionalPayment{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraAssetSupplyDecrease{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraAssetPause{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraAssetFreeze{}
+		case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE_KEYS:
+			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraAssetUpdateKeys{}
 		default:
 			err = errors.New("Invalid PayloadScriptType")
 			return
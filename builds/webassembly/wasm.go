@@ -139,6 +139,7 @@ func initialize() {
 					"ScriptType": js.ValueOf(map[string]any{
 						"SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY":     js.ValueOf(uint64(transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY)),
 						"SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT": js.ValueOf(uint64(transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT)),
+						"SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG":  js.ValueOf(uint64(transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG)),
 					}),
 				}),
 				"transactionZether": js.ValueOf(map[string]any{
@@ -151,6 +152,10 @@ func initialize() {
 						"SCRIPT_ASSET_SUPPLY_INCREASE": js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE)),
 						"SCRIPT_PLAIN_ACCOUNT_FUND":    js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND)),
 						"SCRIPT_CONDITIONAL_PAYMENT":   js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT)),
+						"SCRIPT_ASSET_SUPPLY_DECREASE": js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE)),
+						"SCRIPT_ASSET_PAUSE":           js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_PAUSE)),
+						"SCRIPT_ASSET_FREEZE":          js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_FREEZE)),
+						"SCRIPT_ASSET_UPDATE_KEYS":     js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_UPDATE_KEYS)),
 					}),
 				}),
 			}),
@@ -177,6 +182,8 @@ func initialize() {
 						"SUBSCRIPTION_ASSET":                js.ValueOf(int(api_code_types.SUBSCRIPTION_ASSET)),
 						"SUBSCRIPTION_REGISTRATION":         js.ValueOf(int(api_code_types.SUBSCRIPTION_REGISTRATION)),
 						"SUBSCRIPTION_TRANSACTION":          js.ValueOf(int(api_code_types.SUBSCRIPTION_TRANSACTION)),
+						"SUBSCRIPTION_INVOICE":              js.ValueOf(int(api_code_types.SUBSCRIPTION_INVOICE)),
+						"SUBSCRIPTION_SCHEDULED_PAYMENT":    js.ValueOf(int(api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT)),
 					}),
 				}),
 			}),
@@ -15,6 +15,8 @@ import (
 	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/api_code/api_code_websockets"
 	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet/invoices"
+	"pandora-pay/wallet/scheduled_payments"
 	"sync/atomic"
 	"syscall/js"
 )
@@ -133,6 +135,12 @@ func listenNetworkNotifications(this js.Value, args []js.Value) interface{} {
 					case api_code_types.SUBSCRIPTION_TRANSACTION:
 						object = data.Data
 						extra = &api_types.APISubscriptionNotificationTxExtra{}
+					case api_code_types.SUBSCRIPTION_INVOICE:
+						object = data.Data
+						extra = &invoices.Invoice{}
+					case api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT:
+						object = data.Data
+						extra = &scheduled_payments.ScheduledPaymentAlert{}
 					default:
 						return //invalid
 					}
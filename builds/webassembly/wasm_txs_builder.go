@@ -49,6 +49,8 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			txData.Extra = &wizard.WizardTxSimpleExtraUpdateAssetFeeLiquidity{}
 		case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
 			txData.Extra = &wizard.WizardTxSimpleExtraResolutionConditionalPayment{}
+		case transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
+			txData.Extra = &wizard.WizardTxSimpleExtraUpdatePlainAccountMultisig{}
 		default:
 			txData.Extra = nil
 			return nil, errors.New("Invalid Tx Simple Script")
@@ -64,6 +66,11 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			txData.Fee,
 			txData.Nonce,
 			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
 		}
 
 		if len(txData.Sender) > 0 {
@@ -72,7 +79,7 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 				return nil, err
 			}
 
-			if senderWalletAddr.PrivateKey.Key == nil {
+			if senderWalletAddr.PrivateKey == nil {
 				return nil, errors.New("Can't be used for transactions as the private key is missing")
 			}
 			transfer.Key = senderWalletAddr.PrivateKey.Key
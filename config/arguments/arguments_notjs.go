@@ -8,7 +8,7 @@ package arguments
 var commands = `PANDORA PAY.
 
 Usage:
-  pandorapay [--pprof] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--tcp-connections-ready=threshold] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY]
+  pandorapay [--pprof] [--network=network] [--debug] [--gui-type=type] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--node-consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--node-provide-extended-info-app=bool] [--node-explorer-indexer=bool] [--node-metrics=bool] [--node-store-backup=bool] [--prune=blocks] [--snapshot-import=path] [--snapshot-import-hash=hash] [--snapshot-export=path] [--verify-chain] [--store-migrate-dry-run] [--store-migrate-backup=path] [--store-restore=path] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-auto-lock=seconds] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--wallet-signer-daemon=path] [--wallet-signer-daemon-keys=keys] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--auth-token-secret=secret] [--auth-token-expiration=seconds] [--auth-hash-password=password] [--webhooks-allowed-hosts=hosts] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--tcp-connections-ready=threshold] [--exit] [--skip-init-sync] [--tcp-server-url=url] [--tcp-proxy=PROXY]
   pandorapay -h | --help
   pandorapay -v | --version
 
@@ -25,11 +25,22 @@ Options:
   --set-genesis=genesis                              Manually set the Genesis via a JSON. By using argument "file" it will read it via a file.
   --create-new-genesis=args                          Create a new Genesis. Useful for creating a new private testnet. Argument must be "0.stake,1.stake,2.stake"
   --store-wallet-type=type                           Set Wallet Store Type. Accepted values: "bolt|bunt|bunt-memory|memory". [default: bolt]
-  --store-chain-type=type                            Set Chain Store Type. Accepted values: "bolt|bunt|bunt-memory|memory".  [default: bolt]
+  --store-chain-type=type                            Set Chain Store Type. Accepted values: "bolt|bunt|bunt-memory|memory|lsm".  [default: bolt]
   --forging                                          Start Forging blocks.
   --node-name=name                                   Change node name.
   --node-consensus=type                              Consensus type. Accepted values: "full|app|none" [default: full].
   --node-provide-extended-info-app=bool              Storing and serving additional info to wallet nodes. [default: true]. To enable, it requires full node
+  --node-explorer-indexer=bool                       Indexing assets, scripts and conditional payments for the explorer routes. Use "true" to enable it. To enable, it requires full node
+  --node-metrics=bool                                Serve the Prometheus metrics at /metrics. Use "true" to enable it. The scrape requires the "metrics" scope using a Bearer token or the basic auth.
+  --node-store-backup=bool                           Serve the backup of the chain store at /store/backup. Use "true" to enable it. It requires the "delegator-admin" scope using a Bearer token or the basic auth. Only one backup is served at once.
+  --prune=blocks                                     Pruned node. Only the transactions of the latest blocks are kept, the state and the block headers are kept entirely. To enable, it requires full node
+  --snapshot-import=path                             Import a state snapshot into an empty chain store and sync from its height. It requires --snapshot-import-hash and full node
+  --snapshot-import-hash=hash                        Expected hash (hex) of the imported snapshot.
+  --snapshot-export=path                             Export a state snapshot of the current height at startup. It requires full node
+  --verify-chain                                     Replay the stored blocks from genesis in memory at startup and report the first height where the state diverges. It exits afterwards with a non-zero code if the state diverges. It requires full node and no pruning
+  --store-migrate-dry-run                            Run the pending migrations of the chain store at startup without saving them, report the changed entries and exit
+  --store-migrate-backup=path                        Backup the chain store in the directory before the pending migrations change it
+  --store-restore=path                               Restore a store backup at startup. All the entries of the store are replaced by the backup
   --tcp-server-url=url                               TCP Server URL (schema, address, port, path).
   --tcp-server-port=port                             Change node tcp server port [default: 8080].
   --tcp-max-clients=limit                            Change limit of clients [default: 50].
@@ -44,14 +55,21 @@ Options:
   --wallet-import-secret-entropy=entropy             Import Wallet from a given Entropy. It will delete your existing wallet.
   --wallet-encrypt=args                              Encrypt wallet. Argument must be "password,difficulty".
   --wallet-decrypt=password                          Decrypt wallet.
+  --wallet-auto-lock=seconds                         Lock the encrypted wallet after inactivity. The keys are wiped from the memory until the wallet is unlocked. [default: 0]
   --wallet-remove-encryption                         Remove wallet encryption.
   --wallet-export-shared-staked-address=args         Derive and export Staked address. Argument must be "account,nonce,path".
+  --wallet-signer-daemon=path                        Serve the signatures of the wallet keys over a Unix socket to wallets using it as external signer.
+  --wallet-signer-daemon-keys=keys                   Comma separated base64 public keys or spend public keys which the signer daemon signs for. Every request is logged.
   --hcaptcha-secret=args                             hcaptcha Secret.
   --faucet-testnet-enabled=args                      Enable Faucet Testnet. Use "true" to enable it
   --delegator-enabled=bool                           Enable Delegator. Will allow other users to Delegate to the node. Use "true" to enable it
   --delegator-require-auth=bool                      Delegator will require authentication.
   --delegates-maximum=args                           Maximum number of Delegates
-  --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret'}]".
+  --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret', 'scopes': ['wallet-read', 'wallet-spend', 'delegator-admin', 'webhooks', 'metrics']}]". Pass can be a hash generated with --auth-hash-password. Empty scopes means all scopes.
+  --auth-token-secret=secret                         Hex secret used to sign the authentication tokens. Random by default, the tokens are invalidated after restart.
+  --auth-token-expiration=seconds                    Default expiration of the authentication tokens. [default: 3600]
+  --auth-hash-password=password                      Print the hash of a password to be used in --auth-users and exit.
+  --webhooks-allowed-hosts=hosts                     Comma separated hosts which the webhooks can use even if they resolve to loopback, link-local or private addresses.
   --light-computations                               Reduces the computations for a testnet node.
   --balance-decryptor-disable-init                   Disable first balance decryptor initialization. 
   --balance-decryptor-table-size=size                Balance Decryptor initial table size. [default: 23]
@@ -9,6 +9,7 @@ import (
 	"pandora-pay/config/config_forging"
 	"pandora-pay/config/config_nodes"
 	"runtime"
+	"strconv"
 	"time"
 )
 
@@ -28,6 +29,7 @@ var (
 const (
 	TRANSACTIONS_MAX_DATA_LENGTH = 512
 	TRANSACTIONS_ZETHER_RING_MAX = 256
+	TRANSACTIONS_MULTISIG_MAX    = 10
 )
 
 const (
@@ -46,11 +48,14 @@ const (
 )
 
 const (
-	BLOCK_MAX_SIZE          uint64 = 1024 * 1024
-	BLOCK_TIME              uint64 = 90 //seconds
-	DIFFICULTY_BLOCK_WINDOW uint64 = 10
-	FORK_MAX_UNCLE_ALLOWED  uint64 = 60
-	FORK_MAX_DOWNLOAD       uint64 = 20
+	BLOCK_MAX_SIZE                   uint64 = 1024 * 1024
+	BLOCK_TIME                       uint64 = 90 //seconds
+	DIFFICULTY_BLOCK_WINDOW          uint64 = 10
+	FORK_MAX_UNCLE_ALLOWED           uint64 = 60
+	FORK_MAX_DOWNLOAD                uint64 = 20
+	PRUNE_MAX_BLOCKS                 uint64 = 100 //maximum number of blocks pruned in one update
+	VERIFY_CHAIN_CHUNK_BLOCKS        uint64 = 100 //blocks replayed by the chain verification in one read transaction
+	CONDITIONAL_PAYMENT_DEADLINE_MAX uint64 = 100000
 )
 
 var (
@@ -65,6 +70,16 @@ var (
 	API_MEMPOOL_MAX_TRANSACTIONS = 50
 	API_ACCOUNT_MAX_TXS          = uint64(10)
 	API_ASSETS_INFO_MAX_RESULTS  = 10
+	API_EXPLORER_MAX_RESULTS     = uint64(20)
+	API_EXPLORER_MAX_DEADLINES   = uint64(1000) //heights scanned for a conditional payments page
+)
+
+var (
+	EXPLORER_SYNC_RETRY_MAX = time.Minute //backoff limit of the explorer sync retries
+)
+
+var (
+	MEMPOOL_STORED_TX_EXPIRATION = int64(7 * 24 * 60 * 60) //seconds
 )
 
 var (
@@ -76,6 +91,8 @@ var (
 
 var (
 	NODE_PROVIDE_EXTENDED_INFO_APP bool
+	NODE_EXPLORER_INDEXER          bool
+	NODE_PRUNE                     uint64            //number of latest blocks whose transactions are kept. 0 means no pruning
 	NODE_CONSENSUS                 NodeConsensusType = NODE_CONSENSUS_TYPE_FULL
 )
 
@@ -113,12 +130,28 @@ func InitConfig() (err error) {
 	}
 
 	NODE_PROVIDE_EXTENDED_INFO_APP = false
+	NODE_EXPLORER_INDEXER = false
+	NODE_PRUNE = 0
 	switch arguments.Arguments["--node-consensus"] {
 	case "full":
 		NODE_CONSENSUS = NODE_CONSENSUS_TYPE_FULL
 		if arguments.Arguments["--node-provide-extended-info-app"] == "true" {
 			NODE_PROVIDE_EXTENDED_INFO_APP = true
 		}
+		if arguments.Arguments["--node-explorer-indexer"] == "true" {
+			NODE_EXPLORER_INDEXER = true
+		}
+		if arguments.Arguments["--prune"] != nil {
+			if NODE_PRUNE, err = strconv.ParseUint(arguments.Arguments["--prune"].(string), 10, 64); err != nil {
+				return errors.New("--prune is invalid")
+			}
+			if NODE_PRUNE <= FORK_MAX_UNCLE_ALLOWED {
+				return errors.New("--prune must be greater than " + strconv.FormatUint(FORK_MAX_UNCLE_ALLOWED, 10))
+			}
+			if NODE_EXPLORER_INDEXER {
+				return errors.New("--prune can not be used together with --node-explorer-indexer")
+			}
+		}
 	case "app":
 		NODE_CONSENSUS = NODE_CONSENSUS_TYPE_APP
 	case "none":
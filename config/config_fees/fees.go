@@ -6,6 +6,24 @@ var (
 	FEE_PER_BYTE_EXTRA_SPACE = uint64(100)
 )
 
+const (
+	FEE_ESTIMATOR_BLOCKS         = 100  //recent blocks tracked by the fee estimator
+	FEE_ESTIMATOR_TARGET_MAX     = 100  //blocks
+	FEE_ESTIMATOR_TARGET_DEFAULT = 3    //blocks used by the txs builder for the automatic fees
+	FEE_ESTIMATOR_CONFIDENCE     = 0.95 //probability to be included within the target
+	FEE_ESTIMATOR_FULL_BLOCK     = 90   //percentage of the maximum block size from which the block was competitive
+	REPLACE_BY_FEE_INCREASE      = 10   //percentage by which the fee must increase to replace a pending TX_SIMPLE with the same nonce
+)
+
 func ComputeTxFee(size, feePerByte, extraSpace, feePerByeExtraSpace uint64) uint64 {
 	return size*feePerByte + extraSpace*feePerByeExtraSpace
 }
+
+// ComputeReplaceByFee returns the minimum fee required to replace a pending transaction paying fee
+func ComputeReplaceByFee(fee uint64) uint64 {
+	increase := fee * REPLACE_BY_FEE_INCREASE / 100
+	if increase == 0 {
+		increase = 1
+	}
+	return fee + increase
+}
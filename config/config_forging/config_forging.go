@@ -1,11 +1,18 @@
 package config_forging
 
-import "pandora-pay/config/arguments"
+import (
+	"pandora-pay/config/arguments"
+	"time"
+)
 
 var (
 	FORGING_ENABLED = true
 )
 
+const (
+	FORGING_REMOTE_SIGNER_TIMEOUT = 5 * time.Second
+)
+
 func InitConfig() (err error) {
 
 	if arguments.Arguments["--forging"] == false {
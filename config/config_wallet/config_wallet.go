@@ -0,0 +1,22 @@
+package config_wallet
+
+import (
+	"pandora-pay/config"
+	"time"
+)
+
+const (
+	SCHEDULED_PAYMENTS_MAX           = 1000
+	SCHEDULED_PAYMENT_ID_LENGTH      = 16
+	SCHEDULED_PAYMENTS_MAX_ATTEMPTS  = 5
+	SCHEDULED_PAYMENTS_CONFIRMATIONS = 3
+	SCHEDULED_PAYMENTS_PENDING_MAX   = 20               //blocks a payment may wait in the mempool before it is created again
+	SCHEDULED_PAYMENTS_CHAIN_DELAY   = 10 * time.Minute //payments are not created while the chain is behind
+	INVOICES_MAX                     = 10000
+	INVOICE_ID_LENGTH                = 16
+	INVOICE_EXPIRATION               = 24 * time.Hour
+	BATCH_PAYOUTS_MAX_RECIPIENTS     = 1000
+	BATCH_PAYOUTS_MAX_PAYLOADS       = 255                       //the number of payloads is serialized as a byte
+	BATCH_PAYOUTS_TX_MAX_SIZE        = config.BLOCK_MAX_SIZE / 4 //leaves room in the block for other transactions
+	WALLET_UNLOCK_MAX_TTL            = 24 * time.Hour
+)
@@ -0,0 +1,316 @@
+package explorer
+
+import (
+	"bytes"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EXPLORER_INDEX_VERSION changes whenever new entries are indexed
+const EXPLORER_INDEX_VERSION = "1"
+
+// Explorer keeps secondary indexes (assets, scripts and conditional payments by deadline) in the StoreBlockchain
+type Explorer struct {
+	chain          *blockchain.Blockchain
+	updates        []*blockchain_types.BlockchainUpdates
+	updatesReadyCn chan struct{}
+	updatesLock    *sync.Mutex
+}
+
+type explorerBlock struct {
+	Hash []byte   `msgpack:"hash"`
+	Txs  [][]byte `msgpack:"txs"`
+}
+
+func readHeight(reader store_db_interface.StoreDBTransactionInterface) (uint64, error) {
+	data := reader.Get("explorerHeight")
+	if data == nil {
+		return 0, nil
+	}
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+func (explorer *Explorer) indexBlock(writer store_db_interface.StoreDBTransactionInterface, height uint64, hash []byte, txs []*transaction.Transaction) (err error) {
+
+	block := &explorerBlock{hash, make([][]byte, len(txs))}
+	for i, tx := range txs {
+		if err = indexTx(writer, tx, height); err != nil {
+			return
+		}
+		block.Txs[i] = tx.Bloom.Hash
+	}
+
+	var data []byte
+	if data, err = msgpack.Marshal(block); err != nil {
+		return
+	}
+	writer.Put("explorerBlock:"+strconv.FormatUint(height, 10), data)
+
+	return
+}
+
+func (explorer *Explorer) unindexBlock(writer store_db_interface.StoreDBTransactionInterface, height uint64) (err error) {
+
+	key := "explorerBlock:" + strconv.FormatUint(height, 10)
+
+	data := writer.Get(key)
+	if data == nil {
+		return
+	}
+
+	block := &explorerBlock{}
+	if err = msgpack.Unmarshal(data, block); err != nil {
+		return
+	}
+
+	for _, txHash := range block.Txs {
+		if err = unindexTx(writer, txHash); err != nil {
+			return
+		}
+	}
+
+	writer.Delete(key)
+	return
+}
+
+func (explorer *Explorer) loadBlockTxs(reader store_db_interface.StoreDBTransactionInterface, height uint64) ([]*transaction.Transaction, error) {
+
+	data := reader.Get("blockTxs" + strconv.FormatUint(height, 10))
+	if data == nil {
+		return nil, errors.New("blockTxs was not found")
+	}
+
+	txHashes := [][]byte{}
+	if err := msgpack.Unmarshal(data, &txHashes); err != nil {
+		return nil, err
+	}
+
+	txs := make([]*transaction.Transaction, len(txHashes))
+	for i, txHash := range txHashes {
+
+		if data = reader.Get("tx:" + string(txHash)); data == nil {
+			return nil, errors.New("tx was not found")
+		}
+
+		txs[i] = &transaction.Transaction{}
+		if err := txs[i].Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	return txs, nil
+}
+
+// sync removes the blocks that are no longer in the chain and indexes the missing ones. It is done in batches to avoid a huge db transaction
+func (explorer *Explorer) sync() (err error) {
+
+	//the indexes of an older version are built again. Indexing a tx again is idempotent
+	if err = store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		if string(writer.Get("explorerVersion")) != EXPLORER_INDEX_VERSION {
+			writer.Put("explorerHeight", []byte("0"))
+			writer.Put("explorerVersion", []byte(EXPLORER_INDEX_VERSION))
+		}
+		return nil
+	}); err != nil {
+		return
+	}
+
+	for {
+
+		done := false
+
+		if err = store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+			var height uint64
+			if height, err = readHeight(writer); err != nil {
+				return
+			}
+
+			chainHeight := explorer.chain.GetChainData().Height
+
+			//let's remove the blocks of a fork
+			for height > 0 {
+
+				var hash []byte
+				if height <= chainHeight {
+					if hash, err = explorer.chain.LoadBlockHash(writer, height-1); err != nil {
+						return
+					}
+				}
+
+				data := writer.Get("explorerBlock:" + strconv.FormatUint(height-1, 10))
+				if data != nil && hash != nil {
+					block := &explorerBlock{}
+					if err = msgpack.Unmarshal(data, block); err != nil {
+						return
+					}
+					if bytes.Equal(block.Hash, hash) {
+						break
+					}
+				}
+
+				if err = explorer.unindexBlock(writer, height-1); err != nil {
+					return
+				}
+				height -= 1
+			}
+
+			end := height + 1000
+			if end >= chainHeight {
+				end = chainHeight
+				done = true
+			}
+
+			for ; height < end; height++ {
+
+				var hash []byte
+				if hash, err = explorer.chain.LoadBlockHash(writer, height); err != nil {
+					return
+				}
+
+				var txs []*transaction.Transaction
+				if txs, err = explorer.loadBlockTxs(writer, height); err != nil {
+					return
+				}
+
+				if err = explorer.indexBlock(writer, height, hash, txs); err != nil {
+					return
+				}
+			}
+
+			writer.Put("explorerHeight", []byte(strconv.FormatUint(height, 10)))
+			gui.GUI.Info2Update("Explorer", strconv.FormatUint(height, 10))
+
+			return
+		}); err != nil || done {
+			return
+		}
+	}
+}
+
+// syncWithRetry retries the sync with an exponential backoff until it succeeds. The updates are queued meanwhile
+func (explorer *Explorer) syncWithRetry() {
+
+	delay := time.Second
+	for {
+
+		gui.GUI.Log("Explorer syncing...")
+		err := explorer.sync()
+		if err == nil {
+			gui.GUI.Log("Explorer synced")
+			return
+		}
+
+		gui.GUI.Error("Explorer error syncing. Retrying in "+delay.String(), err)
+		time.Sleep(delay)
+
+		if delay *= 2; delay > config.EXPLORER_SYNC_RETRY_MAX {
+			delay = config.EXPLORER_SYNC_RETRY_MAX
+		}
+	}
+}
+
+func (explorer *Explorer) processUpdate(update *blockchain_types.BlockchainUpdates) error {
+	return store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var height uint64
+		if height, err = readHeight(writer); err != nil {
+			return
+		}
+
+		//the txs of the removed blocks
+		for _, txHash := range update.RemovedTxHashes {
+			if err = unindexTx(writer, txHash); err != nil {
+				return
+			}
+		}
+
+		for _, blkComplete := range update.InsertedBlocks {
+			if err = explorer.indexBlock(writer, blkComplete.Block.Height, blkComplete.Block.Bloom.Hash, blkComplete.Txs); err != nil {
+				return
+			}
+		}
+
+		//in case the new chain is shorter
+		for i := update.BlockHeight; i < height; i++ {
+			writer.Delete("explorerBlock:" + strconv.FormatUint(i, 10))
+		}
+
+		writer.Put("explorerHeight", []byte(strconv.FormatUint(update.BlockHeight, 10)))
+		gui.GUI.Info2Update("Explorer", strconv.FormatUint(update.BlockHeight, 10))
+
+		return
+	})
+}
+
+func CreateExplorer(chain *blockchain.Blockchain) *Explorer {
+
+	explorer := &Explorer{
+		chain,
+		[]*blockchain_types.BlockchainUpdates{},
+		make(chan struct{}, 1),
+		&sync.Mutex{},
+	}
+
+	//the listener is added before the sync to not miss any update. Updates already indexed are indexed again
+	updateNewChainUpdateCn := chain.UpdateNewChainUpdate.AddListener()
+
+	recovery.SafeGo(func() {
+
+		defer chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainUpdateCn)
+
+		//queued to not block the other listeners while the explorer is syncing
+		for {
+			update, ok := <-updateNewChainUpdateCn
+			if !ok {
+				return
+			}
+
+			explorer.updatesLock.Lock()
+			explorer.updates = append(explorer.updates, update)
+			explorer.updatesLock.Unlock()
+
+			select {
+			case explorer.updatesReadyCn <- struct{}{}:
+			default:
+			}
+		}
+	})
+
+	recovery.SafeGo(func() {
+
+		explorer.syncWithRetry()
+
+		for {
+			<-explorer.updatesReadyCn
+
+			explorer.updatesLock.Lock()
+			updates := explorer.updates
+			explorer.updates = []*blockchain_types.BlockchainUpdates{}
+			explorer.updatesLock.Unlock()
+
+			for _, update := range updates {
+				if err := explorer.processUpdate(update); err != nil {
+					//the sync indexes again the chain including the remaining updates
+					gui.GUI.Error("Explorer error processing update", err)
+					explorer.syncWithRetry()
+					break
+				}
+			}
+		}
+	})
+
+	return explorer
+}
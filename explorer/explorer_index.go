@@ -0,0 +1,314 @@
+package explorer
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+// an entry written by a tx in one of the indexes. It is used to remove the tx from the indexes
+type explorerEntry struct {
+	Prefix string `msgpack:"prefix"`
+	Index  uint64 `msgpack:"index"`
+}
+
+type ExplorerConditionalPayment struct {
+	Deadline     uint64 `json:"deadline" msgpack:"deadline"`
+	TxHash       []byte `json:"txHash" msgpack:"txHash"`
+	PayloadIndex byte   `json:"payloadIndex" msgpack:"payloadIndex"`
+}
+
+func assetTxsPrefix(asset []byte) string {
+	return "explorerAssetTx:" + string(asset)
+}
+
+func scriptTxsPrefix(script transaction_zether_payload_script.PayloadScriptType) string {
+	return "explorerScriptTx:" + strconv.FormatUint(uint64(script), 10)
+}
+
+func conditionalPaymentsPrefix(deadline uint64) string {
+	return "explorerCondPayment:" + strconv.FormatUint(deadline, 10)
+}
+
+// the resolution tx of a conditional payment. The resolved conditional payments are not listed
+func conditionalPaymentResolvedPrefix(txHash []byte, payloadIndex byte) string {
+	return "explorerCondPaymentResolved:" + string(txHash) + ":" + strconv.FormatUint(uint64(payloadIndex), 10)
+}
+
+func readCount(reader store_db_interface.StoreDBTransactionInterface, prefix string) (uint64, error) {
+	data := reader.Get("explorerCount:" + prefix)
+	if data == nil {
+		return 0, nil
+	}
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+func writeCount(writer store_db_interface.StoreDBTransactionInterface, prefix string, count uint64) {
+	if count == 0 {
+		writer.Delete("explorerCount:" + prefix)
+	} else {
+		writer.Put("explorerCount:"+prefix, []byte(strconv.FormatUint(count, 10)))
+	}
+}
+
+func entryKey(prefix string, index uint64) string {
+	return prefix + ":" + strconv.FormatUint(index, 10)
+}
+
+func addEntry(writer store_db_interface.StoreDBTransactionInterface, entries []*explorerEntry, prefix string, value []byte) ([]*explorerEntry, error) {
+
+	count, err := readCount(writer, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	writer.Put(entryKey(prefix, count), value)
+	writeCount(writer, prefix, count+1)
+
+	return append(entries, &explorerEntry{prefix, count}), nil
+}
+
+// unindexTx removes all the entries written by the tx. Entries that are not the last ones are left as gaps and skipped by the readers
+func unindexTx(writer store_db_interface.StoreDBTransactionInterface, txHash []byte) (err error) {
+
+	data := writer.Get("explorerTxKeys:" + string(txHash))
+	if data == nil {
+		return
+	}
+
+	entries := []*explorerEntry{}
+	if err = msgpack.Unmarshal(data, &entries); err != nil {
+		return
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+
+		entry := entries[i]
+		writer.Delete(entryKey(entry.Prefix, entry.Index))
+
+		var count uint64
+		if count, err = readCount(writer, entry.Prefix); err != nil {
+			return
+		}
+
+		//trim the gaps from the end
+		if entry.Index+1 == count {
+			for count > 0 && !writer.Exists(entryKey(entry.Prefix, count-1)) {
+				count -= 1
+			}
+			writeCount(writer, entry.Prefix, count)
+		}
+	}
+
+	writer.Delete("explorerTxKeys:" + string(txHash))
+	return
+}
+
+// indexTx is idempotent. A tx that is indexed again is first removed from the indexes
+func indexTx(writer store_db_interface.StoreDBTransactionInterface, tx *transaction.Transaction, blockHeight uint64) (err error) {
+
+	if err = unindexTx(writer, tx.Bloom.Hash); err != nil {
+		return
+	}
+
+	entries := make([]*explorerEntry, 0)
+
+	switch tx.Version {
+	case transaction_type.TX_SIMPLE:
+		if entries, err = indexTxSimple(writer, entries, tx); err != nil {
+			return
+		}
+	case transaction_type.TX_ZETHER:
+		if entries, err = indexTxZether(writer, entries, tx, blockHeight); err != nil {
+			return
+		}
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	var data []byte
+	if data, err = msgpack.Marshal(entries); err != nil {
+		return
+	}
+	writer.Put("explorerTxKeys:"+tx.Bloom.HashStr, data)
+
+	return
+}
+
+// indexTxSimple marks the conditional payment resolved by the tx
+func indexTxSimple(writer store_db_interface.StoreDBTransactionInterface, entries []*explorerEntry, tx *transaction.Transaction) ([]*explorerEntry, error) {
+
+	base := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+
+	if extra, ok := base.Extra.(*transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPayment); ok {
+		return addEntry(writer, entries, conditionalPaymentResolvedPrefix(extra.TxId, extra.PayloadIndex), tx.Bloom.Hash)
+	}
+
+	return entries, nil
+}
+
+func indexTxZether(writer store_db_interface.StoreDBTransactionInterface, entries []*explorerEntry, tx *transaction.Transaction, blockHeight uint64) (_ []*explorerEntry, err error) {
+
+	base := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+	assetsMap := make(map[string]bool)
+	scriptsMap := make(map[transaction_zether_payload_script.PayloadScriptType]bool)
+
+	addAsset := func(asset []byte) (err error) {
+		if !assetsMap[string(asset)] {
+			assetsMap[string(asset)] = true
+			entries, err = addEntry(writer, entries, assetTxsPrefix(asset), tx.Bloom.Hash)
+		}
+		return
+	}
+
+	for payloadIndex, payload := range base.Payloads {
+
+		if err = addAsset(payload.Asset); err != nil {
+			return
+		}
+
+		if !scriptsMap[payload.PayloadScript] {
+			scriptsMap[payload.PayloadScript] = true
+			if entries, err = addEntry(writer, entries, scriptTxsPrefix(payload.PayloadScript), tx.Bloom.Hash); err != nil {
+				return
+			}
+		}
+
+		switch extra := payload.Extra.(type) {
+		case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetCreate:
+			err = addAsset(extra.GetAssetId(tx.Bloom.Hash, byte(payloadIndex)))
+		case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyIncrease:
+			err = addAsset(extra.AssetId)
+		case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause:
+			err = addAsset(extra.AssetId)
+		case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze:
+			err = addAsset(extra.AssetId)
+		case *transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdateKeys:
+			err = addAsset(extra.AssetId)
+		case *transaction_zether_payload_extra.TransactionZetherPayloadExtraConditionalPayment:
+
+			deadline := blockHeight + extra.Deadline
+
+			var data []byte
+			if data, err = msgpack.Marshal(&ExplorerConditionalPayment{deadline, tx.Bloom.Hash, byte(payloadIndex)}); err != nil {
+				return
+			}
+			entries, err = addEntry(writer, entries, conditionalPaymentsPrefix(deadline), data)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	return entries, nil
+}
+
+// readTxs returns a page of an index starting from start. In case dsc is true, the page is read backwards and start is exclusive, 0 meaning from the last one.
+func readTxs(reader store_db_interface.StoreDBTransactionInterface, prefix string, start uint64, dsc bool) (count uint64, txs [][]byte, next uint64, hasNext bool, err error) {
+
+	if count, err = readCount(reader, prefix); err != nil {
+		return
+	}
+
+	if count == 0 {
+		return
+	}
+
+	txs = make([][]byte, 0)
+
+	if dsc {
+		i := count
+		if start != 0 && start < count {
+			i = start
+		}
+		for ; uint64(len(txs)) < config.API_EXPLORER_MAX_RESULTS && i > 0; i-- {
+			if data := reader.Get(entryKey(prefix, i-1)); data != nil {
+				txs = append(txs, data)
+			}
+		}
+		if i > 0 {
+			next, hasNext = i, true
+		}
+	} else {
+		i := start
+		for ; uint64(len(txs)) < config.API_EXPLORER_MAX_RESULTS && i < count; i++ {
+			if data := reader.Get(entryKey(prefix, i)); data != nil {
+				txs = append(txs, data)
+			}
+		}
+		if i < count {
+			next, hasNext = i, true
+		}
+	}
+
+	return
+}
+
+func ReadAssetTxs(reader store_db_interface.StoreDBTransactionInterface, asset []byte, start uint64, dsc bool) (uint64, [][]byte, uint64, bool, error) {
+	return readTxs(reader, assetTxsPrefix(asset), start, dsc)
+}
+
+func ReadScriptTxs(reader store_db_interface.StoreDBTransactionInterface, script transaction_zether_payload_script.PayloadScriptType, start uint64, dsc bool) (uint64, [][]byte, uint64, bool, error) {
+	return readTxs(reader, scriptTxsPrefix(script), start, dsc)
+}
+
+// ReadConditionalPayments returns the open conditional payments ordered by deadline starting from the cursor (deadline, start)
+func ReadConditionalPayments(reader store_db_interface.StoreDBTransactionInterface, deadline, start, maxDeadline uint64) (out []*ExplorerConditionalPayment, nextDeadline, next uint64, hasNext bool, err error) {
+
+	out = make([]*ExplorerConditionalPayment, 0)
+
+	for scanned := uint64(0); deadline <= maxDeadline; deadline, start = deadline+1, 0 {
+
+		if scanned == config.API_EXPLORER_MAX_DEADLINES {
+			return out, deadline, 0, true, nil
+		}
+		scanned += 1
+
+		prefix := conditionalPaymentsPrefix(deadline)
+
+		var count uint64
+		if count, err = readCount(reader, prefix); err != nil {
+			return
+		}
+
+		for i := start; i < count; i++ {
+
+			if uint64(len(out)) == config.API_EXPLORER_MAX_RESULTS {
+				return out, deadline, i, true, nil
+			}
+
+			data := reader.Get(entryKey(prefix, i))
+			if data == nil {
+				continue
+			}
+
+			payment := &ExplorerConditionalPayment{}
+			if err = msgpack.Unmarshal(data, payment); err != nil {
+				return
+			}
+
+			var resolved uint64
+			if resolved, err = readCount(reader, conditionalPaymentResolvedPrefix(payment.TxHash, payment.PayloadIndex)); err != nil {
+				return
+			}
+			if resolved > 0 {
+				continue
+			}
+
+			out = append(out, payment)
+		}
+	}
+
+	return
+}
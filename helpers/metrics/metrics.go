@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type MetricType string
+
+const (
+	METRIC_COUNTER   MetricType = "counter"
+	METRIC_GAUGE     MetricType = "gauge"
+	METRIC_HISTOGRAM MetricType = "histogram"
+)
+
+type Sample struct {
+	Suffix string
+	Labels map[string]string
+	Value  float64
+}
+
+type metric struct {
+	name     string
+	help     string
+	kind     MetricType
+	callback func() []*Sample
+}
+
+var registry = struct {
+	list map[string]*metric
+	sync.RWMutex
+}{list: make(map[string]*metric)}
+
+// Register adds a metric collected when the metrics are exported. Registering the same name again replaces it
+func Register(name, help string, kind MetricType, callback func() []*Sample) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.list[name] = &metric{name, help, kind, callback}
+}
+
+func RegisterGauge(name, help string, callback func() float64) {
+	Register(name, help, METRIC_GAUGE, func() []*Sample {
+		return []*Sample{{Value: callback()}}
+	})
+}
+
+func RegisterCounter(name, help string, callback func() float64) {
+	Register(name, help, METRIC_COUNTER, func() []*Sample {
+		return []*Sample{{Value: callback()}}
+	})
+}
+
+// RegisterHistogram buckets the values returned by the callback. The buckets must be sorted
+func RegisterHistogram(name, help string, buckets []float64, callback func() []float64) {
+	Register(name, help, METRIC_HISTOGRAM, func() []*Sample {
+
+		values := callback()
+		counts := make([]uint64, len(buckets))
+		sum := float64(0)
+
+		for _, value := range values {
+			sum += value
+			for i, bucket := range buckets {
+				if value <= bucket {
+					counts[i]++
+				}
+			}
+		}
+
+		samples := make([]*Sample, 0, len(buckets)+3)
+		for i, bucket := range buckets {
+			samples = append(samples, &Sample{"_bucket", map[string]string{"le": formatFloat(bucket)}, float64(counts[i])})
+		}
+		samples = append(samples, &Sample{"_bucket", map[string]string{"le": "+Inf"}, float64(len(values))})
+		samples = append(samples, &Sample{"_sum", nil, sum})
+		samples = append(samples, &Sample{"_count", nil, float64(len(values))})
+		return samples
+	})
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := make([]string, len(keys))
+	for i, key := range keys {
+		list[i] = key + "=" + strconv.Quote(labels[key])
+	}
+	return "{" + strings.Join(list, ",") + "}"
+}
+
+// Write exports all the metrics in the Prometheus text format
+func Write(w io.Writer) error {
+
+	registry.RLock()
+	list := make([]*metric, 0, len(registry.list))
+	for _, it := range registry.list {
+		list = append(list, it)
+	}
+	registry.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+
+	writer := bufio.NewWriter(w)
+	for _, it := range list {
+		writer.WriteString("# HELP " + it.name + " " + it.help + "\n")
+		writer.WriteString("# TYPE " + it.name + " " + string(it.kind) + "\n")
+		for _, sample := range it.callback() {
+			writer.WriteString(it.name + sample.Suffix + formatLabels(sample.Labels) + " " + formatFloat(sample.Value) + "\n")
+		}
+	}
+
+	return writer.Flush()
+}
@@ -36,6 +36,8 @@ type Mempool struct {
 	removeTransactionsCn      chan *MempoolWorkerRemoveTxs
 	insertTransactionsCn      chan *MempoolWorkerInsertTxs
 	Txs                       *MempoolTxs
+	FeeEstimator              *FeeEstimator
+	store                     *mempoolStore
 	OnBroadcastNewTransaction func([]*transaction.Transaction, bool, bool, advanced_connection_types.UUID, context.Context) []error
 }
 
@@ -155,6 +157,12 @@ func (mempool *Mempool) AddTxsToMempool(txs []*transaction.Transaction, height u
 
 	finalTxs, errs := mempool.processTxsToMempool(txs, height, ctx)
 
+	for _, finalTx := range finalTxs {
+		if finalTx != nil {
+			finalTx.Mine = justCreated
+		}
+	}
+
 	//making sure that the transaction is not inserted twice
 	if runtime.GOARCH != "wasm" {
 		for i, finalTx := range finalTxs {
@@ -233,10 +241,21 @@ func (mempool *Mempool) ContinueWork() {
 	mempool.newWorkCn <- newWork
 }
 
+func (mempool *Mempool) Close() {
+	mempool.store.flush()
+}
+
 func CreateMempool() (*Mempool, error) {
 
 	gui.GUI.Log("Mempool init...")
 
+	store, err := createMempoolStore()
+	if err != nil {
+		return nil, err
+	}
+
+	txs := createMempoolTxs(store)
+
 	mempool := &Mempool{
 		&generics.Value[*MempoolResult]{},
 		make(chan struct{}),
@@ -245,7 +264,9 @@ func CreateMempool() (*Mempool, error) {
 		make(chan *MempoolWorkerAddTx, 1000),
 		make(chan *MempoolWorkerRemoveTxs),
 		make(chan *MempoolWorkerInsertTxs),
-		createMempoolTxs(),
+		txs,
+		createFeeEstimator(txs),
+		store,
 		nil,
 	}
 
@@ -255,6 +276,7 @@ func CreateMempool() (*Mempool, error) {
 	})
 
 	mempool.initCLI()
+	mempool.initMetrics()
 
 	return mempool, nil
 }
@@ -8,6 +8,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
 	"sort"
+	"strconv"
 )
 
 type ContinueProcessingType byte
@@ -130,6 +131,18 @@ func (mempool *Mempool) GetNextTransactionsToInclude(chainHash []byte) (out []*t
 	return []*transaction.Transaction{}, nil
 }
 
+// getTxSimpleNonceKey identifies the TX_SIMPLE of an account with a given nonce. Transactions without Vin have no nonce and can not be replaced
+func getTxSimpleNonceKey(tx *transaction.Transaction) string {
+	if tx.Version != transaction_type.TX_SIMPLE {
+		return ""
+	}
+	base := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+	if !base.HasVin() {
+		return ""
+	}
+	return string(base.Vin.PublicKey) + "_" + strconv.FormatUint(base.Nonce, 10)
+}
+
 func sortTxs(txList []*mempoolTx) {
 	sort.Slice(txList, func(i, j int) bool {
 
@@ -0,0 +1,175 @@
+package mempool
+
+import (
+	"errors"
+	"math"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config"
+	"pandora-pay/config/config_fees"
+	"pandora-pay/helpers/generics"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+type FeeEstimate struct {
+	Target               uint64 `json:"target" msgpack:"target"`
+	FeePerByte           uint64 `json:"feePerByte" msgpack:"feePerByte"`
+	FeePerByteZether     uint64 `json:"feePerByteZether" msgpack:"feePerByteZether"`
+	FeePerByteExtraSpace uint64 `json:"feePerByteExtraSpace" msgpack:"feePerByteExtraSpace"`
+	History              uint64 `json:"history" msgpack:"history"` //fee per byte required by the recent blocks
+	Backlog              uint64 `json:"backlog" msgpack:"backlog"` //fee per byte required to outbid the mempool
+	Blocks               int    `json:"blocks" msgpack:"blocks"`   //recent blocks tracked
+}
+
+type feeEstimatorBlock struct {
+	height     uint64
+	feePerByte uint64 //minimum fee per byte included in a full block. Blocks that were not full required no extra fee
+}
+
+type FeeEstimator struct {
+	txs    *MempoolTxs
+	blocks []*feeEstimatorBlock
+	lock   *sync.RWMutex
+}
+
+// getTxFeePerByte returns the fee per byte in the native asset. Fees paid in other assets are converted using the FeeRate and FeeLeadingZeros of the fee liquidity
+func getTxFeePerByte(tx *transaction.Transaction) (uint64, error) {
+	fee, err := tx.GetAllFee()
+	if err != nil {
+		return 0, err
+	}
+	return fee / tx.Bloom.Size, nil
+}
+
+func isStakingTx(tx *transaction.Transaction) bool {
+	if tx.Version == transaction_type.TX_ZETHER {
+		for _, payload := range tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads {
+			if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD || payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddBlocks tracks the fees included in the new blocks. Blocks replaced by a fork are dropped
+func (estimator *FeeEstimator) AddBlocks(blocks []*block_complete.BlockComplete) {
+
+	if len(blocks) == 0 {
+		return
+	}
+
+	estimator.lock.Lock()
+	defer estimator.lock.Unlock()
+
+	for _, blk := range blocks {
+
+		for len(estimator.blocks) > 0 && estimator.blocks[len(estimator.blocks)-1].height >= blk.Height {
+			estimator.blocks = estimator.blocks[:len(estimator.blocks)-1]
+		}
+
+		size := uint64(0)
+		minFeePerByte := uint64(math.MaxUint64)
+		for _, tx := range blk.Txs {
+			if tx.Bloom == nil || isStakingTx(tx) {
+				continue
+			}
+			size += tx.Bloom.Size
+			if feePerByte, err := getTxFeePerByte(tx); err == nil && feePerByte > 0 {
+				minFeePerByte = generics.Min(minFeePerByte, feePerByte)
+			}
+		}
+
+		block := &feeEstimatorBlock{blk.Height, 0}
+		if size*100 >= config.BLOCK_MAX_SIZE*config_fees.FEE_ESTIMATOR_FULL_BLOCK && minFeePerByte != math.MaxUint64 {
+			block.feePerByte = minFeePerByte
+		}
+
+		estimator.blocks = append(estimator.blocks, block)
+	}
+
+	if len(estimator.blocks) > config_fees.FEE_ESTIMATOR_BLOCKS {
+		estimator.blocks = estimator.blocks[len(estimator.blocks)-config_fees.FEE_ESTIMATOR_BLOCKS:]
+	}
+}
+
+// historyFeePerByte returns the fee that would have been included in at least one of the target blocks with the configured confidence
+func (estimator *FeeEstimator) historyFeePerByte(target uint64) (uint64, int) {
+
+	estimator.lock.RLock()
+	list := make([]uint64, len(estimator.blocks))
+	for i, block := range estimator.blocks {
+		list[i] = block.feePerByte
+	}
+	estimator.lock.RUnlock()
+
+	if len(list) == 0 {
+		return 0, 0
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+
+	quantile := 1 - math.Pow(1-config_fees.FEE_ESTIMATOR_CONFIDENCE, 1/float64(target))
+	index := generics.Min(int(math.Ceil(quantile*float64(len(list))))-1, len(list)-1)
+	if index < 0 {
+		return 0, len(list)
+	}
+
+	return list[index], len(list)
+}
+
+// backlogFeePerByte returns the fee required to outbid the mempool transactions which don't fit in the target blocks
+func (estimator *FeeEstimator) backlogFeePerByte(target uint64) uint64 {
+
+	txs := estimator.txs.GetTxsList()
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].FeePerByte > txs[j].FeePerByte
+	})
+
+	capacity := target * config.BLOCK_MAX_SIZE
+	size := uint64(0)
+	for _, tx := range txs {
+		if size += tx.Tx.Bloom.Size; size > capacity {
+			return tx.FeePerByte + 1
+		}
+	}
+
+	return 0
+}
+
+// Estimate returns the fee per byte required to get a transaction included within the target blocks
+func (estimator *FeeEstimator) Estimate(target uint64) (*FeeEstimate, error) {
+
+	if target == 0 || target > config_fees.FEE_ESTIMATOR_TARGET_MAX {
+		return nil, errors.New("Target must be between 1 and " + strconv.Itoa(config_fees.FEE_ESTIMATOR_TARGET_MAX))
+	}
+
+	history, blocks := estimator.historyFeePerByte(target)
+	backlog := estimator.backlogFeePerByte(target)
+	market := generics.Max(history, backlog)
+
+	return &FeeEstimate{
+		target,
+		generics.Max(market, config_fees.FEE_PER_BYTE),
+		generics.Max(market, config_fees.FEE_PER_BYTE_ZETHER),
+		config_fees.FEE_PER_BYTE_EXTRA_SPACE,
+		history,
+		backlog,
+		blocks,
+	}, nil
+}
+
+func createFeeEstimator(txs *MempoolTxs) *FeeEstimator {
+	return &FeeEstimator{
+		txs,
+		[]*feeEstimatorBlock{},
+		&sync.RWMutex{},
+	}
+}
@@ -0,0 +1,25 @@
+package mempool
+
+import (
+	"pandora-pay/helpers/metrics"
+	"sync/atomic"
+)
+
+var feePerByteBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 50000, 100000}
+
+func (mempool *Mempool) initMetrics() {
+
+	metrics.RegisterGauge("pandora_mempool_txs", "Number of transactions in the mempool", func() float64 {
+		return float64(atomic.LoadInt32(&mempool.Txs.count))
+	})
+
+	metrics.RegisterHistogram("pandora_mempool_fee_per_byte", "Fee per byte of the transactions in the mempool", feePerByteBuckets, func() []float64 {
+		txs := mempool.Txs.GetTxsList()
+		out := make([]float64, len(txs))
+		for i, tx := range txs {
+			out[i] = float64(tx.FeePerByte)
+		}
+		return out
+	})
+
+}
@@ -0,0 +1,247 @@
+package mempool
+
+import (
+	"context"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"time"
+)
+
+type mempoolTxStored struct {
+	Tx    []byte `json:"tx" msgpack:"tx"`
+	Added int64  `json:"added" msgpack:"added"`
+	Mine  bool   `json:"mine" msgpack:"mine"`
+}
+
+type mempoolStoreChange struct {
+	hash string
+	tx   *mempoolTx //nil means deleted
+}
+
+type mempoolStore struct {
+	changesCn chan *mempoolStoreChange
+	flushCn   chan chan struct{}
+	hashes    map[string]bool
+}
+
+func (self *mempoolStore) stored(tx *mempoolTx) {
+	self.changesCn <- &mempoolStoreChange{tx.Tx.Bloom.HashStr, tx}
+}
+
+func (self *mempoolStore) removed(hash string) {
+	self.changesCn <- &mempoolStoreChange{hash, nil}
+}
+
+// writes the changes in a single db transaction to avoid a disk write for every mempool tx
+func (self *mempoolStore) write(changes []*mempoolStoreChange) error {
+
+	return store.StoreMempool.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if self.hashes == nil {
+			if self.hashes, err = self.readHashes(writer); err != nil {
+				return
+			}
+		}
+
+		for _, change := range changes {
+			if change.tx != nil {
+
+				var marshal []byte
+				if marshal, err = msgpack.Marshal(&mempoolTxStored{change.tx.Tx.Bloom.Serialized, change.tx.Added, change.tx.Mine}); err != nil {
+					return
+				}
+
+				writer.Put("tx:"+change.hash, marshal)
+				self.hashes[change.hash] = true
+			} else if self.hashes[change.hash] {
+				writer.Delete("tx:" + change.hash)
+				delete(self.hashes, change.hash)
+			}
+		}
+
+		list := make([]string, 0, len(self.hashes))
+		for hash := range self.hashes {
+			list = append(list, hash)
+		}
+
+		var marshal []byte
+		if marshal, err = msgpack.Marshal(list); err != nil {
+			return
+		}
+
+		writer.Put("txs", marshal)
+		return
+	})
+}
+
+func (self *mempoolStore) processing() {
+
+	for {
+
+		var changes []*mempoolStoreChange
+		var flushed chan struct{}
+
+		select {
+		case change := <-self.changesCn:
+			changes = append(changes, change)
+		case flushed = <-self.flushCn:
+		}
+
+		//let's collect all the changes that are already waiting
+	loop:
+		for {
+			select {
+			case change := <-self.changesCn:
+				changes = append(changes, change)
+			default:
+				break loop
+			}
+		}
+
+		if len(changes) > 0 {
+			if err := self.write(changes); err != nil {
+				gui.GUI.Error("Error storing mempool txs", err)
+			}
+		}
+
+		if flushed != nil {
+			close(flushed)
+		}
+	}
+}
+
+func (self *mempoolStore) flush() {
+	flushed := make(chan struct{})
+	self.flushCn <- flushed
+	<-flushed
+}
+
+func (self *mempoolStore) readHashes(reader store_db_interface.StoreDBTransactionInterface) (map[string]bool, error) {
+
+	hashes := make(map[string]bool)
+
+	data := reader.Get("txs")
+	if data == nil {
+		return hashes, nil
+	}
+
+	list := []string{}
+	if err := msgpack.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	for _, hash := range list {
+		hashes[hash] = true
+	}
+	return hashes, nil
+}
+
+func (self *mempoolStore) load() (out []*mempoolTxStored, err error) {
+
+	err = store.StoreMempool.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var hashes map[string]bool
+		if hashes, err = self.readHashes(reader); err != nil {
+			return
+		}
+
+		out = make([]*mempoolTxStored, 0, len(hashes))
+		for hash := range hashes {
+
+			data := reader.Get("tx:" + hash)
+			if data == nil {
+				continue
+			}
+
+			stored := &mempoolTxStored{}
+			if err = msgpack.Unmarshal(data, stored); err != nil {
+				return
+			}
+			out = append(out, stored)
+		}
+
+		return
+	})
+
+	return
+}
+
+// LoadStoredTxs reloads the txs saved in the StoreMempool. They are revalidated against the current chain height and the expired or invalid ones are dropped.
+func (mempool *Mempool) LoadStoredTxs(height uint64) (err error) {
+
+	stored, err := mempool.store.load()
+	if err != nil {
+		return
+	}
+
+	if len(stored) == 0 {
+		return
+	}
+
+	gui.GUI.Log("Mempool loading stored txs... " + strconv.Itoa(len(stored)))
+
+	now := time.Now().Unix()
+
+	insertTxs := make([]*mempoolTx, 0, len(stored))
+
+	for _, it := range stored {
+
+		tx := &transaction.Transaction{}
+		if err = tx.Deserialize(advanced_buffers.NewBufferReader(it.Tx)); err != nil {
+			gui.GUI.Error("Error loading stored mempool tx", err)
+			err = nil
+			continue
+		}
+
+		if !it.Mine && now-it.Added > config.MEMPOOL_STORED_TX_EXPIRATION {
+			mempool.store.removed(tx.Bloom.HashStr)
+			continue
+		}
+
+		//processed one by one to avoid an invalid tx to stop the processing of the others
+		finalTxs, errs := mempool.processTxsToMempool([]*transaction.Transaction{tx}, height, context.Background())
+		if finalTxs[0] == nil || errs[0] != nil {
+			if !mempool.Txs.Exists(tx.Bloom.HashStr) {
+				mempool.store.removed(tx.Bloom.HashStr)
+			}
+			continue
+		}
+
+		finalTxs[0].Added = it.Added
+		finalTxs[0].Mine = it.Mine
+		insertTxs = append(insertTxs, finalTxs[0])
+	}
+
+	answerCn := make(chan bool)
+	mempool.insertTransactionsCn <- &MempoolWorkerInsertTxs{insertTxs, answerCn}
+	<-answerCn
+
+	gui.GUI.Log("Mempool stored txs loaded " + strconv.Itoa(len(insertTxs)))
+
+	return
+}
+
+func createMempoolStore() (*mempoolStore, error) {
+
+	if store.StoreMempool == nil {
+		return nil, errors.New("StoreMempool was not initialized")
+	}
+
+	self := &mempoolStore{
+		make(chan *mempoolStoreChange, 1000),
+		make(chan chan struct{}),
+		nil,
+	}
+
+	recovery.SafeGo(self.processing)
+
+	return self, nil
+}
@@ -2,9 +2,12 @@ package mempool
 
 import (
 	"errors"
+	"fmt"
 	"golang.org/x/exp/slices"
 	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
 	"pandora-pay/config"
+	"pandora-pay/config/config_fees"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"sync/atomic"
@@ -51,8 +54,14 @@ func (worker *mempoolWorker) processing(
 
 	txsList := []*mempoolTx{}
 	txsMap := make(map[string]*mempoolTx)
+	txsNoncesMap := make(map[string]*mempoolTx)
 	listIndex := 0
 
+	//tx that replaced a pending tx. It is processed after the txs list is processed again
+	var replacedAddTx *MempoolWorkerAddTx
+	//pending tx evicted by replacedAddTx. It is deleted only once the replacement is valid, otherwise it is inserted back
+	var replacedTx *mempoolTx
+
 	includedTotalSize := uint64(0)
 	includedTxs := []*mempoolTx{}
 
@@ -73,6 +82,9 @@ func (worker *mempoolWorker) processing(
 	removeTxNow := func(tx *mempoolTx, txWasInserted bool, includedInBlockchainNotification bool) {
 
 		delete(txsMap, tx.Tx.Bloom.HashStr)
+		if key := getTxSimpleNonceKey(tx.Tx); key != "" && txsNoncesMap[key] == tx {
+			delete(txsNoncesMap, key)
+		}
 
 		if txWasInserted {
 			txs.deleteTx(tx.Tx.Bloom.HashStr)
@@ -81,6 +93,48 @@ func (worker *mempoolWorker) processing(
 		}
 	}
 
+	insertTxNonce := func(tx *mempoolTx) {
+		if key := getTxSimpleNonceKey(tx.Tx); key != "" {
+			txsNoncesMap[key] = tx
+		}
+	}
+
+	//a TX_SIMPLE with the same nonce and a sufficiently higher fee evicts the pending one. The included txs are processed again as the evicted tx might have been included.
+	//The evicted tx is kept in txs until the replacement is validated
+	replaceTxByFee := func(tx *mempoolTx) (bool, error) {
+
+		key := getTxSimpleNonceKey(tx.Tx)
+		if key == "" {
+			return false, nil
+		}
+
+		oldTx := txsNoncesMap[key]
+		if oldTx == nil {
+			return false, nil
+		}
+
+		oldFee := oldTx.Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Fee
+		if tx.Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Fee < config_fees.ComputeReplaceByFee(oldFee) {
+			return false, fmt.Errorf("Replacement fee is too low. It requires at least %d", config_fees.ComputeReplaceByFee(oldFee))
+		}
+
+		if index := slices.Index(txsList, oldTx); index >= 0 {
+			txsList = slices.Delete(txsList, index, index+1)
+		}
+		removeTxNow(oldTx, false, false)
+		replacedTx = oldTx
+
+		dataStorage = nil
+		includedTotalSize = uint64(0)
+		includedTxs = []*mempoolTx{}
+		listIndex = 0
+
+		atomic.StoreUint64(&work.result.totalSize, includedTotalSize)
+		work.result.txs.Store(includedTxs)
+
+		return true, nil
+	}
+
 	removeTxs := func(data *MempoolWorkerRemoveTxs) {
 
 		removedTxsMap := make(map[string]bool)
@@ -125,6 +179,7 @@ func (worker *mempoolWorker) processing(
 		for _, tx := range data.Txs {
 			if tx != nil && txsMap[tx.Tx.Bloom.HashStr] == nil {
 				txsMap[tx.Tx.Bloom.HashStr] = tx
+				insertTxNonce(tx)
 				txs.insertTx(tx)
 				txs.inserted(tx)
 				txsList = append(txsList, tx)
@@ -184,8 +239,13 @@ func (worker *mempoolWorker) processing(
 
 				tx = nil
 				newAddTx = nil
+				var oldTx *mempoolTx
 
-				if listIndex == len(txsList) {
+				if listIndex == len(txsList) && replacedAddTx != nil {
+					newAddTx, replacedAddTx = replacedAddTx, nil
+					oldTx, replacedTx = replacedTx, nil
+					tx = newAddTx.Tx
+				} else if listIndex == len(txsList) {
 					select {
 					case newWork := <-newWorkCn:
 						resetNow(newWork)
@@ -203,6 +263,15 @@ func (worker *mempoolWorker) processing(
 							}
 							continue
 						}
+						if replaced, err := replaceTxByFee(newAddTx.Tx); err != nil {
+							if newAddTx.Result != nil {
+								newAddTx.Result <- err
+							}
+							continue
+						} else if replaced {
+							replacedAddTx = newAddTx
+							continue
+						}
 						tx = newAddTx.Tx
 					}
 				} else {
@@ -266,9 +335,14 @@ func (worker *mempoolWorker) processing(
 							}
 
 							if newAddTx != nil {
+								if oldTx != nil {
+									txs.deleteTx(oldTx.Tx.Bloom.HashStr)
+									txs.deleted(oldTx, true, false)
+								}
 								listIndex += 1
 								txsList = append(txsList, newAddTx.Tx)
 								txsMap[tx.Tx.Bloom.HashStr] = newAddTx.Tx
+								insertTxNonce(tx)
 								txs.insertTx(tx)
 								txs.inserted(tx)
 							}
@@ -286,6 +360,13 @@ func (worker *mempoolWorker) processing(
 							listIndex--
 						}
 						removeTxNow(tx, newAddTx == nil, exists)
+
+						//the replacement is invalid, so the evicted tx is processed again
+						if oldTx != nil && txsMap[oldTx.Tx.Bloom.HashStr] == nil {
+							txsMap[oldTx.Tx.Bloom.HashStr] = oldTx
+							insertTxNonce(oldTx)
+							txsList = append(txsList, oldTx)
+						}
 					}
 
 				}
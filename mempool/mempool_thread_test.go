@@ -0,0 +1,106 @@
+package mempool
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/txs_validator"
+	"testing"
+)
+
+func createTestSimpleTx(t *testing.T, privateKey *addresses.PrivateKey, nonce, fee uint64) *transaction.Transaction {
+	tx, err := wizard.CreateSimpleTx(&wizard.WizardTxSimpleTransfer{
+		Extra: &wizard.WizardTxSimpleExtraUpdatePlainAccountMultisig{Threshold: 0, PublicKeys: [][]byte{}},
+		Data:  &wizard.WizardTransactionData{},
+		Fee:   &wizard.WizardTransactionFee{Fixed: fee},
+		Nonce: nonce,
+		Key:   privateKey.Key,
+	}, true, func(string) {})
+	assert.NoError(t, err)
+	return tx
+}
+
+func TestMempoolReplaceTxByFee(t *testing.T) {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive()
+
+	defer func(storeBlockchain, storeMempool *store.Store) {
+		store.StoreBlockchain = storeBlockchain
+		store.StoreMempool = storeMempool
+	}(store.StoreBlockchain, store.StoreMempool)
+
+	db, err := store_db_memory.CreateStoreDBMemory("/blockchain")
+	assert.NoError(t, err)
+	store.StoreBlockchain = &store.Store{Name: "/blockchain", Opened: true, DB: db}
+
+	db, err = store_db_memory.CreateStoreDBMemory("/mempool")
+	assert.NoError(t, err)
+	store.StoreMempool = &store.Store{Name: "/mempool", Opened: true, DB: db}
+
+	assert.NoError(t, txs_validator.NewTxsValidator())
+
+	privateKey := addresses.GenerateNewPrivateKey()
+
+	assert.NoError(t, store.StoreBlockchain.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		dataStorage := data_storage.NewDataStorage(writer)
+		plainAcc, err := dataStorage.CreatePlainAccount(privateKey.GeneratePublicKey(), false)
+		assert.NoError(t, err)
+		assert.NoError(t, plainAcc.AddUnclaimed(true, 100000))
+		assert.NoError(t, dataStorage.PlainAccs.Update(string(plainAcc.Key), plainAcc))
+		return dataStorage.CommitChanges()
+	}))
+
+	mempool, err := CreateMempool()
+	assert.NoError(t, err)
+
+	var broadcasted []*transaction.Transaction
+	mempool.OnBroadcastNewTransaction = func(txs []*transaction.Transaction, justCreated, awaitBroadcasting bool, exceptSocketUUID advanced_connection_types.UUID, ctx context.Context) []error {
+		broadcasted = append(broadcasted, txs...)
+		return make([]error, len(txs))
+	}
+
+	mempool.UpdateWork(cryptography.SHA3([]byte("Hash")), 10)
+
+	addTx := func(tx *transaction.Transaction) error {
+		return mempool.AddTxToMempool(tx, 10, true, true, false, advanced_connection_types.UUID_ALL, context.Background())
+	}
+
+	tx := createTestSimpleTx(t, privateKey, 0, 10000)
+	assert.NoError(t, addTx(tx))
+	assert.True(t, mempool.Txs.Exists(tx.Bloom.HashStr))
+	assert.Equal(t, []*transaction.Transaction{tx}, broadcasted)
+
+	//the fee bump is too small
+	tooLow := createTestSimpleTx(t, privateKey, 0, 10500)
+	assert.Error(t, addTx(tooLow), "too small fee bump should be rejected")
+	assert.False(t, mempool.Txs.Exists(tooLow.Bloom.HashStr))
+	assert.True(t, mempool.Txs.Exists(tx.Bloom.HashStr))
+	assert.Len(t, broadcasted, 1)
+
+	//the replacement can't pay its fee
+	invalid := createTestSimpleTx(t, privateKey, 0, 1000000)
+	assert.Error(t, addTx(invalid), "invalid replacement should be rejected")
+	assert.False(t, mempool.Txs.Exists(invalid.Bloom.HashStr))
+	assert.True(t, mempool.Txs.Exists(tx.Bloom.HashStr), "invalid replacement should keep the original tx")
+	assert.Len(t, broadcasted, 1)
+
+	replacement := createTestSimpleTx(t, privateKey, 0, 12000)
+	assert.NoError(t, addTx(replacement))
+	assert.True(t, mempool.Txs.Exists(replacement.Bloom.HashStr))
+	assert.False(t, mempool.Txs.Exists(tx.Bloom.HashStr), "replaced tx should be evicted")
+	assert.Equal(t, []*transaction.Transaction{tx, replacement}, broadcasted)
+
+	//the next bump is computed from the fee of the replacement
+	assert.Error(t, addTx(createTestSimpleTx(t, privateKey, 0, 13000)))
+	assert.Len(t, mempool.Txs.GetTxsList(), 1)
+}
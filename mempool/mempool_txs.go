@@ -26,6 +26,7 @@ type MempoolTxs struct {
 	count                     int32
 	txsMap                    *generics.Map[string, *mempoolTx]
 	accountsMapTxs            *generics.Map[string, *MempoolAccountTxs]
+	store                     *mempoolStore
 	UpdateMempoolTransactions *multicast.MulticastChannel[*blockchain_types.MempoolTransactionUpdate]
 }
 
@@ -33,6 +34,7 @@ func (self *MempoolTxs) insertTx(tx *mempoolTx) bool {
 	_, loaded := self.txsMap.LoadOrStore(tx.Tx.Bloom.HashStr, tx)
 	if !loaded {
 		atomic.AddInt32(&self.count, 1)
+		self.store.stored(tx)
 	}
 	return !loaded
 }
@@ -74,6 +76,7 @@ func (self *MempoolTxs) deleteTx(hashStr string) bool {
 	_, deleted := self.txsMap.LoadAndDelete(hashStr)
 	if deleted {
 		atomic.AddInt32(&self.count, -1)
+		self.store.removed(hashStr)
 	}
 	return deleted
 }
@@ -179,12 +182,13 @@ func (self *MempoolTxs) GetAccountTxs(publicKey []byte) []*mempoolTx {
 	return nil
 }
 
-func createMempoolTxs() (txs *MempoolTxs) {
+func createMempoolTxs(store *mempoolStore) (txs *MempoolTxs) {
 
 	txs = &MempoolTxs{
 		0,
 		&generics.Map[string, *mempoolTx]{},
 		&generics.Map[string, *MempoolAccountTxs]{},
+		store,
 		multicast.NewMulticastChannel[*blockchain_types.MempoolTransactionUpdate](),
 	}
 
@@ -9,10 +9,11 @@ import (
 	"pandora-pay/network/api_code/api_code_types"
 )
 
-func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values url.Values) (interface{}, error) {
+func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error, scope string) func(values url.Values) (interface{}, error) {
 	return func(values url.Values) (interface{}, error) {
 
-		authenticated := api_code_types.CheckAuthenticated(values)
+		authenticated := api_code_types.CheckAuthenticated(values, scope)
+		values.Del("token")
 		values.Del("user")
 		values.Del("pass")
 
@@ -38,21 +39,22 @@ func Handle[T any, B any](callback func(r *http.Request, args *T, reply *B) erro
 	}
 }
 
-func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
+func HandlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error, scope string) func(values io.ReadCloser, authorization *api_code_types.Authorization) (interface{}, error) {
+	return func(values io.ReadCloser, authorization *api_code_types.Authorization) (interface{}, error) {
 
 		authenticated := new(api_code_types.APIAuthenticated[T])
 		if err := json.NewDecoder(values).Decode(authenticated); err != nil {
 			return nil, err
 		}
+		authenticated.SetAuthorization(authorization)
 
 		reply := new(B)
-		return reply, callback(nil, authenticated.Data, reply, authenticated.CheckAuthenticated())
+		return reply, callback(nil, authenticated.Data, reply, authenticated.CheckAuthenticated(scope))
 	}
 }
 
-func HandlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
+func HandlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values io.ReadCloser, authorization *api_code_types.Authorization) (interface{}, error) {
+	return func(values io.ReadCloser, authorization *api_code_types.Authorization) (interface{}, error) {
 		args := new(T)
 
 		if err := json.NewDecoder(values).Decode(args); err != nil {
@@ -1,31 +1,89 @@
 package api_code_types
 
 import (
+	"net/http"
 	"net/url"
 	"pandora-pay/network/network_config/network_config_auth"
+	"strings"
 )
 
-func CheckAuthenticated(args url.Values) bool {
+// CheckAuthenticated accepts a bearer "token" or the legacy "user" and "pass"
+func CheckAuthenticated(args url.Values, scope string) bool {
 
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[args.Get("user")]
-	if user == nil {
-		return false
+	if token := args.Get("token"); token != "" {
+		return network_config_auth.CheckToken(token, scope)
 	}
 
-	return user.Password == args.Get("pass")
+	return network_config_auth.CheckCredentials(args.Get("user"), args.Get("pass"), scope)
+}
+
+// Authorization is the parsed Authorization header, either a bearer token or the basic auth of an user
+type Authorization struct {
+	Token string
+	User  string
+	Pass  string
+}
+
+// ParseAuthorization accepts only a "Bearer" token or the basic auth of an user. The schemes are case insensitive. Other headers return nil
+func ParseAuthorization(req *http.Request) *Authorization {
+
+	if username, password, ok := req.BasicAuth(); ok {
+		return &Authorization{User: username, Pass: password}
+	}
+
+	header := req.Header.Get("Authorization")
+	if len(header) <= len("Bearer ") || !strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return nil
+	}
+
+	return &Authorization{Token: header[len("Bearer "):]}
+}
+
+func (authorization *Authorization) CheckAuthenticated(scope string) bool {
+
+	if authorization.Token != "" {
+		return network_config_auth.CheckToken(authorization.Token, scope)
+	}
+
+	return network_config_auth.CheckCredentials(authorization.User, authorization.Pass, scope)
+}
+
+// SetValues injects the credentials unless the values already contain some
+func (authorization *Authorization) SetValues(values url.Values) {
+
+	if authorization == nil || values.Has("token") || values.Has("user") || values.Has("pass") {
+		return
+	}
+
+	if authorization.Token != "" {
+		values.Set("token", authorization.Token)
+	} else {
+		values.Set("user", authorization.User)
+		values.Set("pass", authorization.Pass)
+	}
 }
 
 type APIAuthenticated[T any] struct {
-	User string `json:"user" msgpack:"user"`
-	Pass string `json:"pass" msgpack:"pass"`
-	Data *T     `json:"req" msgpack:"req"`
+	Token string `json:"token,omitempty" msgpack:"token,omitempty"`
+	User  string `json:"user" msgpack:"user"`
+	Pass  string `json:"pass" msgpack:"pass"`
+	Data  *T     `json:"req" msgpack:"req"`
+}
+
+// SetAuthorization uses the credentials of the Authorization header unless the request already contains some
+func (authenticated *APIAuthenticated[T]) SetAuthorization(authorization *Authorization) {
+	if authorization != nil && authenticated.Token == "" && authenticated.User == "" && authenticated.Pass == "" {
+		authenticated.Token = authorization.Token
+		authenticated.User = authorization.User
+		authenticated.Pass = authorization.Pass
+	}
 }
 
-func (authenticated *APIAuthenticated[T]) CheckAuthenticated() bool {
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[authenticated.User]
-	if user == nil {
-		return false
+func (authenticated *APIAuthenticated[T]) CheckAuthenticated(scope string) bool {
+
+	if authenticated.Token != "" {
+		return network_config_auth.CheckToken(authenticated.Token, scope)
 	}
 
-	return user.Password == authenticated.Pass
+	return network_config_auth.CheckCredentials(authenticated.User, authenticated.Pass, scope)
 }
@@ -0,0 +1,55 @@
+package api_code_types
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestParseAuthorization(t *testing.T) {
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ParseAuthorization(req))
+
+	req.Header.Set("Authorization", "bearer token")
+	assert.Equal(t, &Authorization{Token: "token"}, ParseAuthorization(req))
+
+	req.Header.Set("Authorization", "Bearer ")
+	assert.Nil(t, ParseAuthorization(req), "empty token should fail")
+
+	req.Header.Set("Authorization", "Digest token")
+	assert.Nil(t, ParseAuthorization(req), "other scheme should fail")
+
+	req.SetBasicAuth("user", "pass")
+	assert.Equal(t, &Authorization{User: "user", Pass: "pass"}, ParseAuthorization(req))
+}
+
+func TestAuthorizationSetValues(t *testing.T) {
+
+	var authorization *Authorization
+	values := url.Values{}
+	authorization.SetValues(values)
+	assert.Empty(t, values)
+
+	authorization = &Authorization{Token: "token"}
+	authorization.SetValues(values)
+	assert.Equal(t, "token", values.Get("token"))
+
+	values = url.Values{"user": {"other"}}
+	authorization.SetValues(values)
+	assert.False(t, values.Has("token"), "the credentials of the request should be kept")
+
+	values = url.Values{}
+	(&Authorization{User: "user", Pass: "pass"}).SetValues(values)
+	assert.Equal(t, url.Values{"user": {"user"}, "pass": {"pass"}}, values)
+
+	authenticated := &APIAuthenticated[struct{}]{}
+	authenticated.SetAuthorization(&Authorization{Token: "token"})
+	assert.Equal(t, "token", authenticated.Token)
+
+	authenticated = &APIAuthenticated[struct{}]{User: "other"}
+	authenticated.SetAuthorization(&Authorization{Token: "token"})
+	assert.Equal(t, "", authenticated.Token)
+}
@@ -13,6 +13,8 @@ const (
 	SUBSCRIPTION_ASSET
 	SUBSCRIPTION_REGISTRATION
 	SUBSCRIPTION_TRANSACTION
+	SUBSCRIPTION_INVOICE
+	SUBSCRIPTION_SCHEDULED_PAYMENT
 )
 
 type APISubscriptionNotification struct {
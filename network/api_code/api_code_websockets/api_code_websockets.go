@@ -10,7 +10,7 @@ import (
 
 var SubscriptionNotifications *multicast.MulticastChannel[*api_code_types.APISubscriptionNotification]
 
-func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error, scope string) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
 	return func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
 		args := new(T)
 		if err := msgpack.Unmarshal(values, args); err != nil {
@@ -18,7 +18,7 @@ func HandleAuthenticated[T any, B any](callback func(r *http.Request, args *T, r
 		}
 
 		reply := new(B)
-		return reply, callback(nil, args, reply, conn.Authenticated.IsSet())
+		return reply, callback(nil, args, reply, conn.Authenticated.Load().Check(scope))
 	}
 }
 
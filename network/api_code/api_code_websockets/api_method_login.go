@@ -2,17 +2,25 @@ package api_code_websockets
 
 import (
 	"github.com/vmihailenco/msgpack/v5"
+	"math"
 	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/network/websocks/connection"
+	"time"
 )
 
+// APILogin authenticates the connection with a token or with user and pass.
+// A user and pass session lasts as long as the connection, unless Expiration (seconds) is set. A token session ends when the token expires
 type APILogin struct {
-	Username string `json:"user" msgpack:"user"`
-	Password string `json:"pass" msgpack:"pass"`
+	Token      string `json:"token,omitempty" msgpack:"token,omitempty"`
+	Username   string `json:"user" msgpack:"user"`
+	Password   string `json:"pass" msgpack:"pass"`
+	Expiration uint64 `json:"expiration,omitempty" msgpack:"expiration,omitempty"`
 }
 
 type APILoginReply struct {
-	Status bool `json:"status" msgpack:"status"`
+	Status     bool   `json:"status" msgpack:"status"`
+	Token      string `json:"token,omitempty" msgpack:"token,omitempty"`
+	Expiration int64  `json:"expiration,omitempty" msgpack:"expiration,omitempty"` //unix time when the session ends. Zero means when the connection is closed
 }
 
 func Login(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
@@ -22,12 +30,30 @@ func Login(conn *connection.AdvancedConnection, values []byte) (interface{}, err
 	}
 	reply := &APILoginReply{}
 
-	user := network_config_auth.CONFIG_AUTH_USERS_MAP[args.Username]
-	if user == nil || user.Password != args.Password {
-		return reply, nil
+	var token *network_config_auth.AuthToken
+	var err error
+
+	if args.Token != "" {
+		if token, err = network_config_auth.VerifyToken(args.Token); err != nil {
+			return reply, nil
+		}
+		reply.Token = args.Token
+		reply.Expiration = token.Expiration
+	} else {
+		if reply.Token, token, err = network_config_auth.CreateToken(args.Username, args.Password, nil, time.Duration(args.Expiration)*time.Second); err != nil {
+			return reply, nil
+		}
+		if args.Expiration > 0 {
+			reply.Expiration = token.Expiration
+		} else {
+			//the session doesn't expire, but it can still be revoked using the token
+			session := *token
+			session.Expiration = math.MaxInt64
+			token = &session
+		}
 	}
 
-	conn.Authenticated.Set()
+	conn.Authenticated.Store(token)
 	reply.Status = true
 
 	return reply, nil
@@ -11,6 +11,7 @@ import (
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
+	"pandora-pay/network/api_implementation/api_common/api_remote_signer"
 	"pandora-pay/wallet"
 	"time"
 )
@@ -29,6 +30,7 @@ type APICommon struct {
 	localChainSync            *generics.Value[*blockchain_sync.BlockchainSyncData]
 	Faucet                    *api_faucet.Faucet
 	DelegatorNode             *api_delegator_node.DelegatorNode
+	RemoteSigner              *api_remote_signer.RemoteSigner
 	ApiStore                  *APIStore
 	mempoolProcessedThisBlock *generics.Value[*generics.Map[string, *mempoolNewTxReply]]
 	temporaryList             *generics.Value[*APINetworkNodesReply]
@@ -81,6 +83,7 @@ func NewAPICommon(mempool *mempool.Mempool, chain *blockchain.Blockchain, wallet
 		&generics.Value[*blockchain_sync.BlockchainSyncData]{},
 		faucet,
 		delegatorNode,
+		api_remote_signer.NewRemoteSigner(wallet),
 		apiStore,
 		&generics.Value[*generics.Map[string, *mempoolNewTxReply]]{},
 		&generics.Value[*APINetworkNodesReply]{},
@@ -14,7 +14,7 @@ type ApiDelegatorNodeInfoReply struct {
 
 func (api *DelegatorNode) GetDelegatorNodeInfo(r *http.Request, args *struct{}, reply *ApiDelegatorNodeInfoReply) error {
 	reply.MaximumAllowed = config_nodes.DELEGATES_MAXIMUM
-	reply.DelegatesCount = api.wallet.GetDelegatesCount()
+	reply.DelegatesCount = api.getDelegatesCount()
 	reply.Blocks = atomic.LoadUint64(&api.chainHeight)
 	return nil
 }
@@ -28,34 +28,16 @@ type ApiDelegatorNodeNotifyReply struct {
 	Result bool `json:"result" msgpack:"result"`
 }
 
-func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNodeNotifyRequest, reply *ApiDelegatorNodeNotifyReply, authenticated bool) (err error) {
-
-	if config_nodes.DELEGATOR_REQUIRE_AUTH && !authenticated {
-		return errors.New("Invalid User or Password")
-	}
-
-	sharedStakedPrivateKey, err := addresses.NewPrivateKey(args.SharedStakedPrivateKey)
-	if err != nil {
-		return
-	}
-	sharedStakedPublicKey := sharedStakedPrivateKey.GeneratePublicKey()
+// GetStakedAccount returns the native account of a staked registration
+func GetStakedAccount(publicKey []byte) (acc *account.Account, chainHeight uint64, err error) {
 
-	addr := api.wallet.GetWalletAddressByPublicKey(sharedStakedPublicKey, true)
-	if addr != nil && addr.PrivateKey == nil {
-		reply.Result = true
-		return
-	}
-
-	var acc *account.Account
-	var chainHeight uint64
-
-	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
 		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
 		dataStorage := data_storage.NewDataStorage(reader)
 
 		var reg *registration.Registration
-		if reg, err = dataStorage.Regs.Get(string(sharedStakedPublicKey)); err != nil {
+		if reg, err = dataStorage.Regs.Get(string(publicKey)); err != nil {
 			return
 		}
 		if reg == nil {
@@ -71,7 +53,7 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 			return
 		}
 
-		if acc, err = accs.Get(string(sharedStakedPublicKey)); err != nil {
+		if acc, err = accs.Get(string(publicKey)); err != nil {
 			return
 		}
 		if acc == nil {
@@ -79,9 +61,35 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 		}
 
 		return nil
+	})
+
+	return
+}
+
+func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNodeNotifyRequest, reply *ApiDelegatorNodeNotifyReply, authenticated bool) (err error) {
 
-	}); err != nil {
-		return err
+	if config_nodes.DELEGATOR_REQUIRE_AUTH && !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	sharedStakedPrivateKey, err := addresses.NewPrivateKey(args.SharedStakedPrivateKey)
+	if err != nil {
+		return
+	}
+	sharedStakedPublicKey := sharedStakedPrivateKey.GeneratePublicKey()
+
+	addr := api.wallet.GetWalletAddressByPublicKey(sharedStakedPublicKey, true)
+	if addr != nil && addr.PrivateKey == nil {
+		reply.Result = true
+		return
+	}
+
+	//the private key replaces the remote signer
+	api.removeRemoteSigner(sharedStakedPublicKey, nil)
+
+	acc, chainHeight, err := GetStakedAccount(sharedStakedPublicKey)
+	if err != nil {
+		return
 	}
 
 	if !sharedStakedPrivateKey.TryDecryptBalance(acc.Balance.Amount, args.SharedStakedBalance) {
@@ -102,6 +110,7 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 		sharedStakedPrivateKey,
 		nil,
 		nil,
+		nil,
 		sharedStakedPublicKey,
 		true,
 		false,
@@ -111,6 +120,7 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 			sharedStakedPrivateKey,
 			sharedStakedPublicKey,
 		},
+		nil,
 		"",
 		"",
 	}, true); err != nil {
@@ -0,0 +1,129 @@
+package api_delegator_node
+
+import (
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/addresses"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/network/websocks/connection"
+)
+
+type ApiDelegatorNodeRemoteSignerNonceReply struct {
+	Nonce helpers.Base64 `json:"nonce" msgpack:"nonce"`
+}
+
+type ApiDelegatorNodeRemoteSignerRequest struct {
+	PublicKey helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	Signature helpers.Base64 `json:"signature" msgpack:"signature"` //signature of GetRemoteSignerMessage using the nonce
+}
+
+type ApiDelegatorNodeRemoteSignerReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *DelegatorNode) removeRemoteSigner(publicKey []byte, signer *delegatorRemoteSigner) {
+
+	api.remoteSignersLock.Lock()
+	current := api.remoteSigners[string(publicKey)]
+	if current == nil || (signer != nil && current != signer) {
+		api.remoteSignersLock.Unlock()
+		return
+	}
+	delete(api.remoteSigners, string(publicKey))
+	api.remoteSignersLock.Unlock()
+
+	api.wallet.RemoveRemoteSignerAddress(publicKey)
+}
+
+// DelegatorRemoteSignerNonce issues the nonce signed by the key holder in DelegatorRemoteSigner. The nonce is valid only once and only for this connection
+func (api *DelegatorNode) DelegatorRemoteSignerNonce(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+
+	nonce := helpers.RandomBytes(32)
+
+	api.remoteSignersLock.Lock()
+	_, found := api.remoteSignerNonces[conn.UUID]
+	api.remoteSignerNonces[conn.UUID] = nonce
+	api.remoteSignersLock.Unlock()
+
+	if !found {
+		recovery.SafeGo(func() {
+			<-conn.Closed
+			api.remoteSignersLock.Lock()
+			delete(api.remoteSignerNonces, conn.UUID)
+			api.remoteSignersLock.Unlock()
+		})
+	}
+
+	return &ApiDelegatorNodeRemoteSignerNonceReply{nonce}, nil
+}
+
+// DelegatorRemoteSigner stakes a delegated address without sending the private key. The node will ask the connection for the staking data while it stays open
+func (api *DelegatorNode) DelegatorRemoteSigner(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+
+	if config_nodes.DELEGATOR_REQUIRE_AUTH && !conn.Authenticated.Load().Check(network_config_auth.SCOPE_DELEGATOR_ADMIN) {
+		return nil, errors.New("Invalid User or Password")
+	}
+
+	args := &ApiDelegatorNodeRemoteSignerRequest{}
+	if err := msgpack.Unmarshal(values, args); err != nil {
+		return nil, err
+	}
+
+	//the nonce is consumed, so the signature can't be replayed
+	api.remoteSignersLock.Lock()
+	nonce := api.remoteSignerNonces[conn.UUID]
+	if nonce != nil {
+		api.remoteSignerNonces[conn.UUID] = nil
+	}
+	api.remoteSignersLock.Unlock()
+
+	if nonce == nil {
+		return nil, errors.New("Nonce was not requested")
+	}
+
+	address, err := addresses.CreateAddr(args.PublicKey, false, nil, nil, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !address.VerifySignedMessage(GetRemoteSignerMessage(args.PublicKey, nonce), args.Signature) {
+		return nil, errors.New("Invalid signature")
+	}
+
+	if addr := api.wallet.GetWalletAddressByPublicKey(args.PublicKey, true); addr != nil {
+		return nil, errors.New("Address is already delegated")
+	}
+
+	if _, _, err = GetStakedAccount(args.PublicKey); err != nil {
+		return nil, err
+	}
+
+	signer := &delegatorRemoteSigner{conn}
+	key := string(args.PublicKey)
+
+	api.remoteSignersLock.Lock()
+	if api.remoteSigners[key] != nil {
+		api.remoteSignersLock.Unlock()
+		return nil, errors.New("Remote signer is already registered. It can register again after its connection is closed")
+	}
+	if api.wallet.GetDelegatesCount()+len(api.remoteSigners) >= config_nodes.DELEGATES_MAXIMUM {
+		api.remoteSignersLock.Unlock()
+		return nil, errors.New("DELEGATES_MAXIMUM exceeded")
+	}
+	api.remoteSigners[key] = signer
+	api.remoteSignersLock.Unlock()
+
+	if err = api.wallet.AddRemoteSignerAddress(args.PublicKey, signer); err != nil {
+		api.removeRemoteSigner(args.PublicKey, signer)
+		return nil, err
+	}
+
+	recovery.SafeGo(func() {
+		<-conn.Closed
+		api.removeRemoteSigner(args.PublicKey, signer)
+	})
+
+	return &ApiDelegatorNodeRemoteSignerReply{true}, nil
+}
@@ -2,13 +2,24 @@ package api_delegator_node
 
 import (
 	"pandora-pay/blockchain"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/wallet"
+	"sync"
 )
 
 type DelegatorNode struct {
-	chainHeight uint64 //use atomic
-	wallet      *wallet.Wallet
-	chain       *blockchain.Blockchain
+	chainHeight        uint64 //use atomic
+	wallet             *wallet.Wallet
+	chain              *blockchain.Blockchain
+	remoteSigners      map[string]*delegatorRemoteSigner
+	remoteSignerNonces map[advanced_connection_types.UUID][]byte
+	remoteSignersLock  *sync.Mutex //remoteSigners and remoteSignerNonces
+}
+
+func (api *DelegatorNode) getDelegatesCount() int {
+	api.remoteSignersLock.Lock()
+	defer api.remoteSignersLock.Unlock()
+	return api.wallet.GetDelegatesCount() + len(api.remoteSigners)
 }
 
 func NewDelegatorNode(chain *blockchain.Blockchain, wallet *wallet.Wallet) (delegator *DelegatorNode) {
@@ -17,6 +28,9 @@ func NewDelegatorNode(chain *blockchain.Blockchain, wallet *wallet.Wallet) (dele
 		0,
 		wallet,
 		chain,
+		make(map[string]*delegatorRemoteSigner),
+		make(map[advanced_connection_types.UUID][]byte),
+		&sync.Mutex{},
 	}
 
 	return
@@ -0,0 +1,75 @@
+package api_delegator_node
+
+import (
+	"context"
+	"errors"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/txs_validator"
+)
+
+type ApiRemoteSignerStakingDataRequest struct {
+	PublicKey      helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	PrevKernelHash helpers.Base64 `json:"prevKernelHash" msgpack:"prevKernelHash"`
+}
+
+type ApiRemoteSignerStakingDataReply struct {
+	StakingNonce            helpers.Base64 `json:"stakingNonce" msgpack:"stakingNonce"`
+	DecryptedStakingBalance uint64         `json:"decryptedStakingBalance" msgpack:"decryptedStakingBalance"`
+}
+
+type ApiRemoteSignerForgingTxRequest struct {
+	PublicKey               helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	BlkComplete             helpers.Base64 `json:"blkComplete" msgpack:"blkComplete"` //serialized including the transactions already included
+	DecryptedStakingBalance uint64         `json:"decryptedStakingBalance" msgpack:"decryptedStakingBalance"`
+}
+
+type ApiRemoteSignerForgingTxReply struct {
+	Tx helpers.Base64 `json:"tx" msgpack:"tx"` //serialized staking reward transaction
+}
+
+// delegatorRemoteSigner forwards the forging requests to the key holder connected by websocket
+type delegatorRemoteSigner struct {
+	conn *connection.AdvancedConnection
+}
+
+// GetRemoteSignerMessage is the message signed by the key holder to prove the ownership of the staked address. The nonce is issued by the delegator node for the connection
+func GetRemoteSignerMessage(publicKey, nonce []byte) []byte {
+	message := append([]byte("delegator-node/remote-signer"), publicKey...)
+	message = append(message, nonce...)
+	return cryptography.SHA3(message)
+}
+
+func (signer *delegatorRemoteSigner) GetStakingData(publicKey, prevKernelHash []byte, ctx context.Context) (*forging.ForgingRemoteStakingData, error) {
+
+	reply, err := connection.SendJSONAwaitAnswer[ApiRemoteSignerStakingDataReply](signer.conn, []byte("remote-signer/staking-data"), &ApiRemoteSignerStakingDataRequest{publicKey, prevKernelHash}, ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &forging.ForgingRemoteStakingData{reply.StakingNonce, reply.DecryptedStakingBalance}, nil
+}
+
+func (signer *delegatorRemoteSigner) CreateForgingTransaction(publicKey []byte, blkComplete *block_complete.BlockComplete, decryptedStakingBalance uint64, ctx context.Context) (*transaction.Transaction, error) {
+
+	reply, err := connection.SendJSONAwaitAnswer[ApiRemoteSignerForgingTxReply](signer.conn, []byte("remote-signer/forging-tx"), &ApiRemoteSignerForgingTxRequest{publicKey, blkComplete.SerializeManualToBytes(), decryptedStakingBalance}, ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &transaction.Transaction{}
+	if err = tx.Deserialize(advanced_buffers.NewBufferReader(reply.Tx)); err != nil {
+		return nil, err
+	}
+
+	if err = txs_validator.TxsValidator.ValidateTx(tx); err != nil {
+		return nil, errors.New("Invalid staking reward transaction: " + err.Error())
+	}
+
+	return tx, nil
+}
@@ -47,6 +47,7 @@ func (api *Faucet) GetFaucetCoins(r *http.Request, args *APIFaucetCoinsRequest,
 			&wizard.WizardTransactionData{[]byte("Testnet Faucet Tx"), true},
 			&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, false, 0, 0},
 			nil,
+			false,
 		}},
 	}
 
@@ -0,0 +1,48 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/network/network_config/network_config_auth"
+	"time"
+)
+
+type APIAuthLoginRequest struct {
+	User       string   `json:"user" msgpack:"user"`
+	Pass       string   `json:"pass" msgpack:"pass"`
+	Scopes     []string `json:"scopes,omitempty" msgpack:"scopes,omitempty"`
+	Expiration uint64   `json:"expiration,omitempty" msgpack:"expiration,omitempty"` //seconds
+}
+
+type APIAuthLoginReply struct {
+	Token      string   `json:"token" msgpack:"token"`
+	Scopes     []string `json:"scopes" msgpack:"scopes"`
+	Expiration int64    `json:"expiration" msgpack:"expiration"`
+}
+
+type APIAuthRevokeRequest struct {
+	Token string `json:"token" msgpack:"token"`
+}
+
+type APIAuthRevokeReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) AuthLogin(r *http.Request, args *APIAuthLoginRequest, reply *APIAuthLoginReply) (err error) {
+
+	var token *network_config_auth.AuthToken
+	if reply.Token, token, err = network_config_auth.CreateToken(args.User, args.Pass, args.Scopes, time.Duration(args.Expiration)*time.Second); err != nil {
+		return
+	}
+
+	reply.Scopes = token.Scopes
+	reply.Expiration = token.Expiration
+	return
+}
+
+func (api *APICommon) AuthRevoke(r *http.Request, args *APIAuthRevokeRequest, reply *APIAuthRevokeReply) (err error) {
+	if err = network_config_auth.RevokeToken(args.Token); err != nil {
+		return
+	}
+	reply.Result = true
+	return
+}
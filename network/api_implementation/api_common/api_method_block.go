@@ -1,9 +1,11 @@
 package api_common
 
 import (
+	"errors"
 	"github.com/vmihailenco/msgpack/v5"
 	"net/http"
 	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/config"
 	"pandora-pay/helpers"
 	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/store"
@@ -39,6 +41,9 @@ func (api *APICommon) GetBlock(r *http.Request, args *APIBlockRequest, reply *AP
 
 		txHashes := [][]byte{}
 		data := reader.Get("blockTxs" + strconv.FormatUint(reply.Block.Height, 10))
+		if data == nil && config.NODE_PRUNE > 0 {
+			return errors.New("Block transactions were pruned")
+		}
 		if err = msgpack.Unmarshal(data, &txHashes); err != nil {
 			return nil
 		}
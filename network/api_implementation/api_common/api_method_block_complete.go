@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/network/api_code/api_code_types"
@@ -40,6 +41,9 @@ func (api *APICommon) GetBlockComplete(r *http.Request, args *APIBlockCompleteRe
 
 		data := reader.Get("blockTxs" + strconv.FormatUint(reply.BlockComplete.Block.Height, 10))
 		if data == nil {
+			if config.NODE_PRUNE > 0 {
+				return errors.New("Block transactions were pruned")
+			}
 			return errors.New("Strange. blockTxs was not found")
 		}
 
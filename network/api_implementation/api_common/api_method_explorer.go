@@ -0,0 +1,66 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/explorer"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIExplorerAssetTxsRequest struct {
+	Asset helpers.Base64 `json:"asset" msgpack:"asset"`
+	Start uint64         `json:"start,omitempty" msgpack:"start,omitempty"`
+	Dsc   bool           `json:"dsc,omitempty" msgpack:"dsc,omitempty"`
+}
+
+type APIExplorerScriptTxsRequest struct {
+	Script transaction_zether_payload_script.PayloadScriptType `json:"script" msgpack:"script"`
+	Start  uint64                                              `json:"start,omitempty" msgpack:"start,omitempty"`
+	Dsc    bool                                                `json:"dsc,omitempty" msgpack:"dsc,omitempty"`
+}
+
+type APIExplorerTxsReply struct {
+	Count   uint64   `json:"count,omitempty" msgpack:"count,omitempty"`
+	Txs     [][]byte `json:"txs,omitempty" msgpack:"txs,omitempty"`
+	Next    uint64   `json:"next,omitempty" msgpack:"next,omitempty"`
+	HasNext bool     `json:"hasNext,omitempty" msgpack:"hasNext,omitempty"`
+}
+
+type APIExplorerConditionalPaymentsRequest struct {
+	Deadline uint64 `json:"deadline,omitempty" msgpack:"deadline,omitempty"`
+	Start    uint64 `json:"start,omitempty" msgpack:"start,omitempty"`
+}
+
+type APIExplorerConditionalPaymentsReply struct {
+	Payments     []*explorer.ExplorerConditionalPayment `json:"payments,omitempty" msgpack:"payments,omitempty"`
+	NextDeadline uint64                                 `json:"nextDeadline,omitempty" msgpack:"nextDeadline,omitempty"`
+	Next         uint64                                 `json:"next,omitempty" msgpack:"next,omitempty"`
+	HasNext      bool                                   `json:"hasNext,omitempty" msgpack:"hasNext,omitempty"`
+}
+
+func (api *APICommon) GetExplorerAssetTxs(r *http.Request, args *APIExplorerAssetTxsRequest, reply *APIExplorerTxsReply) error {
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		reply.Count, reply.Txs, reply.Next, reply.HasNext, err = explorer.ReadAssetTxs(reader, args.Asset, args.Start, args.Dsc)
+		return
+	})
+}
+
+func (api *APICommon) GetExplorerScriptTxs(r *http.Request, args *APIExplorerScriptTxsRequest, reply *APIExplorerTxsReply) error {
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		reply.Count, reply.Txs, reply.Next, reply.HasNext, err = explorer.ReadScriptTxs(reader, args.Script, args.Start, args.Dsc)
+		return
+	})
+}
+
+func (api *APICommon) GetExplorerConditionalPayments(r *http.Request, args *APIExplorerConditionalPaymentsRequest, reply *APIExplorerConditionalPaymentsReply) error {
+
+	//deadlines are at most 100000 blocks in the future
+	maxDeadline := api.chain.GetChainData().Height + 100000
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		reply.Payments, reply.NextDeadline, reply.Next, reply.HasNext, err = explorer.ReadConditionalPayments(reader, args.Deadline, args.Start, maxDeadline)
+		return
+	})
+}
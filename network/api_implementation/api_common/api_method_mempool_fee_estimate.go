@@ -0,0 +1,30 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/config/config_fees"
+	"pandora-pay/mempool"
+)
+
+type APIMempoolFeeEstimateRequest struct {
+	Target uint64 `json:"target,omitempty" msgpack:"target,omitempty"` //blocks
+}
+
+type APIMempoolFeeEstimateReply struct {
+	mempool.FeeEstimate
+}
+
+func (api *APICommon) GetMempoolFeeEstimate(r *http.Request, args *APIMempoolFeeEstimateRequest, reply *APIMempoolFeeEstimateReply) error {
+
+	if args.Target == 0 {
+		args.Target = config_fees.FEE_ESTIMATOR_TARGET_DEFAULT
+	}
+
+	estimate, err := api.mempool.FeeEstimator.Estimate(args.Target)
+	if err != nil {
+		return err
+	}
+
+	reply.FeeEstimate = *estimate
+	return nil
+}
@@ -0,0 +1,71 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"pandora-pay/network/banned_nodes"
+	"time"
+)
+
+type APINetworkBannedReply struct {
+	Nodes []*banned_nodes.BannedNode `json:"nodes" msgpack:"nodes"`
+}
+
+type APINetworkBannedAddRequest struct {
+	URL      string `json:"url" msgpack:"url"`
+	Message  string `json:"message,omitempty" msgpack:"message,omitempty"`
+	Duration uint64 `json:"duration" msgpack:"duration"` //seconds
+}
+
+type APINetworkBannedAddReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+type APINetworkBannedRemoveRequest struct {
+	URL string `json:"url" msgpack:"url"`
+}
+
+type APINetworkBannedRemoveReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetNetworkBanned(r *http.Request, args *struct{}, reply *APINetworkBannedReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Nodes = banned_nodes.BannedNodes.GetList()
+	return nil
+}
+
+func (api *APICommon) GetNetworkBannedAdd(r *http.Request, args *APINetworkBannedAddRequest, reply *APINetworkBannedAddReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if args.Duration == 0 {
+		return errors.New("Duration must be positive")
+	}
+
+	u, err := url.Parse(args.URL)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return errors.New("Invalid url")
+	}
+
+	banned_nodes.BannedNodes.Ban(u, args.URL, args.Message, time.Duration(args.Duration)*time.Second)
+	reply.Status = true
+	return nil
+}
+
+func (api *APICommon) GetNetworkBannedRemove(r *http.Request, args *APINetworkBannedRemoveRequest, reply *APINetworkBannedRemoveReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Status = banned_nodes.BannedNodes.Unban(args.URL)
+	return nil
+}
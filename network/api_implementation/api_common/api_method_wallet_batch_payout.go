@@ -0,0 +1,42 @@
+package api_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder"
+)
+
+type APIWalletBatchPayoutRequest struct {
+	Data           *txs_builder.TxBuilderBatchPayoutData `json:"data" msgpack:"data"`
+	AwaitBroadcast bool                                  `json:"awaitBroadcast" msgpack:"awaitBroadcast"`
+}
+
+type APIWalletBatchPayoutReply struct {
+	Txs        []helpers.Base64                                   `json:"txs" msgpack:"txs"`
+	Recipients []*txs_builder.TxBuilderBatchPayoutRecipientResult `json:"recipients" msgpack:"recipients"`
+}
+
+func (api *APICommon) WalletBatchPayout(r *http.Request, args *APIWalletBatchPayoutRequest, reply *APIWalletBatchPayoutReply, authenticated bool) error {
+
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+	if args.Data == nil {
+		return errors.New("Data is missing")
+	}
+
+	txs, recipients, err := txs_builder.TxsBuilder.CreateZetherBatchPayout(args.Data, args.AwaitBroadcast, context.Background(), func(string) {})
+	if err != nil {
+		return err
+	}
+
+	reply.Txs = make([]helpers.Base64, len(txs))
+	for i, tx := range txs {
+		reply.Txs[i] = tx.Bloom.Hash
+	}
+	reply.Recipients = recipients
+
+	return nil
+}
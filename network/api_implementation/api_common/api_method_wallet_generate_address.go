@@ -3,12 +3,8 @@ package api_common
 import (
 	"errors"
 	"net/http"
-	"pandora-pay/addresses"
-	"pandora-pay/blockchain/data_storage"
 	"pandora-pay/helpers"
 	"pandora-pay/network/api_implementation/api_common/api_types"
-	"pandora-pay/store"
-	"pandora-pay/store/store_db/store_db_interface"
 )
 
 type APIWalletGenerateAddressRequest struct {
@@ -38,29 +34,11 @@ func (api *APICommon) GetWalletGenerateAddress(r *http.Request, args *APIWalletG
 		return errors.New("address doesn't exist in your waallet")
 	}
 
-	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
-
-		dataStorage := data_storage.NewDataStorage(reader)
-
-		var isReg bool
-		if isReg, err = dataStorage.Regs.Exists(string(publicKey)); err != nil {
-			return
-		}
-
-		var addr *addresses.Address
-
-		if !isReg {
-			addr, err = walletAddr.PrivateKey.GenerateAddress(walletAddr.Staked, walletAddr.SpendPublicKey, true, args.PaymentID, args.PaymentAmount, args.PaymentAsset)
-		} else {
-			addr, err = walletAddr.PrivateKey.GenerateAddress(false, nil, false, args.PaymentID, args.PaymentAmount, args.PaymentAsset)
-		}
-		if err != nil {
-			return
-		}
-
-		reply.Address = addr.EncodeAddr()
-
-		return nil
-	})
+	addr, err := api.wallet.GenerateIntegratedAddress(walletAddr, args.PaymentID, args.PaymentAmount, args.PaymentAsset)
+	if err != nil {
+		return
+	}
 
+	reply.Address = addr.EncodeAddr()
+	return
 }
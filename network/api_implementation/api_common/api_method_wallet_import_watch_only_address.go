@@ -0,0 +1,43 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/addresses"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/wallet/wallet_address"
+)
+
+type APIWalletImportWatchOnlyAddressRequest struct {
+	Name    string `json:"name" msgpack:"name"`
+	Address string `json:"address" msgpack:"address"`
+	ViewKey []byte `json:"viewKey" msgpack:"viewKey"`
+}
+
+type APIWalletImportWatchOnlyAddressReply struct {
+	Address *wallet_address.WalletAddress `json:"address" msgpack:"address"`
+}
+
+func (api *APICommon) WalletImportWatchOnlyAddress(r *http.Request, args *APIWalletImportWatchOnlyAddressRequest, reply *APIWalletImportWatchOnlyAddressReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	address, err := addresses.DecodeAddr(args.Address)
+	if err != nil {
+		return err
+	}
+
+	viewKey, err := addresses.NewPrivateKey(args.ViewKey)
+	if err != nil {
+		return err
+	}
+
+	addr, err := api.wallet.ImportWatchOnlyAddress(args.Name, address, viewKey)
+	if err != nil {
+		return err
+	}
+
+	reply.Address, err = generics.Clone[*wallet_address.WalletAddress](addr, new(wallet_address.WalletAddress))
+	return err
+}
@@ -0,0 +1,73 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/wallet/invoices"
+	"time"
+)
+
+type APIWalletInvoicesRequest struct {
+	Id helpers.Base64 `json:"id,omitempty" msgpack:"id,omitempty"` //empty returns all the invoices
+}
+
+type APIWalletInvoicesReply struct {
+	Invoices []*invoices.Invoice `json:"invoices" msgpack:"invoices"`
+}
+
+type APIWalletInvoiceCreateRequest struct {
+	Address     string         `json:"address" msgpack:"address"`
+	Asset       helpers.Base64 `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	Amount      uint64         `json:"amount,omitempty" msgpack:"amount,omitempty"`
+	Description string         `json:"description,omitempty" msgpack:"description,omitempty"`
+	Expiration  uint64         `json:"expiration,omitempty" msgpack:"expiration,omitempty"` //seconds
+}
+
+type APIWalletInvoiceCreateReply struct {
+	Invoice *invoices.Invoice `json:"invoice" msgpack:"invoice"`
+}
+
+type APIWalletInvoiceRemoveRequest struct {
+	Id helpers.Base64 `json:"id" msgpack:"id"`
+}
+
+type APIWalletInvoiceRemoveReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetWalletInvoices(r *http.Request, args *APIWalletInvoicesRequest, reply *APIWalletInvoicesReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if len(args.Id) == 0 {
+		reply.Invoices = invoices.Invoices.GetList()
+		return nil
+	}
+
+	invoice := invoices.Invoices.Get(args.Id)
+	if invoice == nil {
+		return errors.New("Invoice was not found")
+	}
+	reply.Invoices = []*invoices.Invoice{invoice}
+	return nil
+}
+
+func (api *APICommon) WalletInvoiceCreate(r *http.Request, args *APIWalletInvoiceCreateRequest, reply *APIWalletInvoiceCreateReply, authenticated bool) (err error) {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Invoice, err = invoices.Invoices.Create(args.Address, args.Asset, args.Amount, args.Description, time.Duration(args.Expiration)*time.Second)
+	return
+}
+
+func (api *APICommon) WalletInvoiceRemove(r *http.Request, args *APIWalletInvoiceRemoveRequest, reply *APIWalletInvoiceRemoveReply, authenticated bool) (err error) {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Status, err = invoices.Invoices.Remove(args.Id)
+	return
+}
@@ -0,0 +1,64 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder"
+	"pandora-pay/wallet/scheduled_payments"
+)
+
+type APIWalletScheduledPaymentsReply struct {
+	Payments []*scheduled_payments.ScheduledPayment `json:"payments" msgpack:"payments"`
+}
+
+type APIWalletScheduledPaymentAddRequest struct {
+	Sender            string                                          `json:"sender" msgpack:"sender"`
+	Recipient         string                                          `json:"recipient" msgpack:"recipient"`
+	Asset             helpers.Base64                                  `json:"asset" msgpack:"asset"`
+	Amount            uint64                                          `json:"amount" msgpack:"amount"`
+	IntervalType      scheduled_payments.ScheduledPaymentIntervalType `json:"intervalType" msgpack:"intervalType"`
+	Interval          uint64                                          `json:"interval" msgpack:"interval"`
+	Next              uint64                                          `json:"next" msgpack:"next"`
+	RingSize          int                                             `json:"ringSize" msgpack:"ringSize"` //-1 for random
+	RingConfiguration *txs_builder.ZetherRingConfiguration            `json:"ringConfiguration" msgpack:"ringConfiguration"`
+}
+
+type APIWalletScheduledPaymentAddReply struct {
+	Payment *scheduled_payments.ScheduledPayment `json:"payment" msgpack:"payment"`
+}
+
+type APIWalletScheduledPaymentRemoveRequest struct {
+	Id helpers.Base64 `json:"id" msgpack:"id"`
+}
+
+type APIWalletScheduledPaymentRemoveReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetWalletScheduledPayments(r *http.Request, args *struct{}, reply *APIWalletScheduledPaymentsReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Payments = scheduled_payments.ScheduledPayments.GetList()
+	return nil
+}
+
+func (api *APICommon) WalletScheduledPaymentAdd(r *http.Request, args *APIWalletScheduledPaymentAddRequest, reply *APIWalletScheduledPaymentAddReply, authenticated bool) (err error) {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Payment, err = scheduled_payments.ScheduledPayments.Add(args.Sender, args.Recipient, args.Asset, args.Amount, args.IntervalType, args.Interval, args.Next, args.RingSize, args.RingConfiguration)
+	return
+}
+
+func (api *APICommon) WalletScheduledPaymentRemove(r *http.Request, args *APIWalletScheduledPaymentRemoveRequest, reply *APIWalletScheduledPaymentRemoveReply, authenticated bool) (err error) {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Status, err = scheduled_payments.ScheduledPayments.Remove(args.Id)
+	return
+}
@@ -0,0 +1,29 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+type APIWalletUnlockRequest struct {
+	Password string `json:"password" msgpack:"password"`
+	TTL      uint64 `json:"ttl" msgpack:"ttl"` //seconds of inactivity until the wallet is locked again. Zero uses --wallet-auto-lock
+}
+
+type APIWalletUnlockReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (api *APICommon) WalletUnlock(r *http.Request, args *APIWalletUnlockRequest, reply *APIWalletUnlockReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if err := api.wallet.Encryption.Unlock(args.Password, time.Duration(args.TTL)*time.Second); err != nil {
+		return err
+	}
+
+	reply.Result = true
+	return nil
+}
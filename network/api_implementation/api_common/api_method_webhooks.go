@@ -0,0 +1,62 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/webhooks"
+)
+
+type APIWebhooksReply struct {
+	Webhooks []*webhooks.Webhook `json:"webhooks" msgpack:"webhooks"`
+}
+
+type APIWebhooksRegisterRequest struct {
+	Type api_code_types.SubscriptionType `json:"type" msgpack:"type"`
+	Key  helpers.Base64                  `json:"key" msgpack:"key"`
+	URL  string                          `json:"url" msgpack:"url"`
+}
+
+type APIWebhooksRegisterReply struct {
+	Webhook *webhooks.Webhook `json:"webhook" msgpack:"webhook"`
+	Secret  helpers.Base64    `json:"secret" msgpack:"secret"` //used to verify the X-Pandora-Signature header
+}
+
+type APIWebhooksUnregisterRequest struct {
+	Id helpers.Base64 `json:"id" msgpack:"id"`
+}
+
+type APIWebhooksUnregisterReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) GetWebhooks(r *http.Request, args *struct{}, reply *APIWebhooksReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Webhooks = webhooks.Webhooks.GetList()
+	return nil
+}
+
+func (api *APICommon) GetWebhooksRegister(r *http.Request, args *APIWebhooksRegisterRequest, reply *APIWebhooksRegisterReply, authenticated bool) (err error) {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	if reply.Webhook, err = webhooks.Webhooks.Register(args.Type, args.Key, args.URL); err != nil {
+		return
+	}
+	reply.Secret = reply.Webhook.Secret
+	return
+}
+
+func (api *APICommon) GetWebhooksUnregister(r *http.Request, args *APIWebhooksUnregisterRequest, reply *APIWebhooksUnregisterReply, authenticated bool) error {
+	if !authenticated {
+		return errors.New("Invalid User or Password")
+	}
+
+	reply.Status = webhooks.Webhooks.Unregister(args.Id)
+	return nil
+}
@@ -0,0 +1,85 @@
+package api_remote_signer
+
+import (
+	"context"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/blocks/block_complete"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forging"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/txs_builder"
+)
+
+// StakingData answers the delegator node with the staking nonce and the decrypted staking balance of an address registered on this connection
+func (api *RemoteSigner) StakingData(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+
+	args := &api_delegator_node.ApiRemoteSignerStakingDataRequest{}
+	if err := msgpack.Unmarshal(values, args); err != nil {
+		return nil, err
+	}
+
+	if !api.hasPublicKey(conn, args.PublicKey) {
+		return nil, errors.New("Address was not registered on this connection")
+	}
+
+	addr := api.wallet.GetWalletAddressByPublicKey(args.PublicKey, true)
+	if addr == nil || addr.PrivateKey == nil {
+		return nil, errors.New("Private Key is missing")
+	}
+
+	acc, _, err := api_delegator_node.GetStakedAccount(args.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config_forging.FORGING_REMOTE_SIGNER_TIMEOUT)
+	defer cancel()
+
+	balance, err := api.wallet.DecryptBalance(addr, acc.Balance.Amount.Serialize(), config_coins.NATIVE_ASSET_FULL, false, 0, true, ctx, func(string) {})
+	if err != nil {
+		return nil, err
+	}
+
+	keyPoint := new(crypto.BNRed).SetBytes(addr.PrivateKey.Key)
+
+	return &api_delegator_node.ApiRemoteSignerStakingDataReply{
+		StakingNonce:            forging.ComputeStakingNonce(keyPoint.BigInt(), args.PrevKernelHash),
+		DecryptedStakingBalance: balance,
+	}, nil
+}
+
+// ForgingTx creates the staking reward transaction of the block forged by the delegator node for an address registered on this connection
+func (api *RemoteSigner) ForgingTx(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+
+	args := &api_delegator_node.ApiRemoteSignerForgingTxRequest{}
+	if err := msgpack.Unmarshal(values, args); err != nil {
+		return nil, err
+	}
+
+	if !api.hasPublicKey(conn, args.PublicKey) {
+		return nil, errors.New("Address was not registered on this connection")
+	}
+
+	blkComplete := block_complete.CreateEmptyBlockComplete()
+	if err := blkComplete.Deserialize(advanced_buffers.NewBufferReader(args.BlkComplete)); err != nil {
+		return nil, err
+	}
+
+	txs := blkComplete.Txs
+	if txs == nil {
+		txs = []*transaction.Transaction{}
+	}
+
+	tx, err := txs_builder.TxsBuilder.CreateForgingTransactions(blkComplete, args.PublicKey, args.DecryptedStakingBalance, txs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api_delegator_node.ApiRemoteSignerForgingTxReply{Tx: tx.SerializeManualToBytes()}, nil
+}
@@ -0,0 +1,130 @@
+package api_remote_signer
+
+import (
+	"context"
+	"errors"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/api_code/api_code_websockets"
+	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/websocks"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/network/websocks/websock"
+	"pandora-pay/wallet"
+	"sync"
+)
+
+// remoteSignerSession is a connection to a delegator node and the addresses staked by it
+type remoteSignerSession struct {
+	url        string
+	publicKeys map[string]bool
+}
+
+// RemoteSigner is the key holder of the delegated stakes. It registers the addresses to the delegator nodes and answers their forging requests, so the private keys never leave the wallet
+type RemoteSigner struct {
+	wallet   *wallet.Wallet
+	sessions map[advanced_connection_types.UUID]*remoteSignerSession
+	lock     *sync.Mutex
+}
+
+func (api *RemoteSigner) hasPublicKey(conn *connection.AdvancedConnection, publicKey []byte) bool {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+	session := api.sessions[conn.UUID]
+	return session != nil && session.publicKeys[string(publicKey)]
+}
+
+func (api *RemoteSigner) getConnection(url string) (*connection.AdvancedConnection, error) {
+
+	if conn, ok := connected_nodes.ConnectedNodes.AllAddresses.Load(url); ok {
+		return conn, nil
+	}
+
+	c, err := websock.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return websocks.Websockets.NewConnection(c, url, nil, false)
+}
+
+// Register proves the ownership of the address to the delegator node at url and keeps answering its forging requests while the connection is open.
+// The token is used to login when the delegator node requires authentication
+func (api *RemoteSigner) Register(url string, publicKey []byte, token string, ctx context.Context) error {
+
+	addr := api.wallet.GetWalletAddressByPublicKey(publicKey, true)
+	if addr == nil {
+		return errors.New("Address was not found")
+	}
+	if addr.PrivateKey == nil {
+		return errors.New("Private Key is missing. The wallet may be locked")
+	}
+
+	conn, err := api.getConnection(url)
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		login, err := connection.SendJSONAwaitAnswer[api_code_websockets.APILoginReply](conn, []byte("login"), &api_code_websockets.APILogin{Token: token}, ctx, 0)
+		if err != nil {
+			return err
+		}
+		if !login.Status {
+			return errors.New("Token was rejected")
+		}
+	}
+
+	nonce, err := connection.SendJSONAwaitAnswer[api_delegator_node.ApiDelegatorNodeRemoteSignerNonceReply](conn, []byte("delegator-node/remote-signer/nonce"), nil, ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	signature, err := addr.PrivateKey.Sign(api_delegator_node.GetRemoteSignerMessage(publicKey, nonce.Nonce))
+	if err != nil {
+		return err
+	}
+
+	//the delegator node may ask for the staking data right after the registration
+	api.lock.Lock()
+	session := api.sessions[conn.UUID]
+	if session == nil {
+		session = &remoteSignerSession{url, make(map[string]bool)}
+		api.sessions[conn.UUID] = session
+		recovery.SafeGo(func() {
+			<-conn.Closed
+			api.lock.Lock()
+			delete(api.sessions, conn.UUID)
+			api.lock.Unlock()
+		})
+	}
+	session.publicKeys[string(publicKey)] = true
+	api.lock.Unlock()
+
+	reply, err := connection.SendJSONAwaitAnswer[api_delegator_node.ApiDelegatorNodeRemoteSignerReply](conn, []byte("delegator-node/remote-signer"), &api_delegator_node.ApiDelegatorNodeRemoteSignerRequest{PublicKey: publicKey, Signature: signature}, ctx, 0)
+	if err == nil && !reply.Result {
+		err = errors.New("Registration was rejected")
+	}
+	if err != nil {
+		api.lock.Lock()
+		delete(session.publicKeys, string(publicKey))
+		api.lock.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func NewRemoteSigner(wallet *wallet.Wallet) *RemoteSigner {
+
+	api := &RemoteSigner{
+		wallet,
+		make(map[advanced_connection_types.UUID]*remoteSignerSession),
+		&sync.Mutex{},
+	}
+
+	api.initCLI()
+
+	return api
+}
@@ -0,0 +1,28 @@
+package api_remote_signer
+
+import (
+	"context"
+	"pandora-pay/gui"
+)
+
+func (api *RemoteSigner) cliRemoteSign(cmd string, ctx context.Context) (err error) {
+
+	addr, _, _, err := api.wallet.CliSelectAddress("Select Address to be staked by the delegator node", ctx)
+	if err != nil {
+		return
+	}
+
+	url := gui.GUI.OutputReadString("Delegator node websocket url")
+	token := gui.GUI.OutputReadString("Auth token of the delegator node. Leave empty for none")
+
+	if err = api.Register(url, addr.PublicKey, token, ctx); err != nil {
+		return
+	}
+
+	gui.GUI.OutputWrite("Address is staked by the delegator node while the connection is open")
+	return
+}
+
+func (api *RemoteSigner) initCLI() {
+	gui.GUI.CommandDefineCallback("Remote Sign Delegated Stake", api.cliRemoteSign, true)
+}
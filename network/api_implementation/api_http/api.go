@@ -8,15 +8,17 @@ import (
 	"pandora-pay/blockchain/info"
 	"pandora-pay/config"
 	"pandora-pay/network/api_code/api_code_http"
+	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/api_implementation/api_common/api_delegator_node"
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 )
 
 type API struct {
 	GetMap    map[string]func(values url.Values) (interface{}, error)
-	PostMap   map[string]func(values io.ReadCloser) (interface{}, error)
+	PostMap   map[string]func(values io.ReadCloser, authorization *api_code_types.Authorization) (interface{}, error)
 	chain     *blockchain.Blockchain
 	apiCommon *api_common.APICommon
 	apiStore  *api_common.APIStore
@@ -58,19 +60,37 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		"asset/exists":            api_code_http.Handle[api_common.APIAssetExistsRequest, api_common.APIAssetExistsReply](api.apiCommon.GetAssetExists),
 		"asset/fee-liquidity":     api_code_http.Handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
 		"mempool":                 api_code_http.Handle[api_common.APIMempoolRequest, api_common.APIMempoolReply](api.apiCommon.GetMempool),
+		"mempool/fee-estimate":    api_code_http.Handle[api_common.APIMempoolFeeEstimateRequest, api_common.APIMempoolFeeEstimateReply](api.apiCommon.GetMempoolFeeEstimate),
 		"mempool/tx-exists":       api_code_http.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
 		"mempool/new-tx":          api_code_http.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
 		"network/nodes":           api_code_http.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    api_code_http.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
-		"wallet/generate-address": api_code_http.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
-		"wallet/create-address":   api_code_http.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
-		"wallet/delete-address":   api_code_http.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
-		"wallet/get-balances":     api_code_http.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
-		"wallet/decrypt-tx":       api_code_http.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
+		"network/banned":          api_code_http.HandleAuthenticated[struct{}, api_common.APINetworkBannedReply](api.apiCommon.GetNetworkBanned, network_config_auth.SCOPE_DELEGATOR_ADMIN),
+		"network/banned/add":      api_code_http.HandleAuthenticated[api_common.APINetworkBannedAddRequest, api_common.APINetworkBannedAddReply](api.apiCommon.GetNetworkBannedAdd, network_config_auth.SCOPE_DELEGATOR_ADMIN),
+		"network/banned/remove":   api_code_http.HandleAuthenticated[api_common.APINetworkBannedRemoveRequest, api_common.APINetworkBannedRemoveReply](api.apiCommon.GetNetworkBannedRemove, network_config_auth.SCOPE_DELEGATOR_ADMIN),
+		"webhooks":                api_code_http.HandleAuthenticated[struct{}, api_common.APIWebhooksReply](api.apiCommon.GetWebhooks, network_config_auth.SCOPE_WEBHOOKS),
+		"webhooks/register":       api_code_http.HandleAuthenticated[api_common.APIWebhooksRegisterRequest, api_common.APIWebhooksRegisterReply](api.apiCommon.GetWebhooksRegister, network_config_auth.SCOPE_WEBHOOKS),
+		"webhooks/unregister":     api_code_http.HandleAuthenticated[api_common.APIWebhooksUnregisterRequest, api_common.APIWebhooksUnregisterReply](api.apiCommon.GetWebhooksUnregister, network_config_auth.SCOPE_WEBHOOKS),
+		"wallet/get-addresses":    api_code_http.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/generate-address": api_code_http.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/create-address":   api_code_http.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/delete-address":   api_code_http.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/get-balances":     api_code_http.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/decrypt-tx":       api_code_http.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/scheduled":        api_code_http.HandleAuthenticated[struct{}, api_common.APIWalletScheduledPaymentsReply](api.apiCommon.GetWalletScheduledPayments, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/invoices":         api_code_http.HandleAuthenticated[api_common.APIWalletInvoicesRequest, api_common.APIWalletInvoicesReply](api.apiCommon.GetWalletInvoices, network_config_auth.SCOPE_WALLET_READ),
 	}
 
-	api.PostMap = map[string]func(values io.ReadCloser) (interface{}, error){
-		"wallet/private-transfer": api_code_http.HandlePOSTAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+	api.PostMap = map[string]func(values io.ReadCloser, authorization *api_code_types.Authorization) (interface{}, error){
+		"auth/login":              api_code_http.HandlePOST[api_common.APIAuthLoginRequest, api_common.APIAuthLoginReply](api.apiCommon.AuthLogin),
+		"auth/revoke":             api_code_http.HandlePOST[api_common.APIAuthRevokeRequest, api_common.APIAuthRevokeReply](api.apiCommon.AuthRevoke),
+		"wallet/private-transfer": api_code_http.HandlePOSTAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/import-view-key":  api_code_http.HandlePOSTAuthenticated[api_common.APIWalletImportWatchOnlyAddressRequest, api_common.APIWalletImportWatchOnlyAddressReply](api.apiCommon.WalletImportWatchOnlyAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/scheduled/add":    api_code_http.HandlePOSTAuthenticated[api_common.APIWalletScheduledPaymentAddRequest, api_common.APIWalletScheduledPaymentAddReply](api.apiCommon.WalletScheduledPaymentAdd, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/scheduled/remove": api_code_http.HandlePOSTAuthenticated[api_common.APIWalletScheduledPaymentRemoveRequest, api_common.APIWalletScheduledPaymentRemoveReply](api.apiCommon.WalletScheduledPaymentRemove, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/invoices/create":  api_code_http.HandlePOSTAuthenticated[api_common.APIWalletInvoiceCreateRequest, api_common.APIWalletInvoiceCreateReply](api.apiCommon.WalletInvoiceCreate, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/invoices/remove":  api_code_http.HandlePOSTAuthenticated[api_common.APIWalletInvoiceRemoveRequest, api_common.APIWalletInvoiceRemoveReply](api.apiCommon.WalletInvoiceRemove, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/batch-payout":     api_code_http.HandlePOSTAuthenticated[api_common.APIWalletBatchPayoutRequest, api_common.APIWalletBatchPayoutReply](api.apiCommon.WalletBatchPayout, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/unlock":           api_code_http.HandlePOSTAuthenticated[api_common.APIWalletUnlockRequest, api_common.APIWalletUnlockReply](api.apiCommon.WalletUnlock, network_config_auth.SCOPE_WALLET_SPEND),
 	}
 
 	if config.NODE_PROVIDE_EXTENDED_INFO_APP {
@@ -83,6 +103,12 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		api.GetMap["account/mempool-nonce"] = api_code_http.Handle[api_common.APIAccountMempoolNonceRequest, api_common.APIAccountMempoolNonceReply](api.apiCommon.GetAccountMempoolNonce)
 	}
 
+	if config.NODE_EXPLORER_INDEXER {
+		api.GetMap["explorer/asset-txs"] = api_code_http.Handle[api_common.APIExplorerAssetTxsRequest, api_common.APIExplorerTxsReply](api.apiCommon.GetExplorerAssetTxs)
+		api.GetMap["explorer/script-txs"] = api_code_http.Handle[api_common.APIExplorerScriptTxsRequest, api_common.APIExplorerTxsReply](api.apiCommon.GetExplorerScriptTxs)
+		api.GetMap["explorer/conditional-payments"] = api_code_http.Handle[api_common.APIExplorerConditionalPaymentsRequest, api_common.APIExplorerConditionalPaymentsReply](api.apiCommon.GetExplorerConditionalPayments)
+	}
+
 	if api.apiCommon.Faucet != nil {
 		api.GetMap["faucet/info"] = api_code_http.Handle[struct{}, api_faucet.APIFaucetInfo](api.apiCommon.Faucet.GetFaucetInfo)
 		if network_config.FAUCET_TESTNET_ENABLED {
@@ -92,7 +118,7 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 
 	if api.apiCommon.DelegatorNode != nil {
 		api.GetMap["delegator-node/info"] = api_code_http.Handle[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply](api.apiCommon.DelegatorNode.GetDelegatorNodeInfo)
-		api.GetMap["delegator-node/notify"] = api_code_http.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
+		api.GetMap["delegator-node/notify"] = api_code_http.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify, network_config_auth.SCOPE_DELEGATOR_ADMIN)
 	}
 
 	if ConfigureAPIRoutes != nil {
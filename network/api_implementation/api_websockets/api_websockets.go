@@ -12,6 +12,7 @@ import (
 	"pandora-pay/network/api_implementation/api_common/api_faucet"
 	"pandora-pay/network/api_implementation/api_websockets/consensus"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/settings"
 )
@@ -66,16 +67,32 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		"asset/exists":            api_code_websockets.Handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
 		"asset/fee-liquidity":     api_code_websockets.Handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
 		"mempool":                 api_code_websockets.Handle[api_common.APIMempoolRequest, api_common.APIMempoolReply](api.apiCommon.GetMempool),
+		"mempool/fee-estimate":    api_code_websockets.Handle[api_common.APIMempoolFeeEstimateRequest, api_common.APIMempoolFeeEstimateReply](api.apiCommon.GetMempoolFeeEstimate),
 		"mempool/tx-exists":       api_code_websockets.Handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
 		"mempool/new-tx":          api_code_websockets.Handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
 		"network/nodes":           api_code_websockets.Handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
-		"wallet/generate-address": api_code_websockets.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
-		"wallet/create-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
-		"wallet/delete-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
-		"wallet/get-balances":     api_code_websockets.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
-		"wallet/decrypt-tx":       api_code_websockets.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
-		"wallet/private-transfer": api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+		"network/banned":          api_code_websockets.HandleAuthenticated[struct{}, api_common.APINetworkBannedReply](api.apiCommon.GetNetworkBanned, network_config_auth.SCOPE_DELEGATOR_ADMIN),
+		"network/banned/add":      api_code_websockets.HandleAuthenticated[api_common.APINetworkBannedAddRequest, api_common.APINetworkBannedAddReply](api.apiCommon.GetNetworkBannedAdd, network_config_auth.SCOPE_DELEGATOR_ADMIN),
+		"network/banned/remove":   api_code_websockets.HandleAuthenticated[api_common.APINetworkBannedRemoveRequest, api_common.APINetworkBannedRemoveReply](api.apiCommon.GetNetworkBannedRemove, network_config_auth.SCOPE_DELEGATOR_ADMIN),
+		"webhooks":                api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWebhooksReply](api.apiCommon.GetWebhooks, network_config_auth.SCOPE_WEBHOOKS),
+		"webhooks/register":       api_code_websockets.HandleAuthenticated[api_common.APIWebhooksRegisterRequest, api_common.APIWebhooksRegisterReply](api.apiCommon.GetWebhooksRegister, network_config_auth.SCOPE_WEBHOOKS),
+		"webhooks/unregister":     api_code_websockets.HandleAuthenticated[api_common.APIWebhooksUnregisterRequest, api_common.APIWebhooksUnregisterReply](api.apiCommon.GetWebhooksUnregister, network_config_auth.SCOPE_WEBHOOKS),
+		"wallet/get-addresses":    api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/generate-address": api_code_websockets.HandleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/create-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/delete-address":   api_code_websockets.HandleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/get-balances":     api_code_websockets.HandleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/decrypt-tx":       api_code_websockets.HandleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/private-transfer": api_code_websockets.HandleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/batch-payout":     api_code_websockets.HandleAuthenticated[api_common.APIWalletBatchPayoutRequest, api_common.APIWalletBatchPayoutReply](api.apiCommon.WalletBatchPayout, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/unlock":           api_code_websockets.HandleAuthenticated[api_common.APIWalletUnlockRequest, api_common.APIWalletUnlockReply](api.apiCommon.WalletUnlock, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/import-view-key":  api_code_websockets.HandleAuthenticated[api_common.APIWalletImportWatchOnlyAddressRequest, api_common.APIWalletImportWatchOnlyAddressReply](api.apiCommon.WalletImportWatchOnlyAddress, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/scheduled":        api_code_websockets.HandleAuthenticated[struct{}, api_common.APIWalletScheduledPaymentsReply](api.apiCommon.GetWalletScheduledPayments, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/scheduled/add":    api_code_websockets.HandleAuthenticated[api_common.APIWalletScheduledPaymentAddRequest, api_common.APIWalletScheduledPaymentAddReply](api.apiCommon.WalletScheduledPaymentAdd, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/scheduled/remove": api_code_websockets.HandleAuthenticated[api_common.APIWalletScheduledPaymentRemoveRequest, api_common.APIWalletScheduledPaymentRemoveReply](api.apiCommon.WalletScheduledPaymentRemove, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/invoices":         api_code_websockets.HandleAuthenticated[api_common.APIWalletInvoicesRequest, api_common.APIWalletInvoicesReply](api.apiCommon.GetWalletInvoices, network_config_auth.SCOPE_WALLET_READ),
+		"wallet/invoices/create":  api_code_websockets.HandleAuthenticated[api_common.APIWalletInvoiceCreateRequest, api_common.APIWalletInvoiceCreateReply](api.apiCommon.WalletInvoiceCreate, network_config_auth.SCOPE_WALLET_SPEND),
+		"wallet/invoices/remove":  api_code_websockets.HandleAuthenticated[api_common.APIWalletInvoiceRemoveRequest, api_common.APIWalletInvoiceRemoveReply](api.apiCommon.WalletInvoiceRemove, network_config_auth.SCOPE_WALLET_SPEND),
 		//below are ONLY websockets API
 		"block-miss-txs":    api_code_websockets.Handle[consensus.APIBlockCompleteMissingTxsRequest, consensus.APIBlockCompleteMissingTxsReply](api.Consensus.GetBlockCompleteMissingTxs),
 		"handshake":         api_code_websockets.Handshake,
@@ -84,6 +101,8 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		"chain-update":      api.Consensus.ChainUpdate,
 		"login":             api_code_websockets.Login,
 		"logout":            api_code_websockets.Logout,
+		"auth/login":        api_code_websockets.Handle[api_common.APIAuthLoginRequest, api_common.APIAuthLoginReply](api.apiCommon.AuthLogin),
+		"auth/revoke":       api_code_websockets.Handle[api_common.APIAuthRevokeRequest, api_common.APIAuthRevokeReply](api.apiCommon.AuthRevoke),
 		"sub":               api_code_websockets.Subscribe,
 		"unsub":             api_code_websockets.Unsubscribe,
 	}
@@ -102,6 +121,12 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		api.GetMap["sub/notify"] = api_code_websockets.SubscribedNotificationReceived
 	}
 
+	if config.NODE_EXPLORER_INDEXER {
+		api.GetMap["explorer/asset-txs"] = api_code_websockets.Handle[api_common.APIExplorerAssetTxsRequest, api_common.APIExplorerTxsReply](api.apiCommon.GetExplorerAssetTxs)
+		api.GetMap["explorer/script-txs"] = api_code_websockets.Handle[api_common.APIExplorerScriptTxsRequest, api_common.APIExplorerTxsReply](api.apiCommon.GetExplorerScriptTxs)
+		api.GetMap["explorer/conditional-payments"] = api_code_websockets.Handle[api_common.APIExplorerConditionalPaymentsRequest, api_common.APIExplorerConditionalPaymentsReply](api.apiCommon.GetExplorerConditionalPayments)
+	}
+
 	if api.apiCommon.Faucet != nil {
 		api.GetMap["faucet/info"] = api_code_websockets.Handle[struct{}, api_faucet.APIFaucetInfo](api.apiCommon.Faucet.GetFaucetInfo)
 		if network_config.FAUCET_TESTNET_ENABLED {
@@ -111,9 +136,14 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 
 	if api.apiCommon.DelegatorNode != nil {
 		api.GetMap["delegator-node/info"] = api_code_websockets.Handle[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply](api.apiCommon.DelegatorNode.GetDelegatorNodeInfo)
-		api.GetMap["delegator-node/notify"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
+		api.GetMap["delegator-node/notify"] = api_code_websockets.HandleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify, network_config_auth.SCOPE_DELEGATOR_ADMIN)
+		api.GetMap["delegator-node/remote-signer/nonce"] = api.apiCommon.DelegatorNode.DelegatorRemoteSignerNonce
+		api.GetMap["delegator-node/remote-signer"] = api.apiCommon.DelegatorNode.DelegatorRemoteSigner
 	}
 
+	api.GetMap["remote-signer/staking-data"] = api.apiCommon.RemoteSigner.StakingData
+	api.GetMap["remote-signer/forging-tx"] = api.apiCommon.RemoteSigner.ForgingTx
+
 	if ConfigureAPIRoutes != nil {
 		ConfigureAPIRoutes(api)
 	}
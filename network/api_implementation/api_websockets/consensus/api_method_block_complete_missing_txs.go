@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/vmihailenco/msgpack/v5"
 	"net/http"
+	"pandora-pay/config"
 	"pandora-pay/helpers"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -34,6 +35,9 @@ func (api *Consensus) GetBlockCompleteMissingTxs(r *http.Request, args *APIBlock
 
 		data := reader.Get("blockTxs" + strconv.FormatUint(height, 10))
 		if data == nil {
+			if config.NODE_PRUNE > 0 {
+				return errors.New("Block transactions were pruned")
+			}
 			return errors.New("Block not found")
 		}
 
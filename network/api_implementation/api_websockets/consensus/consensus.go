@@ -30,6 +30,7 @@ func NewConsensus(chain *blockchain.Blockchain, mempool *mempool.Mempool) *Conse
 	}
 
 	consensus.execute()
+	consensus.initMetrics()
 
 	return consensus
 }
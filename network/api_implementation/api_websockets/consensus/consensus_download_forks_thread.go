@@ -18,6 +18,7 @@ import (
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/txs_validator"
+	"sync/atomic"
 	"time"
 )
 
@@ -155,7 +156,7 @@ func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 			fork.errors = -10
 		}
 
-		conn := fork.getRandomConn()
+		conn := fork.getRandomConn(start - 1)
 		if conn == nil {
 			return false
 		}
@@ -163,6 +164,7 @@ func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 		hash, err := thread.downloadBlockHash(conn, fork, start-1)
 		if err != nil {
 			fork.errors += 1
+			atomic.AddUint64(&thread.forks.downloadErrors, 1)
 			continue
 		}
 
@@ -174,16 +176,19 @@ func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 		blkComplete, err := thread.downloadBlockComplete(conn, fork, start-1)
 		if err != nil {
 			fork.errors += 1
+			atomic.AddUint64(&thread.forks.downloadErrors, 1)
 			continue
 		}
 
 		if !bytes.Equal(blkComplete.Bloom.Hash, hash) { //it is not the same block
 			fork.errors += 1
+			atomic.AddUint64(&thread.forks.downloadErrors, 1)
 			continue
 		}
 
 		//prepend
 		fork.Blocks.PushFront(blkComplete)
+		atomic.AddUint64(&thread.forks.downloadedBlocks, 1)
 
 		start -= 1
 	}
@@ -213,7 +218,7 @@ func (thread *ConsensusProcessForksThread) downloadRemainingBlocks(fork *Fork) b
 			fork.errors = -10
 		}
 
-		conn := fork.getRandomConn()
+		conn := fork.getRandomConn(fork.Current)
 		if conn == nil {
 			return false
 		}
@@ -221,11 +226,13 @@ func (thread *ConsensusProcessForksThread) downloadRemainingBlocks(fork *Fork) b
 		blkComplete, err := thread.downloadBlockComplete(conn, fork, fork.Current)
 		if err != nil {
 			fork.errors += 1
+			atomic.AddUint64(&thread.forks.downloadErrors, 1)
 			continue
 		}
 
 		fork.Blocks.Push(blkComplete)
 		fork.Current += 1
+		atomic.AddUint64(&thread.forks.downloadedBlocks, 1)
 
 	}
 
@@ -260,10 +267,12 @@ func (thread *ConsensusProcessForksThread) execute() {
 						}
 
 						if _, err := thread.chain.AddBlocks(blocks, false, advanced_connection_types.UUID_ALL); err != nil {
+							atomic.AddUint64(&thread.forks.invalid, 1)
 							if config.DEBUG {
 								gui.GUI.Error("Invalid Fork", err)
 							}
 						} else {
+							atomic.AddUint64(&thread.forks.processed, 1)
 							fork.Lock()
 							if fork.Current < fork.End {
 								fork.Blocks.Empty()
@@ -0,0 +1,37 @@
+package consensus
+
+import (
+	"pandora-pay/helpers/metrics"
+	"sync/atomic"
+)
+
+func (consensus *Consensus) initMetrics() {
+
+	forks := consensus.forks
+
+	metrics.RegisterGauge("pandora_forks", "Forks waiting to be downloaded", func() float64 {
+		count := 0
+		forks.hashes.Range(func(key string, fork *Fork) bool {
+			count++
+			return true
+		})
+		return float64(count)
+	})
+
+	metrics.RegisterCounter("pandora_forks_downloaded_blocks_total", "Blocks downloaded for forks", func() float64 {
+		return float64(atomic.LoadUint64(&forks.downloadedBlocks))
+	})
+
+	metrics.RegisterCounter("pandora_forks_download_errors_total", "Errors while downloading forks", func() float64 {
+		return float64(atomic.LoadUint64(&forks.downloadErrors))
+	})
+
+	metrics.RegisterCounter("pandora_forks_processed_total", "Forks added to the blockchain", func() float64 {
+		return float64(atomic.LoadUint64(&forks.processed))
+	})
+
+	metrics.RegisterCounter("pandora_forks_invalid_total", "Forks rejected by the blockchain", func() float64 {
+		return float64(atomic.LoadUint64(&forks.invalid))
+	})
+
+}
@@ -23,20 +23,28 @@ type Fork struct {
 	sync.RWMutex       `json:"-" msgpack:"-"`
 }
 
-//is locked before
-func (fork *Fork) getRandomConn() (conn *connection.AdvancedConnection) {
+// is locked before
+// pruned nodes that no longer have the block at height are skipped
+func (fork *Fork) getRandomConn(height uint64) (conn *connection.AdvancedConnection) {
 
-	for len(fork.conns) > 0 {
-		index := rand.Intn(len(fork.conns))
-		conn = fork.conns[index]
+	candidates := make([]*connection.AdvancedConnection, 0, len(fork.conns))
+	for i := 0; i < len(fork.conns); {
+		conn = fork.conns[i]
 		if conn.IsClosed.IsSet() {
-			fork.conns[index] = fork.conns[len(fork.conns)-1]
+			fork.conns[i] = fork.conns[len(fork.conns)-1]
 			fork.conns = fork.conns[:len(fork.conns)-1]
-		} else {
-			return
+			continue
+		}
+		if conn.Handshake.CanProvideBlockComplete(height, fork.End) {
+			candidates = append(candidates, conn)
 		}
+		i++
+	}
+
+	if len(candidates) == 0 {
+		return nil
 	}
-	return nil
+	return candidates[rand.Intn(len(candidates))]
 }
 
 func (fork *Fork) AddConn(conn *connection.AdvancedConnection, lock bool) {
@@ -6,7 +6,11 @@ import (
 )
 
 type Forks struct {
-	hashes *generics.Map[string, *Fork]
+	hashes           *generics.Map[string, *Fork]
+	downloadedBlocks uint64 //use atomic
+	downloadErrors   uint64 //use atomic
+	invalid          uint64 //use atomic
+	processed        uint64 //use atomic
 }
 
 func (forks *Forks) getBestFork() (selectedFork *Fork) {
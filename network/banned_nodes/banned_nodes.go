@@ -1,18 +1,31 @@
 package banned_nodes
 
 import (
+	"github.com/vmihailenco/msgpack/v5"
 	"net/url"
+	"pandora-pay/gui"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/network_config"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 )
 
 type BannedNodesType struct {
 	bannedMap *generics.Map[string, *BannedNode]
+	saveLock  *sync.Mutex
 }
 
 func (this *BannedNodesType) IsBanned(urlStr string) bool {
-	if _, found := this.bannedMap.Load(urlStr); found {
-		return true
+	if bannedNode, found := this.bannedMap.Load(urlStr); found {
+		if time.Now().Before(bannedNode.Expiration) {
+			return true
+		}
+		this.removeExpired()
 	}
 	return false
 }
@@ -21,13 +34,146 @@ func (this *BannedNodesType) Ban(url *url.URL, urlStr, message string, duration
 	if urlStr == "" {
 		urlStr = url.String()
 	}
+
 	time := time.Now()
-	this.bannedMap.Store(urlStr, &BannedNode{
-		URL:        url,
+
+	bannedNode := &BannedNode{
+		URL:        urlStr,
 		Message:    message,
 		Timestamp:  time,
 		Expiration: time.Add(duration),
+		Score:      1,
+	}
+
+	if old, found := this.bannedMap.Load(urlStr); found {
+		bannedNode.Score = old.Score + 1
+		if old.Expiration.After(bannedNode.Expiration) {
+			bannedNode.Expiration = old.Expiration
+		}
+	}
+
+	this.bannedMap.Store(urlStr, bannedNode)
+	this.save()
+}
+
+func (this *BannedNodesType) Unban(urlStr string) bool {
+	if _, found := this.bannedMap.LoadAndDelete(urlStr); found {
+		this.save()
+		return true
+	}
+	return false
+}
+
+// GetList returns the active bans sorted by the moment they were banned
+func (this *BannedNodesType) GetList() []*BannedNode {
+
+	now := time.Now()
+
+	list := make([]*BannedNode, 0)
+	this.bannedMap.Range(func(key string, bannedNode *BannedNode) bool {
+		if now.Before(bannedNode.Expiration) {
+			list = append(list, bannedNode)
+		}
+		return true
+	})
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Timestamp.Before(list[j].Timestamp)
+	})
+
+	return list
+}
+
+func (this *BannedNodesType) removeExpired() {
+
+	now := time.Now()
+
+	removed := false
+	this.bannedMap.Range(func(key string, bannedNode *BannedNode) bool {
+		if !now.Before(bannedNode.Expiration) {
+			this.bannedMap.Delete(key)
+			removed = true
+		}
+		return true
 	})
+
+	if removed {
+		this.save()
+	}
+}
+
+func (this *BannedNodesType) save() {
+
+	if store.StoreSettings == nil {
+		return
+	}
+
+	this.saveLock.Lock()
+	defer this.saveLock.Unlock()
+
+	list := make([]*BannedNode, 0)
+	this.bannedMap.Range(func(key string, bannedNode *BannedNode) bool {
+		list = append(list, bannedNode)
+		return true
+	})
+
+	if err := store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		marshal, err := msgpack.Marshal(list)
+		if err != nil {
+			return
+		}
+
+		writer.Put("bannedNodes", marshal)
+		return
+	}); err != nil {
+		gui.GUI.Error("Error saving banned nodes", err)
+	}
+}
+
+func (this *BannedNodesType) load() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		data := reader.Get("bannedNodes")
+		if data == nil {
+			return
+		}
+
+		list := []*BannedNode{}
+		if err = msgpack.Unmarshal(data, &list); err != nil {
+			return
+		}
+
+		now := time.Now()
+		for _, bannedNode := range list {
+			if now.Before(bannedNode.Expiration) {
+				this.bannedMap.Store(bannedNode.URL, bannedNode)
+			}
+		}
+
+		return
+	})
+}
+
+func (this *BannedNodesType) pruneExpired() {
+	for {
+		time.Sleep(network_config.BANNED_NODES_PRUNE_INTERVAL)
+		this.removeExpired()
+	}
+}
+
+// Initialize loads the bans saved in the settings store and starts pruning the expired ones
+func (this *BannedNodesType) Initialize() error {
+
+	if err := this.load(); err != nil {
+		return err
+	}
+
+	gui.GUI.Log("Banned nodes loaded " + strconv.Itoa(len(this.GetList())))
+
+	recovery.SafeGo(this.pruneExpired)
+
+	return nil
 }
 
 var BannedNodes *BannedNodesType
@@ -35,5 +181,6 @@ var BannedNodes *BannedNodesType
 func init() {
 	BannedNodes = &BannedNodesType{
 		bannedMap: &generics.Map[string, *BannedNode]{},
+		saveLock:  &sync.Mutex{},
 	}
 }
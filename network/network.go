@@ -6,9 +6,12 @@ import (
 	"pandora-pay/blockchain"
 	"pandora-pay/config"
 	"pandora-pay/mempool"
+	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/network/server/node_tcp"
+	"pandora-pay/network/webhooks"
 	"pandora-pay/network/websocks"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/settings"
@@ -94,6 +97,18 @@ func SendJSONAwaitAnswer[T any](name []byte, data any, ctxParent context.Context
 
 func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet) error {
 
+	if err := banned_nodes.BannedNodes.Initialize(); err != nil {
+		return err
+	}
+
+	if err := network_config_auth.LoadRevokedTokens(); err != nil {
+		return err
+	}
+
+	if err := webhooks.Webhooks.Initialize(chain, mempool); err != nil {
+		return err
+	}
+
 	list := make([]string, len(config.NETWORK_SELECTED_SEEDS))
 	for i, seed := range config.NETWORK_SELECTED_SEEDS {
 		list[i] = seed.Url
@@ -108,6 +123,8 @@ func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempo
 
 	Network = &networkType{}
 
+	initMetrics()
+
 	Network.continuouslyConnectingNewPeers()
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"pandora-pay/config/arguments"
 	"pandora-pay/network/network_config/network_config_auth"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,8 +17,11 @@ var (
 	NETWORK_KNOWN_NODES_LIMIT            int32 = 5000
 	NETWORK_KNOWN_NODES_LIST_RETURN            = 100
 	NETWORK_ENABLE_SUBSCRIPTIONS               = false
+	NETWORK_METRICS                            = false
+	NETWORK_STORE_BACKUP                       = false
 	NETWORK_CONNECTIONS_READY_THRESHOLD        = int64(1)
 	STATIC_FILES                               = map[string]string{}
+	WEBHOOKS_ALLOWED_HOSTS                     = []string{} //hosts which may resolve to private addresses
 )
 
 const (
@@ -29,6 +33,15 @@ const (
 	WEBSOCKETS_INCREASE_KNOWN_NODE_SCORE_INTERVAL = 1 * time.Minute
 	WEBSOCKETS_CONCURRENT_NEW_CONENCTIONS         = 5
 	WEBSOCKETS_TIMEOUT                            = 15 * time.Second //seconds
+	BANNED_NODES_PRUNE_INTERVAL                   = 1 * time.Minute
+	WEBHOOKS_MAX                                  = 1000
+	WEBHOOKS_QUEUE_MAX                            = 10000
+	WEBHOOKS_MAX_ATTEMPTS                         = 10
+	WEBHOOKS_RETRY_INTERVAL                       = 5 * time.Second
+	WEBHOOKS_RETRY_MAX_INTERVAL                   = 1 * time.Hour
+	WEBHOOKS_TIMEOUT                              = 10 * time.Second
+	WEBHOOKS_WORKERS                              = 4
+	STORE_BACKUP_WRITE_TIMEOUT                    = 30 * time.Second //a client which doesn't read the backup for this interval is disconnected
 )
 
 func InitConfig() (err error) {
@@ -51,6 +64,18 @@ func InitConfig() (err error) {
 		}
 	}
 
+	if arguments.Arguments["--webhooks-allowed-hosts"] != nil {
+		WEBHOOKS_ALLOWED_HOSTS = strings.Split(arguments.Arguments["--webhooks-allowed-hosts"].(string), ",")
+	}
+
+	if arguments.Arguments["--node-metrics"] == "true" {
+		NETWORK_METRICS = true
+	}
+
+	if arguments.Arguments["--node-store-backup"] == "true" {
+		NETWORK_STORE_BACKUP = true
+	}
+
 	if config.NETWORK_SELECTED == config.TEST_NET_NETWORK_BYTE || config.NETWORK_SELECTED == config.DEV_NET_NETWORK_BYTE {
 
 		if arguments.Arguments["--hcaptcha-secret"] != nil {
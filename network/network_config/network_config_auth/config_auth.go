@@ -1,20 +1,53 @@
 package network_config_auth
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"golang.org/x/exp/slices"
 	"pandora-pay/config/arguments"
+	"pandora-pay/helpers"
+	"strconv"
+	"time"
 )
 
 type ConfigAuth struct {
-	Username string `json:"user" msgpack:"user"`
-	Password string `json:"pass"  msgpack:"pass"`
+	Username string   `json:"user" msgpack:"user"`
+	Password string   `json:"pass"  msgpack:"pass"`
+	Scopes   []string `json:"scopes,omitempty"  msgpack:"scopes,omitempty"` //empty means all scopes
 }
 
 var (
-	CONFIG_AUTH_USERS_LIST []*ConfigAuth
-	CONFIG_AUTH_USERS_MAP  map[string]*ConfigAuth
+	CONFIG_AUTH_USERS_LIST       []*ConfigAuth
+	CONFIG_AUTH_USERS_MAP        map[string]*ConfigAuth
+	CONFIG_AUTH_TOKEN_SECRET     []byte
+	CONFIG_AUTH_TOKEN_EXPIRATION = time.Hour
 )
 
+const (
+	CONFIG_AUTH_TOKEN_EXPIRATION_MAX = 30 * 24 * time.Hour
+)
+
+func (auth *ConfigAuth) HasScope(scope string) bool {
+	return len(auth.Scopes) == 0 || slices.Contains(auth.Scopes, scope) || (scope == SCOPE_WALLET_READ && slices.Contains(auth.Scopes, SCOPE_WALLET_SPEND))
+}
+
+func (auth *ConfigAuth) GetScopes() []string {
+	if len(auth.Scopes) == 0 {
+		return SCOPES
+	}
+	return auth.Scopes
+}
+
+// GetUser returns the user only if the password matches
+func GetUser(username, password string) *ConfigAuth {
+	user := CONFIG_AUTH_USERS_MAP[username]
+	if user == nil || !checkPassword(user.Password, password) {
+		return nil
+	}
+	return user
+}
+
 func InitConfig() (err error) {
 
 	if str := arguments.Arguments["--auth-users"]; str != nil {
@@ -25,8 +58,31 @@ func InitConfig() (err error) {
 
 	CONFIG_AUTH_USERS_MAP = map[string]*ConfigAuth{}
 	for _, auth := range CONFIG_AUTH_USERS_LIST {
+		for _, scope := range auth.Scopes {
+			if !slices.Contains(SCOPES, scope) {
+				return errors.New("Invalid auth scope " + scope)
+			}
+		}
 		CONFIG_AUTH_USERS_MAP[auth.Username] = auth
 	}
 
+	if str := arguments.Arguments["--auth-token-secret"]; str != nil {
+		if CONFIG_AUTH_TOKEN_SECRET, err = hex.DecodeString(str.(string)); err != nil || len(CONFIG_AUTH_TOKEN_SECRET) < 32 {
+			return errors.New("--auth-token-secret must be a hex of at least 32 bytes")
+		}
+	} else {
+		CONFIG_AUTH_TOKEN_SECRET = helpers.RandomBytes(32)
+	}
+
+	if str := arguments.Arguments["--auth-token-expiration"]; str != nil {
+		var seconds uint64
+		if seconds, err = strconv.ParseUint(str.(string), 10, 64); err != nil {
+			return
+		}
+		if CONFIG_AUTH_TOKEN_EXPIRATION = time.Duration(seconds) * time.Second; CONFIG_AUTH_TOKEN_EXPIRATION == 0 || CONFIG_AUTH_TOKEN_EXPIRATION > CONFIG_AUTH_TOKEN_EXPIRATION_MAX {
+			return errors.New("--auth-token-expiration is invalid")
+		}
+	}
+
 	return
 }
@@ -0,0 +1,117 @@
+package network_config_auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"golang.org/x/crypto/argon2"
+	"pandora-pay/helpers"
+	"strings"
+	"sync"
+)
+
+const (
+	PASSWORD_HASH_PREFIX  = "argon2id$"
+	PASSWORD_HASH_TIME    = 1
+	PASSWORD_HASH_MEMORY  = 64 * 1024
+	PASSWORD_HASH_THREADS = 4
+	PASSWORD_HASH_LENGTH  = 32
+
+	PASSWORD_HASH_CONCURRENCY = 2    //argon2 hashes computed at the same time, as every one of them allocates PASSWORD_HASH_MEMORY KiB
+	PASSWORD_FAILED_CACHE_MAX = 4096 //failed attempts remembered. The cache is cleared once it is full
+)
+
+// HashPassword returns "argon2id$salt$hash" that can be used as pass in --auth-users instead of the plaintext password
+func HashPassword(password string) string {
+	salt := helpers.RandomBytes(16)
+	hash := argon2.IDKey([]byte(password), salt, PASSWORD_HASH_TIME, PASSWORD_HASH_MEMORY, PASSWORD_HASH_THREADS, PASSWORD_HASH_LENGTH)
+	return PASSWORD_HASH_PREFIX + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(hash)
+}
+
+// verified caches a keyed mac of the last password which matched each hash. The argon2 hash is computed once per login and not on every authenticated request
+var verified = struct {
+	list map[string][]byte //stored hash => mac of the password
+	key  []byte
+	sync.RWMutex
+}{list: make(map[string][]byte), key: helpers.RandomBytes(32)}
+
+// failed caches the macs of the passwords which didn't match, so the same wrong credentials sent on every request don't compute the argon2 hash again
+var failed = struct {
+	list map[string]bool
+	sync.RWMutex
+}{list: make(map[string]bool)}
+
+var hashSlots = make(chan struct{}, PASSWORD_HASH_CONCURRENCY)
+
+func addFailed(mac []byte) {
+	failed.Lock()
+	defer failed.Unlock()
+	if len(failed.list) >= PASSWORD_FAILED_CACHE_MAX {
+		failed.list = make(map[string]bool)
+	}
+	failed.list[string(mac)] = true
+}
+
+func verifiedMac(stored, password string) []byte {
+	mac := hmac.New(sha256.New, verified.key)
+	mac.Write([]byte(stored))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
+// checkPassword supports both hashed and legacy plaintext passwords
+func checkPassword(stored, password string) bool {
+
+	if !strings.HasPrefix(stored, PASSWORD_HASH_PREFIX) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	}
+
+	mac := verifiedMac(stored, password)
+
+	verified.RLock()
+	cached := verified.list[stored]
+	verified.RUnlock()
+
+	if cached != nil && hmac.Equal(cached, mac) {
+		return true
+	}
+
+	failed.RLock()
+	wrong := failed.list[string(mac)]
+	failed.RUnlock()
+
+	if wrong {
+		return false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(stored, PASSWORD_HASH_PREFIX), "$")
+	if len(parts) != 2 {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil || len(expected) != PASSWORD_HASH_LENGTH {
+		return false
+	}
+
+	hashSlots <- struct{}{}
+	hash := argon2.IDKey([]byte(password), salt, PASSWORD_HASH_TIME, PASSWORD_HASH_MEMORY, PASSWORD_HASH_THREADS, PASSWORD_HASH_LENGTH)
+	<-hashSlots
+
+	if subtle.ConstantTimeCompare(hash, expected) != 1 {
+		addFailed(mac)
+		return false
+	}
+
+	verified.Lock()
+	verified.list[stored] = mac
+	verified.Unlock()
+
+	return true
+}
@@ -0,0 +1,58 @@
+package network_config_auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestCheckPassword(t *testing.T) {
+
+	assert.True(t, checkPassword("plain", "plain"))
+	assert.False(t, checkPassword("plain", "other"))
+
+	stored := HashPassword("password")
+	assert.True(t, checkPassword(stored, "password"))
+	assert.True(t, checkPassword(stored, "password"), "verified password should be cached")
+
+	assert.False(t, checkPassword(stored, "wrong"))
+	failed.RLock()
+	assert.True(t, failed.list[string(verifiedMac(stored, "wrong"))])
+	failed.RUnlock()
+	assert.False(t, checkPassword(stored, "wrong"), "failed password should be cached")
+
+	assert.False(t, checkPassword("argon2id$invalid", "password"))
+}
+
+func TestCheckPasswordConcurrency(t *testing.T) {
+
+	stored := HashPassword("password")
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 3*PASSWORD_HASH_CONCURRENCY; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.False(t, checkPassword(stored, "wrong"+string(rune('a'+i))))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, len(hashSlots))
+	assert.True(t, checkPassword(stored, "password"))
+}
+
+func TestCheckPasswordFailedCacheMax(t *testing.T) {
+
+	failed.Lock()
+	failed.list = make(map[string]bool)
+	failed.Unlock()
+
+	for i := 0; i < PASSWORD_FAILED_CACHE_MAX; i++ {
+		addFailed([]byte{byte(i), byte(i >> 8)})
+	}
+	assert.Equal(t, PASSWORD_FAILED_CACHE_MAX, len(failed.list))
+
+	addFailed([]byte("mac"))
+	assert.Equal(t, 1, len(failed.list))
+}
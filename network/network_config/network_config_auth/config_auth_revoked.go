@@ -0,0 +1,78 @@
+package network_config_auth
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/gui"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sync"
+	"time"
+)
+
+// the revoked tokens are saved in the settings store, as the tokens signed with --auth-token-secret remain valid after restart
+var revoked = struct {
+	list map[string]int64 //token id => expiration
+	sync.Mutex
+}{list: make(map[string]int64)}
+
+func (token *AuthToken) isRevoked() bool {
+	revoked.Lock()
+	defer revoked.Unlock()
+	_, found := revoked.list[string(token.Id)]
+	return found
+}
+
+// must be locked before
+func removeExpiredRevoked() {
+	now := time.Now().Unix()
+	for id, expiration := range revoked.list {
+		if now >= expiration {
+			delete(revoked.list, id)
+		}
+	}
+}
+
+// must be locked before
+func saveRevoked() error {
+
+	if store.StoreSettings == nil {
+		return nil
+	}
+
+	marshal, err := msgpack.Marshal(revoked.list)
+	if err != nil {
+		return err
+	}
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put("authRevokedTokens", marshal)
+		return nil
+	})
+}
+
+// LoadRevokedTokens loads the revoked tokens which didn't expire yet
+func LoadRevokedTokens() error {
+
+	revoked.Lock()
+	defer revoked.Unlock()
+
+	if err := store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		data := reader.Get("authRevokedTokens")
+		if data == nil {
+			return nil
+		}
+
+		return msgpack.Unmarshal(data, &revoked.list)
+	}); err != nil {
+		return err
+	}
+
+	removeExpiredRevoked()
+
+	if len(revoked.list) > 0 {
+		gui.GUI.Log("Revoked auth tokens loaded", len(revoked.list))
+	}
+
+	return nil
+}
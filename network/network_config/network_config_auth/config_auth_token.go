@@ -0,0 +1,156 @@
+package network_config_auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"golang.org/x/exp/slices"
+	"pandora-pay/helpers"
+	"strings"
+	"time"
+)
+
+const (
+	SCOPE_WALLET_READ     = "wallet-read"     //read wallet addresses, balances and decrypt transactions
+	SCOPE_WALLET_SPEND    = "wallet-spend"    //create transactions and manage the wallet addresses. It includes SCOPE_WALLET_READ
+	SCOPE_DELEGATOR_ADMIN = "delegator-admin" //delegator notifications and node administration
+	SCOPE_WEBHOOKS        = "webhooks"        //register and remove webhooks
+	SCOPE_METRICS         = "metrics"         //scrape the /metrics
+)
+
+var SCOPES = []string{SCOPE_WALLET_READ, SCOPE_WALLET_SPEND, SCOPE_DELEGATOR_ADMIN, SCOPE_WEBHOOKS, SCOPE_METRICS}
+
+type AuthToken struct {
+	Id         []byte   `json:"id" msgpack:"id"`
+	Username   string   `json:"user" msgpack:"user"`
+	Scopes     []string `json:"scopes" msgpack:"scopes"`
+	Expiration int64    `json:"exp" msgpack:"exp"`
+}
+
+// Check verifies that the token is still valid and grants the scope
+func (token *AuthToken) Check(scope string) bool {
+
+	if token == nil || time.Now().Unix() >= token.Expiration || token.isRevoked() {
+		return false
+	}
+
+	user := CONFIG_AUTH_USERS_MAP[token.Username]
+	if user == nil || !user.HasScope(scope) {
+		return false
+	}
+
+	return slices.Contains(token.Scopes, scope) || (scope == SCOPE_WALLET_READ && slices.Contains(token.Scopes, SCOPE_WALLET_SPEND))
+}
+
+func signToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, CONFIG_AUTH_TOKEN_SECRET)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// CreateToken authenticates the user and issues a signed bearer token for the requested scopes. No scopes means all the user's scopes
+func CreateToken(username, password string, scopes []string, expiration time.Duration) (string, *AuthToken, error) {
+
+	user := GetUser(username, password)
+	if user == nil {
+		return "", nil, errors.New("Invalid user or password")
+	}
+
+	if len(scopes) == 0 {
+		scopes = user.GetScopes()
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(SCOPES, scope) {
+			return "", nil, errors.New("Invalid scope " + scope)
+		}
+		if !user.HasScope(scope) {
+			return "", nil, errors.New("Scope " + scope + " is not allowed")
+		}
+	}
+
+	if expiration == 0 {
+		expiration = CONFIG_AUTH_TOKEN_EXPIRATION
+	}
+	if expiration < 0 || expiration > CONFIG_AUTH_TOKEN_EXPIRATION_MAX {
+		return "", nil, errors.New("Invalid expiration")
+	}
+
+	token := &AuthToken{helpers.RandomBytes(16), user.Username, scopes, time.Now().Add(expiration).Unix()}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signToken(payload)), token, nil
+}
+
+// VerifyToken checks the signature and the expiration of a bearer token
+func VerifyToken(str string) (*AuthToken, error) {
+
+	parts := strings.Split(str, ".")
+	if len(parts) != 2 {
+		return nil, errors.New("Invalid token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("Invalid token")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("Invalid token")
+	}
+
+	if !hmac.Equal(signature, signToken(payload)) {
+		return nil, errors.New("Invalid token signature")
+	}
+
+	token := &AuthToken{}
+	if err = json.Unmarshal(payload, token); err != nil {
+		return nil, errors.New("Invalid token")
+	}
+
+	if time.Now().Unix() >= token.Expiration {
+		return nil, errors.New("Token expired")
+	}
+	if token.isRevoked() {
+		return nil, errors.New("Token was revoked")
+	}
+
+	return token, nil
+}
+
+// CheckToken verifies a bearer token and that it grants the scope
+func CheckToken(str, scope string) bool {
+	token, err := VerifyToken(str)
+	if err != nil {
+		return false
+	}
+	return token.Check(scope)
+}
+
+// RevokeToken invalidates a token until it expires. The revocation is saved and survives restarts
+func RevokeToken(str string) error {
+
+	token, err := VerifyToken(str)
+	if err != nil {
+		return err
+	}
+
+	revoked.Lock()
+	defer revoked.Unlock()
+
+	removeExpiredRevoked()
+	revoked.list[string(token.Id)] = token.Expiration
+
+	return saveRevoked()
+}
+
+// CheckCredentials is the legacy user/pass authentication
+func CheckCredentials(username, password, scope string) bool {
+	user := GetUser(username, password)
+	return user != nil && user.HasScope(scope)
+}
@@ -0,0 +1,28 @@
+package network
+
+import (
+	"pandora-pay/helpers/metrics"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/known_nodes"
+	"sync/atomic"
+)
+
+func initMetrics() {
+
+	metrics.Register("pandora_peers_connected", "Connected peers", metrics.METRIC_GAUGE, func() []*metrics.Sample {
+		return []*metrics.Sample{
+			{"", map[string]string{"type": "client"}, float64(atomic.LoadInt64(&connected_nodes.ConnectedNodes.Clients))},
+			{"", map[string]string{"type": "server"}, float64(atomic.LoadInt64(&connected_nodes.ConnectedNodes.ServerSockets))},
+		}
+	})
+
+	metrics.RegisterGauge("pandora_peers_known", "Known peers", func() float64 {
+		return float64(len(known_nodes.KnownNodes.GetList()))
+	})
+
+	metrics.RegisterGauge("pandora_peers_banned", "Banned peers", func() float64 {
+		return float64(len(banned_nodes.BannedNodes.GetList()))
+	})
+
+}
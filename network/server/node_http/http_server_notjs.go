@@ -4,30 +4,44 @@
 package node_http
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"github.com/rs/cors"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"pandora-pay/blockchain"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/metrics"
 	"pandora-pay/mempool"
+	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/api_implementation/api_common"
 	"pandora-pay/network/api_implementation/api_http"
 	"pandora-pay/network/api_implementation/api_websockets"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
+	"pandora-pay/network/server/node_http_jsonrpc"
 	"pandora-pay/network/server/node_http_rpc"
 	"pandora-pay/network/websocks"
 	"pandora-pay/settings"
+	"pandora-pay/store"
 	"pandora-pay/wallet"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type httpServerType struct {
 	Api           *api_http.API
 	ApiWebsockets *api_websockets.APIWebsockets
 	ApiStore      *api_common.APIStore
+	JSONRPC       *node_http_jsonrpc.JSONRPCServer
 	GetMap        map[string]func(values url.Values) (any, error)
-	PostMap       map[string]func(values io.ReadCloser) (any, error)
+	PostMap       map[string]func(values io.ReadCloser, authorization *api_code_types.Authorization) (any, error)
+	storeBackups  int32 //a single backup is created and downloaded at once
 }
 
 var HttpServer *httpServerType
@@ -51,6 +65,7 @@ func (this *httpServerType) get(w http.ResponseWriter, req *http.Request) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		api_code_types.ParseAuthorization(req).SetValues(args)
 		output, err = callback(args)
 	} else {
 		err = errors.New("Unknown request")
@@ -91,7 +106,7 @@ func (this *httpServerType) post(w http.ResponseWriter, req *http.Request) {
 
 	callback := this.PostMap[req.URL.Path]
 	if callback != nil {
-		output, err = callback(req.Body)
+		output, err = callback(req.Body, api_code_types.ParseAuthorization(req))
 	} else {
 		err = errors.New("Unknown request")
 	}
@@ -118,11 +133,104 @@ func (this *httpServerType) post(w http.ResponseWriter, req *http.Request) {
 	w.Write(final)
 }
 
+// checkAuthorization verifies the Authorization header. It accepts only a "Bearer" token or the basic auth of an user
+func checkAuthorization(req *http.Request, scope string) bool {
+	authorization := api_code_types.ParseAuthorization(req)
+	return authorization != nil && authorization.CheckAuthenticated(scope)
+}
+
+func (this *httpServerType) metrics(w http.ResponseWriter, req *http.Request) {
+
+	if !checkAuthorization(req, network_config_auth.SCOPE_METRICS) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Invalid User or Password", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := metrics.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// storeBackupWriter renews the write deadline of the response for every chunk, so a client which stops reading is disconnected
+type storeBackupWriter struct {
+	w http.ResponseWriter
+}
+
+func (writer *storeBackupWriter) Write(p []byte) (int, error) {
+	if conn, ok := writer.w.(interface{ SetWriteDeadline(time.Time) error }); ok {
+		if err := conn.SetWriteDeadline(time.Now().Add(network_config.STORE_BACKUP_WRITE_TIMEOUT)); err != nil {
+			return 0, err
+		}
+	}
+	return writer.w.Write(p)
+}
+
+// storeBackup streams the backup archive of the blockchain store. The other stores contain secrets and can be backed up only from the CLI.
+// The backup is written first into a temporary file, so the store is not kept open while the client downloads it. Only one backup is served at once
+func (this *httpServerType) storeBackup(w http.ResponseWriter, req *http.Request) {
+
+	if !checkAuthorization(req, network_config_auth.SCOPE_DELEGATOR_ADMIN) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Invalid User or Password", http.StatusUnauthorized)
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&this.storeBackups, 0, 1) {
+		http.Error(w, "Another backup is in progress", http.StatusTooManyRequests)
+		return
+	}
+	defer atomic.StoreInt32(&this.storeBackups, 0)
+
+	file, err := os.CreateTemp("", "store_backup")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	buf := bufio.NewWriterSize(file, 1<<20)
+	if _, err = store.StoreBlockchain.Backup(buf); err == nil {
+		if err = buf.Flush(); err == nil {
+			_, err = file.Seek(0, io.SeekStart)
+		}
+	}
+
+	var stat os.FileInfo
+	if err == nil {
+		stat, err = file.Stat()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Length", strconv.FormatInt(stat.Size(), 10))
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+strings.TrimPrefix(store.StoreBlockchain.Name, "/")+".backup\"")
+
+	if _, err = io.Copy(&storeBackupWriter{w}, file); err != nil {
+		gui.GUI.Error("Error sending the backup of the store", store.StoreBlockchain.Name, err)
+	}
+}
+
 func (this *httpServerType) GetHttpHandler() *http.Handler {
 
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/ws", websocks.Websockets.HandleUpgradeConnection)
+	mux.Handle("/jsonrpc", this.JSONRPC)
+	mux.HandleFunc("/jsonrpc/ws", this.JSONRPC.HandleUpgradeConnection)
+	if network_config.NETWORK_METRICS {
+		mux.HandleFunc("/metrics", this.metrics)
+	}
+	if network_config.NETWORK_STORE_BACKUP {
+		mux.HandleFunc("/store/backup", this.storeBackup)
+	}
 
 	for key, filepath := range network_config.STATIC_FILES {
 		fs := http.FileServer(http.Dir(filepath))
@@ -160,8 +268,10 @@ func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, me
 		api,
 		apiWebsockets,
 		apiStore,
+		node_http_jsonrpc.NewJSONRPCServer(api, chain, mempool),
 		make(map[string]func(values url.Values) (any, error)),
-		make(map[string]func(values io.ReadCloser) (any, error)),
+		make(map[string]func(values io.ReadCloser, authorization *api_code_types.Authorization) (any, error)),
+		0,
 	}
 
 	if err = node_http_rpc.InitializeRPC(apiCommon); err != nil {
@@ -0,0 +1,291 @@
+package node_http_jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"pandora-pay/blockchain"
+	"pandora-pay/mempool"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/api_implementation/api_http"
+	"pandora-pay/network/network_config"
+	"strconv"
+)
+
+// JSONRPCServer exposes the GetMap and PostMap routes of the http API as JSON-RPC 2.0 methods.
+// The method name is the route (e.g. "block-complete" or "wallet/private-transfer"), "info" being the root route
+type JSONRPCServer struct {
+	api           *api_http.API
+	subscriptions *jsonrpcSubscriptions
+}
+
+func (this *JSONRPCServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "JSON-RPC requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, req.Body, int64(network_config.WEBSOCKETS_MAX_READ)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	final := this.process(data, nil, api_code_types.ParseAuthorization(req))
+	if final == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(final)
+}
+
+// process handles a single request or a batch and returns nil when there is nothing to answer (only notifications).
+// The authorization of the http request is used by the methods whose params don't contain credentials
+func (this *JSONRPCServer) process(data []byte, conn *jsonrpcConn, authorization *api_code_types.Authorization) []byte {
+
+	var final any
+
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+
+		batch := []json.RawMessage{}
+		if err := json.Unmarshal(data, &batch); err != nil {
+			final = errorResponse(nil, newError(ERROR_PARSE, "Parse error"))
+		} else if len(batch) == 0 {
+			final = errorResponse(nil, newError(ERROR_INVALID_REQUEST, "Batch is empty"))
+		} else if len(batch) > JSONRPC_MAX_BATCH {
+			final = errorResponse(nil, newError(ERROR_INVALID_REQUEST, "Batch is too large"))
+		} else {
+
+			responses := make([]*JSONRPCResponse, 0, len(batch))
+			for _, raw := range batch {
+				if response := this.processRequest(raw, conn, authorization); response != nil {
+					responses = append(responses, response)
+				}
+			}
+
+			if len(responses) == 0 {
+				return nil
+			}
+			final = responses
+		}
+
+	} else {
+		response := this.processRequest(data, conn, authorization)
+		if response == nil {
+			return nil
+		}
+		final = response
+	}
+
+	out, err := json.Marshal(final)
+	if err != nil {
+		out, _ = json.Marshal(errorResponse(nil, newError(ERROR_INTERNAL, err.Error())))
+	}
+	return out
+}
+
+func (this *JSONRPCServer) processRequest(raw json.RawMessage, conn *jsonrpcConn, authorization *api_code_types.Authorization) *JSONRPCResponse {
+
+	if !json.Valid(raw) {
+		return errorResponse(nil, newError(ERROR_PARSE, "Parse error"))
+	}
+
+	request := &JSONRPCRequest{}
+	if err := json.Unmarshal(raw, request); err != nil {
+		return errorResponse(nil, newError(ERROR_INVALID_REQUEST, "Invalid request"))
+	}
+
+	if !validId(request.Id) {
+		return errorResponse(nil, newError(ERROR_INVALID_REQUEST, "Invalid request id"))
+	}
+
+	if request.Version != JSONRPC_VERSION || request.Method == "" {
+		return errorResponse(request.Id, newError(ERROR_INVALID_REQUEST, "Invalid request"))
+	}
+
+	result, rpcErr := this.call(request.Method, request.Params, conn, authorization)
+
+	//notifications are never answered
+	if len(request.Id) == 0 {
+		return nil
+	}
+
+	if rpcErr != nil {
+		return errorResponse(request.Id, rpcErr)
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(request.Id, newError(ERROR_INTERNAL, err.Error()))
+	}
+
+	return &JSONRPCResponse{JSONRPC_VERSION, out, nil, request.Id}
+}
+
+func (this *JSONRPCServer) call(method string, params json.RawMessage, conn *jsonrpcConn, authorization *api_code_types.Authorization) (result any, rpcErr *JSONRPCError) {
+
+	defer func() {
+		if err := recover(); err != nil {
+			rpcErr = newError(ERROR_INTERNAL, fmt.Sprint(err))
+		}
+	}()
+
+	var err error
+
+	if conn != nil {
+		switch method {
+		case "subscribe":
+			request := &JSONRPCSubscriptionRequest{}
+			if err = json.Unmarshal(params, request); err != nil {
+				return nil, newError(ERROR_INVALID_PARAMS, err.Error())
+			}
+			if result, err = this.subscriptions.subscribe(conn, request.Type, request.Key); err != nil {
+				return nil, newError(ERROR_SERVER, err.Error())
+			}
+			return
+		case "unsubscribe":
+			request := &JSONRPCUnsubscriptionRequest{}
+			if err = json.Unmarshal(params, request); err != nil {
+				return nil, newError(ERROR_INVALID_PARAMS, err.Error())
+			}
+			if err = this.subscriptions.unsubscribe(conn, request.Subscription); err != nil {
+				return nil, newError(ERROR_SERVER, err.Error())
+			}
+			return true, nil
+		}
+	}
+
+	route := method
+	if route == "info" {
+		route = ""
+	}
+
+	if callback := this.api.GetMap[route]; callback != nil {
+
+		values := url.Values{}
+		if err = paramsToValues(params, values); err != nil {
+			return nil, newError(ERROR_INVALID_PARAMS, err.Error())
+		}
+		authorization.SetValues(values)
+		if result, err = callback(values); err != nil {
+			return nil, newError(ERROR_SERVER, err.Error())
+		}
+		return
+
+	} else if callback := this.api.PostMap[route]; callback != nil {
+
+		if len(params) == 0 {
+			params = []byte("{}")
+		} else if params[0] != '{' {
+			return nil, newError(ERROR_INVALID_PARAMS, "Params must be an object")
+		}
+		if result, err = callback(io.NopCloser(bytes.NewReader(params)), authorization); err != nil {
+			return nil, newError(ERROR_SERVER, err.Error())
+		}
+		return
+
+	}
+
+	return nil, newError(ERROR_METHOD_NOT_FOUND, "Method not found")
+}
+
+// paramsToValues flattens the named params into the url values expected by the GET handlers
+func paramsToValues(params json.RawMessage, values url.Values) error {
+
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.UseNumber()
+
+	object := map[string]any{}
+	if err := decoder.Decode(&object); err != nil {
+		return errors.New("Params must be an object")
+	}
+
+	for key, value := range object {
+		if err := flattenParam(key, value, values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func flattenParam(key string, value any, values url.Values) error {
+	switch v := value.(type) {
+	case nil:
+	case string:
+		values.Add(key, v)
+	case json.Number:
+		values.Add(key, v.String())
+	case bool:
+		values.Add(key, strconv.FormatBool(v))
+	case []any:
+		for i, it := range v {
+			switch it.(type) {
+			case map[string]any, []any:
+				if err := flattenParam(key+"."+strconv.Itoa(i), it, values); err != nil {
+					return err
+				}
+			default:
+				if err := flattenParam(key, it, values); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		for k, it := range v {
+			if err := flattenParam(key+"."+k, it, values); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("Invalid param " + key)
+	}
+	return nil
+}
+
+// validId accepts a missing id (notification), null, a string or an integer. Fractional numbers, objects, arrays and booleans are rejected
+func validId(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return true
+	}
+
+	id = bytes.TrimSpace(id)
+	if bytes.Equal(id, []byte("null")) {
+		return true
+	}
+
+	switch id[0] {
+	case '"':
+		var str string
+		return json.Unmarshal(id, &str) == nil
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		_, err := strconv.ParseInt(string(id), 10, 64)
+		return err == nil
+	}
+	return false
+}
+
+func errorResponse(id json.RawMessage, rpcErr *JSONRPCError) *JSONRPCResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &JSONRPCResponse{JSONRPC_VERSION, nil, rpcErr, id}
+}
+
+func NewJSONRPCServer(api *api_http.API, chain *blockchain.Blockchain, mempool *mempool.Mempool) *JSONRPCServer {
+	return &JSONRPCServer{
+		api,
+		newJSONRPCSubscriptions(chain, mempool),
+	}
+}
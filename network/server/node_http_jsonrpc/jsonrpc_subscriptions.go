@@ -0,0 +1,215 @@
+package node_http_jsonrpc
+
+import (
+	"errors"
+	"pandora-pay/blockchain"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/mempool"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/subscriptions"
+	"pandora-pay/network/websocks/connection"
+	"sync"
+	"sync/atomic"
+)
+
+type jsonrpcSubscription struct {
+	Id   uint64
+	Type api_code_types.SubscriptionType
+	Key  []byte
+	conn *jsonrpcConn
+}
+
+type jsonrpcSubscriptions struct {
+	chain                             *blockchain.Blockchain
+	mempool                           *mempool.Mempool
+	index                             uint64 //use atomic
+	accountsSubscriptions             map[string]map[*jsonrpcSubscription]bool
+	accountsTransactionsSubscriptions map[string]map[*jsonrpcSubscription]bool
+	assetsSubscriptions               map[string]map[*jsonrpcSubscription]bool
+	transactionsSubscriptions         map[string]map[*jsonrpcSubscription]bool
+	invoicesSubscriptions             map[string]map[*jsonrpcSubscription]bool
+	scheduledPaymentsSubscriptions    map[string]map[*jsonrpcSubscription]bool
+	lock                              *sync.RWMutex
+}
+
+func (this *jsonrpcSubscriptions) getSubsMap(subscriptionType api_code_types.SubscriptionType) (subsMap map[string]map[*jsonrpcSubscription]bool) {
+	switch subscriptionType {
+	case api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_code_types.SUBSCRIPTION_REGISTRATION:
+		subsMap = this.accountsSubscriptions
+	case api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS:
+		subsMap = this.accountsTransactionsSubscriptions
+	case api_code_types.SUBSCRIPTION_ASSET:
+		subsMap = this.assetsSubscriptions
+	case api_code_types.SUBSCRIPTION_TRANSACTION:
+		subsMap = this.transactionsSubscriptions
+	case api_code_types.SUBSCRIPTION_INVOICE:
+		subsMap = this.invoicesSubscriptions
+	case api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT:
+		subsMap = this.scheduledPaymentsSubscriptions
+	}
+	return
+}
+
+func (this *jsonrpcSubscriptions) subscribe(conn *jsonrpcConn, subscriptionType api_code_types.SubscriptionType, key []byte) (uint64, error) {
+
+	if !network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
+		return 0, errors.New("Subscriptions are disabled")
+	}
+
+	if subscriptionType == api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT || subscriptionType == api_code_types.SUBSCRIPTION_REGISTRATION {
+		return 0, errors.New("These subscriptions are automatically. They can't be subsribed manually")
+	}
+
+	if err := connection.CheckSubscriptionLength(key, subscriptionType); err != nil {
+		return 0, err
+	}
+
+	conn.Lock()
+	defer conn.Unlock()
+
+	if len(conn.subscriptions) >= network_config.WEBSOCKETS_MAX_SUBSCRIPTIONS {
+		return 0, errors.New("Too many subscriptions")
+	}
+
+	keyStr := string(key)
+	for _, subscription := range conn.subscriptions {
+		if subscription.Type == subscriptionType && subscription.Key != nil && string(subscription.Key) == keyStr {
+			return 0, errors.New("Already subscribed")
+		}
+	}
+
+	subscription := &jsonrpcSubscription{atomic.AddUint64(&this.index, 1), subscriptionType, key, conn}
+	conn.subscriptions[subscription.Id] = subscription
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	subsMap := this.getSubsMap(subscriptionType)
+	if subsMap[keyStr] == nil {
+		subsMap[keyStr] = make(map[*jsonrpcSubscription]bool)
+	}
+	subsMap[keyStr][subscription] = true
+
+	return subscription.Id, nil
+}
+
+func (this *jsonrpcSubscriptions) remove(subscription *jsonrpcSubscription) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	subsMap := this.getSubsMap(subscription.Type)
+
+	keyStr := string(subscription.Key)
+	if subsMap[keyStr] != nil {
+		delete(subsMap[keyStr], subscription)
+		if len(subsMap[keyStr]) == 0 {
+			delete(subsMap, keyStr)
+		}
+	}
+}
+
+func (this *jsonrpcSubscriptions) unsubscribe(conn *jsonrpcConn, id uint64) error {
+
+	conn.Lock()
+	defer conn.Unlock()
+
+	subscription := conn.subscriptions[id]
+	if subscription == nil {
+		return errors.New("Subscription not found")
+	}
+
+	delete(conn.subscriptions, id)
+	this.remove(subscription)
+
+	return nil
+}
+
+func (this *jsonrpcSubscriptions) removeConnection(conn *jsonrpcConn) {
+
+	conn.Lock()
+	defer conn.Unlock()
+
+	for id, subscription := range conn.subscriptions {
+		delete(conn.subscriptions, id)
+		this.remove(subscription)
+	}
+}
+
+func (this *jsonrpcSubscriptions) getList(subscriptionType api_code_types.SubscriptionType, key string) []*jsonrpcSubscription {
+
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	subsMap := this.getSubsMap(subscriptionType)
+	if len(subsMap[key]) == 0 {
+		return nil
+	}
+
+	list := make([]*jsonrpcSubscription, 0, len(subsMap[key]))
+	for subscription := range subsMap[key] {
+		list = append(list, subscription)
+	}
+	return list
+}
+
+func (this *jsonrpcSubscriptions) has(subscriptionType api_code_types.SubscriptionType, key string) bool {
+
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	return len(this.getSubsMap(subscriptionType)[key]) > 0
+}
+
+func (this *jsonrpcSubscriptions) notify(subscriptionType api_code_types.SubscriptionType, key []byte, element helpers.SerializableInterface, elementBytes []byte, extra any) {
+
+	list := this.getList(subscriptionType, string(key))
+	if list == nil {
+		return
+	}
+
+	result := &JSONRPCSubscriptionResult{subscriptionType, key, elementBytes, extra}
+	if element != nil {
+		result.Data = helpers.SerializeToBytes(element)
+	}
+
+	for _, subscription := range list {
+		_ = subscription.conn.writeJSON(&JSONRPCNotification{
+			JSONRPC_VERSION,
+			"subscription",
+			&JSONRPCSubscriptionNotification{subscription.Id, result},
+		})
+	}
+}
+
+func (this *jsonrpcSubscriptions) processSubscriptions() {
+
+	listeners := subscriptions.AddListeners(this.chain, this.mempool)
+	defer listeners.Remove()
+
+	listeners.Process(this.has, this.notify)
+}
+
+func newJSONRPCSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Mempool) (subs *jsonrpcSubscriptions) {
+
+	subs = &jsonrpcSubscriptions{
+		chain,
+		mempool,
+		0,
+		make(map[string]map[*jsonrpcSubscription]bool),
+		make(map[string]map[*jsonrpcSubscription]bool),
+		make(map[string]map[*jsonrpcSubscription]bool),
+		make(map[string]map[*jsonrpcSubscription]bool),
+		make(map[string]map[*jsonrpcSubscription]bool),
+		make(map[string]map[*jsonrpcSubscription]bool),
+		&sync.RWMutex{},
+	}
+
+	if network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
+		recovery.SafeGo(subs.processSubscriptions)
+	}
+
+	return
+}
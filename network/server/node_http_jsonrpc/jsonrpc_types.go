@@ -0,0 +1,72 @@
+package node_http_jsonrpc
+
+import (
+	"encoding/json"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_code/api_code_types"
+)
+
+const JSONRPC_VERSION = "2.0"
+
+const (
+	JSONRPC_MAX_BATCH = 100
+)
+
+const (
+	ERROR_PARSE            = -32700
+	ERROR_INVALID_REQUEST  = -32600
+	ERROR_METHOD_NOT_FOUND = -32601
+	ERROR_INVALID_PARAMS   = -32602
+	ERROR_INTERNAL         = -32603
+	ERROR_SERVER           = -32000
+)
+
+type JSONRPCRequest struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Id      json.RawMessage `json:"id,omitempty"`
+}
+
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type JSONRPCResponse struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+	Id      json.RawMessage `json:"id"`
+}
+
+type JSONRPCNotification struct {
+	Version string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type JSONRPCSubscriptionRequest struct {
+	Key  helpers.Base64                  `json:"key"`
+	Type api_code_types.SubscriptionType `json:"type"`
+}
+
+type JSONRPCUnsubscriptionRequest struct {
+	Subscription uint64 `json:"subscription"`
+}
+
+type JSONRPCSubscriptionResult struct {
+	Type  api_code_types.SubscriptionType `json:"type"`
+	Key   helpers.Base64                  `json:"key"`
+	Data  helpers.Base64                  `json:"data,omitempty"`
+	Extra any                             `json:"extra,omitempty"`
+}
+
+type JSONRPCSubscriptionNotification struct {
+	Subscription uint64                     `json:"subscription"`
+	Result       *JSONRPCSubscriptionResult `json:"result"`
+}
+
+func newError(code int, message string) *JSONRPCError {
+	return &JSONRPCError{code, message}
+}
@@ -0,0 +1,141 @@
+package node_http_jsonrpc
+
+import (
+	"encoding/json"
+	"github.com/tevino/abool"
+	"net/http"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/websocks/websock"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var connectionsCount int64 //use atomic
+
+type jsonrpcConn struct {
+	conn          *websock.Conn
+	authorization *api_code_types.Authorization //of the upgrade request
+	subscriptions map[uint64]*jsonrpcSubscription
+	closed        chan struct{}
+	isClosed      *abool.AtomicBool
+	writeLock     *sync.Mutex
+	sync.Mutex
+}
+
+func (c *jsonrpcConn) write(data []byte) error {
+
+	if c.isClosed.IsSet() {
+		return nil
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(network_config.WEBSOCKETS_TIMEOUT))
+	return c.conn.WriteMessage(websock.TextMessage, data)
+}
+
+func (c *jsonrpcConn) writeJSON(data any) error {
+	out, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return c.write(out)
+}
+
+func (c *jsonrpcConn) sendPings() {
+
+	pingTicker := time.NewTicker(network_config.WEBSOCKETS_PING_INTERVAL)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-pingTicker.C:
+			c.writeLock.Lock()
+			c.conn.SetWriteDeadline(time.Now().Add(network_config.WEBSOCKETS_TIMEOUT))
+			err := c.conn.WriteMessage(websock.PingMessage, nil)
+			c.writeLock.Unlock()
+			if err != nil {
+				c.conn.Close()
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+func (this *JSONRPCServer) readPump(c *jsonrpcConn) {
+
+	c.conn.SetReadLimit(int64(network_config.WEBSOCKETS_MAX_READ))
+	c.conn.SetReadDeadline(time.Now().Add(network_config.WEBSOCKETS_PONG_WAIT))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(network_config.WEBSOCKETS_PONG_WAIT))
+		return nil
+	})
+
+	//the requests are processed by at most WEBSOCKETS_MAX_READ_THREADS goroutines. The reading waits for a free one
+	threads := make(chan struct{}, network_config.WEBSOCKETS_MAX_READ_THREADS)
+
+	for {
+
+		_, read, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		threads <- struct{}{}
+
+		recovery.SafeGo(func() {
+			defer func() { <-threads }()
+			if out := this.process(read, c, c.authorization); out != nil {
+				_ = c.write(out)
+			}
+		})
+	}
+
+}
+
+// HandleUpgradeConnection serves JSON-RPC over websocket. Besides the http methods, it provides "subscribe" and "unsubscribe"
+func (this *JSONRPCServer) HandleUpgradeConnection(w http.ResponseWriter, r *http.Request) {
+
+	if atomic.LoadInt64(&connectionsCount) >= network_config.WEBSOCKETS_NETWORK_SERVER_MAX {
+		http.Error(w, "Too many websockets", 400)
+		return
+	}
+
+	conn, err := websock.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	atomic.AddInt64(&connectionsCount, 1)
+
+	c := &jsonrpcConn{
+		conn,
+		api_code_types.ParseAuthorization(r),
+		make(map[uint64]*jsonrpcSubscription),
+		make(chan struct{}),
+		abool.New(),
+		&sync.Mutex{},
+		sync.Mutex{},
+	}
+
+	recovery.SafeGo(func() {
+
+		defer func() {
+			c.isClosed.Set()
+			close(c.closed)
+			this.subscriptions.removeConnection(c)
+			conn.Close()
+			atomic.AddInt64(&connectionsCount, -1)
+		}()
+
+		recovery.SafeGo(c.sendPings)
+		this.readPump(c)
+	})
+
+}
@@ -0,0 +1,202 @@
+package subscriptions
+
+import (
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/api_implementation/api_common/api_types"
+	"pandora-pay/wallet/invoices"
+	"pandora-pay/wallet/scheduled_payments"
+)
+
+// Has returns true when the key of the subscription type has subscribers. The notifications are built only for these keys
+type Has func(subscriptionType api_code_types.SubscriptionType, key string) bool
+
+// Notify delivers a subscription notification to the subscribers of the key
+type Notify func(subscriptionType api_code_types.SubscriptionType, key []byte, element helpers.SerializableInterface, elementBytes []byte, extra any)
+
+// Listeners are the updates of the chain, the mempool, the invoices and the scheduled payments which are fanned out as subscription notifications.
+// The websockets subscriptions, the JSON-RPC subscriptions and the webhooks consume the same notifications
+type Listeners struct {
+	chain                *blockchain.Blockchain
+	mempool              *mempool.Mempool
+	NotificationsCn      chan *data_storage.DataStorage
+	TransactionsCn       chan []*blockchain_types.BlockchainTransactionUpdate
+	MempoolTransactionCn chan *blockchain_types.MempoolTransactionUpdate
+	InvoicesCn           chan *invoices.Invoice
+	ScheduledPaymentsCn  chan *scheduled_payments.ScheduledPaymentAlert
+}
+
+func AddListeners(chain *blockchain.Blockchain, mempool *mempool.Mempool) *Listeners {
+	return &Listeners{
+		chain,
+		mempool,
+		chain.UpdateSocketsSubscriptionsNotifications.AddListener(),
+		chain.UpdateSocketsSubscriptionsTransactions.AddListener(),
+		mempool.Txs.UpdateMempoolTransactions.AddListener(),
+		invoices.Invoices.UpdateInvoices.AddListener(),
+		scheduled_payments.ScheduledPayments.UpdateScheduledPayments.AddListener(),
+	}
+}
+
+func (listeners *Listeners) Remove() {
+	listeners.chain.UpdateSocketsSubscriptionsNotifications.RemoveChannel(listeners.NotificationsCn)
+	listeners.chain.UpdateSocketsSubscriptionsTransactions.RemoveChannel(listeners.TransactionsCn)
+	listeners.mempool.Txs.UpdateMempoolTransactions.RemoveChannel(listeners.MempoolTransactionCn)
+	invoices.Invoices.UpdateInvoices.RemoveChannel(listeners.InvoicesCn)
+	scheduled_payments.ScheduledPayments.UpdateScheduledPayments.RemoveChannel(listeners.ScheduledPaymentsCn)
+}
+
+// Process notifies the updates until one of the listeners is closed
+func (listeners *Listeners) Process(has Has, notify Notify) {
+	for {
+		select {
+		case dataStorage, ok := <-listeners.NotificationsCn:
+			if !ok {
+				return
+			}
+			NotifyDataStorage(dataStorage, has, notify)
+		case txsUpdates, ok := <-listeners.TransactionsCn:
+			if !ok {
+				return
+			}
+			NotifyTransactions(txsUpdates, has, notify)
+		case txUpdate, ok := <-listeners.MempoolTransactionCn:
+			if !ok {
+				return
+			}
+			NotifyMempoolTransaction(txUpdate, has, notify)
+		case invoice, ok := <-listeners.InvoicesCn:
+			if !ok {
+				return
+			}
+			NotifyInvoice(invoice, has, notify)
+		case alert, ok := <-listeners.ScheduledPaymentsCn:
+			if !ok {
+				return
+			}
+			NotifyScheduledPayment(alert, has, notify)
+		}
+	}
+}
+
+// NotifyDataStorage notifies the accounts, the plain accounts, the assets and the registrations committed by a block
+func NotifyDataStorage(dataStorage *data_storage.DataStorage, has Has, notify Notify) {
+
+	for _, accs := range dataStorage.AccsCollection.GetAllMaps() {
+		for k, v := range accs.HashMap.Committed {
+			if has(api_code_types.SUBSCRIPTION_ACCOUNT, k) {
+
+				var index uint64
+				if v.Element != nil {
+					index = v.Element.GetIndex()
+				}
+
+				notify(api_code_types.SUBSCRIPTION_ACCOUNT, []byte(k), v.Element, nil, &api_types.APISubscriptionNotificationAccountExtra{
+					accs.Asset,
+					index,
+				})
+			}
+		}
+	}
+
+	for k, v := range dataStorage.PlainAccs.HashMap.Committed {
+		if has(api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, k) {
+
+			var index uint64
+			if v.Element != nil {
+				index = v.Element.GetIndex()
+			}
+
+			notify(api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, []byte(k), v.Element, nil, &api_types.APISubscriptionNotificationPlainAccExtra{
+				index,
+			})
+		}
+	}
+
+	for k, v := range dataStorage.Asts.HashMap.Committed {
+		if has(api_code_types.SUBSCRIPTION_ASSET, k) {
+
+			var index uint64
+			if v.Element != nil {
+				index = v.Element.GetIndex()
+			}
+
+			notify(api_code_types.SUBSCRIPTION_ASSET, []byte(k), v.Element, nil, &api_types.APISubscriptionNotificationAssetExtra{
+				index,
+			})
+		}
+	}
+
+	for k, v := range dataStorage.Regs.HashMap.Committed {
+		if has(api_code_types.SUBSCRIPTION_REGISTRATION, k) {
+
+			var index uint64
+			if v.Element != nil {
+				index = v.Element.GetIndex()
+			}
+
+			notify(api_code_types.SUBSCRIPTION_REGISTRATION, []byte(k), v.Element, nil, &api_types.APISubscriptionNotificationRegistrationExtra{
+				index,
+			})
+		}
+	}
+}
+
+// NotifyTransactions notifies the txs included or removed from the chain
+func NotifyTransactions(txsUpdates []*blockchain_types.BlockchainTransactionUpdate, has Has, notify Notify) {
+	for _, v := range txsUpdates {
+		for _, key := range v.Keys {
+			if has(api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, string(key.PublicKey)) {
+				notify(api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, key.PublicKey, nil, v.TxHash, &api_types.APISubscriptionNotificationAccountTxExtra{
+					Blockchain: &api_types.APISubscriptionNotificationAccountTxExtraBlockchain{
+						v.Inserted, key.TxsCount, v.BlockHeight, v.BlockTimestamp, v.Height,
+					},
+				})
+			}
+		}
+
+		if has(api_code_types.SUBSCRIPTION_TRANSACTION, v.TxHashStr) {
+			notify(api_code_types.SUBSCRIPTION_TRANSACTION, v.TxHash, nil, nil, &api_types.APISubscriptionNotificationTxExtra{
+				Blockchain: &api_types.APISubscriptionNotificationTxExtraBlockchain{
+					v.Inserted, v.BlockHeight, v.BlockTimestamp, v.Height,
+				},
+			})
+		}
+	}
+}
+
+// NotifyMempoolTransaction notifies the txs inserted or removed from the mempool
+func NotifyMempoolTransaction(txUpdate *blockchain_types.MempoolTransactionUpdate, has Has, notify Notify) {
+
+	for key := range txUpdate.Keys {
+		if has(api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, key) {
+			notify(api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, []byte(key), nil, txUpdate.Tx.Bloom.Hash, &api_types.APISubscriptionNotificationAccountTxExtra{
+				Mempool: &api_types.APISubscriptionNotificationAccountTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
+			})
+		}
+	}
+
+	if has(api_code_types.SUBSCRIPTION_TRANSACTION, txUpdate.Tx.Bloom.HashStr) {
+		notify(api_code_types.SUBSCRIPTION_TRANSACTION, txUpdate.Tx.Bloom.Hash, nil, nil, &api_types.APISubscriptionNotificationTxExtra{
+			Mempool: &api_types.APISubscriptionNotificationTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
+		})
+	}
+}
+
+// NotifyInvoice notifies the invoice which received a payment or changed the status
+func NotifyInvoice(invoice *invoices.Invoice, has Has, notify Notify) {
+	if has(api_code_types.SUBSCRIPTION_INVOICE, string(invoice.Id)) {
+		notify(api_code_types.SUBSCRIPTION_INVOICE, invoice.Id, nil, nil, invoice)
+	}
+}
+
+// NotifyScheduledPayment notifies the scheduled payment which was skipped after all the attempts
+func NotifyScheduledPayment(alert *scheduled_payments.ScheduledPaymentAlert, has Has, notify Notify) {
+	if has(api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT, string(alert.Id)) {
+		notify(api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT, alert.Id, nil, nil, alert)
+	}
+}
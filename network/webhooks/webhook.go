@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"pandora-pay/helpers"
+	"pandora-pay/network/api_code/api_code_types"
+	"time"
+)
+
+type WebhookStatus struct {
+	Delivered     uint64    `json:"delivered" msgpack:"delivered"`
+	Failed        uint64    `json:"failed" msgpack:"failed"` //deliveries dropped after all the attempts
+	Pending       uint64    `json:"pending" msgpack:"pending"`
+	LastDelivered time.Time `json:"lastDelivered,omitempty" msgpack:"lastDelivered,omitempty"`
+	LastError     string    `json:"lastError,omitempty" msgpack:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime,omitempty" msgpack:"lastErrorTime,omitempty"`
+}
+
+type Webhook struct {
+	Id        helpers.Base64                  `json:"id" msgpack:"id"`
+	Type      api_code_types.SubscriptionType `json:"type" msgpack:"type"`
+	Key       helpers.Base64                  `json:"key" msgpack:"key"`
+	URL       string                          `json:"url" msgpack:"url"`
+	Secret    []byte                          `json:"-" msgpack:"secret"`
+	Timestamp time.Time                       `json:"timestamp" msgpack:"timestamp"`
+	Status    *WebhookStatus                  `json:"status" msgpack:"status"`
+}
+
+type WebhookDelivery struct {
+	Id          []byte    `json:"id" msgpack:"id"`
+	WebhookId   []byte    `json:"webhookId" msgpack:"webhookId"`
+	Payload     []byte    `json:"payload" msgpack:"payload"`
+	Attempts    uint32    `json:"attempts" msgpack:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt" msgpack:"nextAttempt"`
+}
+
+// WebhookPayload is the signed JSON body posted to the webhook url
+type WebhookPayload struct {
+	Id        helpers.Base64                  `json:"id"`
+	Webhook   helpers.Base64                  `json:"webhook"`
+	Type      api_code_types.SubscriptionType `json:"type"`
+	Key       helpers.Base64                  `json:"key"`
+	Data      helpers.Base64                  `json:"data,omitempty"`
+	Extra     any                             `json:"extra,omitempty"`
+	Timestamp int64                           `json:"timestamp"`
+}
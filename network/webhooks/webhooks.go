@@ -0,0 +1,495 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/tevino/abool"
+	"github.com/vmihailenco/msgpack/v5"
+	"net/http"
+	"pandora-pay/blockchain"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/mempool"
+	"pandora-pay/network/api_code/api_code_types"
+	"pandora-pay/network/network_config"
+	"pandora-pay/network/subscriptions"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type WebhooksType struct {
+	webhooks          *generics.Map[string, *Webhook]
+	queue             []*WebhookDelivery          //deliveries waiting to be sent
+	inFlight          map[string]*WebhookDelivery //deliveries being sent. They are not in the queue
+	lock              *sync.Mutex                 //queue, inFlight, changedDeliveries and statuses
+	saveLock          *sync.Mutex
+	changedDeliveries map[string]*WebhookDelivery //deliveries to be saved. nil means the delivery is removed
+	changedWebhooks   *abool.AtomicBool
+	newDeliveryCn     chan struct{}
+	client            *http.Client
+}
+
+var Webhooks *WebhooksType
+
+func copyWebhook(webhook *Webhook) *Webhook {
+	status := *webhook.Status
+	return &Webhook{webhook.Id, webhook.Type, webhook.Key, webhook.URL, webhook.Secret, webhook.Timestamp, &status}
+}
+
+// GetList returns the registered webhooks sorted by the moment they were registered
+func (this *WebhooksType) GetList() []*Webhook {
+
+	this.lock.Lock()
+	list := make([]*Webhook, 0)
+	this.webhooks.Range(func(key string, webhook *Webhook) bool {
+		list = append(list, copyWebhook(webhook))
+		return true
+	})
+	this.lock.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Timestamp.Before(list[j].Timestamp)
+	})
+
+	return list
+}
+
+// Register returns the webhook including the secret used to sign the payloads
+func (this *WebhooksType) Register(subscriptionType api_code_types.SubscriptionType, key []byte, urlStr string) (*Webhook, error) {
+
+	if subscriptionType == api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT || subscriptionType == api_code_types.SUBSCRIPTION_REGISTRATION {
+		return nil, errors.New("These subscriptions are automatically. They can't be subsribed manually")
+	}
+
+	if err := connection.CheckSubscriptionLength(key, subscriptionType); err != nil {
+		return nil, err
+	}
+
+	if err := checkURL(urlStr); err != nil {
+		return nil, err
+	}
+
+	count := 0
+	this.webhooks.Range(func(key string, webhook *Webhook) bool {
+		count++
+		return true
+	})
+	if count >= network_config.WEBHOOKS_MAX {
+		return nil, errors.New("Too many webhooks")
+	}
+
+	webhook := &Webhook{helpers.RandomBytes(16), subscriptionType, key, urlStr, helpers.RandomBytes(32), time.Now(), &WebhookStatus{}}
+	this.webhooks.Store(string(webhook.Id), webhook)
+	this.changedWebhooks.Set()
+	this.save()
+
+	return webhook, nil
+}
+
+func (this *WebhooksType) Unregister(id []byte) bool {
+
+	if _, deleted := this.webhooks.LoadAndDelete(string(id)); !deleted {
+		return false
+	}
+
+	this.lock.Lock()
+	queue := make([]*WebhookDelivery, 0, len(this.queue))
+	for _, delivery := range this.queue {
+		if bytes.Equal(delivery.WebhookId, id) {
+			this.changedDeliveries[string(delivery.Id)] = nil
+		} else {
+			queue = append(queue, delivery)
+		}
+	}
+	this.queue = queue
+	for key, delivery := range this.inFlight {
+		if bytes.Equal(delivery.WebhookId, id) {
+			delete(this.inFlight, key)
+			this.changedDeliveries[key] = nil
+		}
+	}
+	this.lock.Unlock()
+
+	this.changedWebhooks.Set()
+	this.save()
+	return true
+}
+
+func (this *WebhooksType) getMatching(subscriptionType api_code_types.SubscriptionType, key []byte) (list []*Webhook) {
+
+	//plain accounts and registrations are notified to the account webhooks
+	if subscriptionType == api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT || subscriptionType == api_code_types.SUBSCRIPTION_REGISTRATION {
+		subscriptionType = api_code_types.SUBSCRIPTION_ACCOUNT
+	}
+
+	this.webhooks.Range(func(id string, webhook *Webhook) bool {
+		if webhook.Type == subscriptionType && bytes.Equal(webhook.Key, key) {
+			list = append(list, webhook)
+		}
+		return true
+	})
+	return
+}
+
+func (this *WebhooksType) has(subscriptionType api_code_types.SubscriptionType, key string) bool {
+	return len(this.getMatching(subscriptionType, []byte(key))) > 0
+}
+
+func (this *WebhooksType) notify(subscriptionType api_code_types.SubscriptionType, key []byte, element helpers.SerializableInterface, elementBytes []byte, extra any) {
+
+	list := this.getMatching(subscriptionType, key)
+	if len(list) == 0 {
+		return
+	}
+
+	data := elementBytes
+	if element != nil {
+		data = helpers.SerializeToBytes(element)
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, webhook := range list {
+
+		payload := &WebhookPayload{helpers.RandomBytes(16), webhook.Id, subscriptionType, key, data, extra, time.Now().Unix()}
+		marshal, err := json.Marshal(payload)
+		if err != nil {
+			gui.GUI.Error("Error marshaling webhook payload", err)
+			continue
+		}
+
+		//the in flight deliveries are not in the queue, so the dropped one is never being sent
+		if len(this.queue) >= network_config.WEBHOOKS_QUEUE_MAX {
+			this.dropped(this.queue[0], "Queue is full")
+			this.changedDeliveries[string(this.queue[0].Id)] = nil
+			this.queue = this.queue[1:]
+		}
+
+		delivery := &WebhookDelivery{payload.Id, webhook.Id, marshal, 0, time.Now()}
+		this.queue = append(this.queue, delivery)
+		this.changedDeliveries[string(delivery.Id)] = delivery
+		webhook.Status.Pending++
+	}
+
+	this.changedWebhooks.Set()
+
+	select {
+	case this.newDeliveryCn <- struct{}{}:
+	default:
+	}
+}
+
+// is locked before
+func (this *WebhooksType) dropped(delivery *WebhookDelivery, message string) {
+	if webhook, ok := this.webhooks.Load(string(delivery.WebhookId)); ok {
+		webhook.Status.Pending--
+		webhook.Status.Failed++
+		webhook.Status.LastError = message
+		webhook.Status.LastErrorTime = time.Now()
+	}
+}
+
+func (this *WebhooksType) send(webhook *Webhook, delivery *WebhookDelivery) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), network_config.WEBHOOKS_TIMEOUT)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, webhook.Secret)
+	mac.Write(delivery.Payload)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Pandora-Delivery", hex.EncodeToString(delivery.Id))
+	req.Header.Set("X-Pandora-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	res, err := this.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.New("Webhook returned status " + strconv.Itoa(res.StatusCode))
+	}
+
+	return nil
+}
+
+func (this *WebhooksType) delivered(delivery *WebhookDelivery, err error) {
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	//the webhook was unregistered in the meantime
+	if this.inFlight[string(delivery.Id)] == nil {
+		return
+	}
+	delete(this.inFlight, string(delivery.Id))
+
+	webhook, ok := this.webhooks.Load(string(delivery.WebhookId))
+	if !ok {
+		this.changedDeliveries[string(delivery.Id)] = nil
+		return
+	}
+
+	this.changedWebhooks.Set()
+
+	if err == nil {
+		webhook.Status.Pending--
+		webhook.Status.Delivered++
+		webhook.Status.LastDelivered = time.Now()
+		this.changedDeliveries[string(delivery.Id)] = nil
+		return
+	}
+
+	delivery.Attempts++
+	if delivery.Attempts >= network_config.WEBHOOKS_MAX_ATTEMPTS {
+		this.dropped(delivery, err.Error())
+		this.changedDeliveries[string(delivery.Id)] = nil
+		return
+	}
+
+	webhook.Status.LastError = err.Error()
+	webhook.Status.LastErrorTime = time.Now()
+
+	backoff := network_config.WEBHOOKS_RETRY_INTERVAL << (delivery.Attempts - 1)
+	if backoff <= 0 || backoff > network_config.WEBHOOKS_RETRY_MAX_INTERVAL {
+		backoff = network_config.WEBHOOKS_RETRY_MAX_INTERVAL
+	}
+	delivery.NextAttempt = time.Now().Add(backoff)
+
+	this.queue = append(this.queue, delivery)
+	this.changedDeliveries[string(delivery.Id)] = delivery
+}
+
+func (this *WebhooksType) processDeliveries() {
+
+	workers := make(chan struct{}, network_config.WEBHOOKS_WORKERS)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-ticker.C:
+		case <-this.newDeliveryCn:
+		}
+
+		now := time.Now()
+
+		this.lock.Lock()
+		queue := make([]*WebhookDelivery, 0, len(this.queue))
+		for _, delivery := range this.queue {
+
+			if now.Before(delivery.NextAttempt) {
+				queue = append(queue, delivery)
+				continue
+			}
+
+			webhook, ok := this.webhooks.Load(string(delivery.WebhookId))
+			if !ok {
+				this.changedDeliveries[string(delivery.Id)] = nil
+				continue
+			}
+
+			full := false
+			select {
+			case workers <- struct{}{}:
+			default:
+				full = true
+			}
+			if full { //all workers are busy
+				queue = append(queue, delivery)
+				continue
+			}
+
+			this.inFlight[string(delivery.Id)] = delivery
+			recovery.SafeGo(func(webhook *Webhook, delivery *WebhookDelivery) func() {
+				return func() {
+					defer func() { <-workers }()
+					this.delivered(delivery, this.send(webhook, delivery))
+				}
+			}(webhook, delivery))
+		}
+		this.queue = queue
+		this.lock.Unlock()
+
+		this.save()
+	}
+}
+
+func getDeliveryKey(id []byte) string {
+	return "webhooksDelivery:" + hex.EncodeToString(id)
+}
+
+// save writes only the changed deliveries. The webhooks are written when they or their statuses changed
+func (this *WebhooksType) save() {
+
+	if store.StoreSettings == nil {
+		return
+	}
+
+	this.saveLock.Lock()
+	defer this.saveLock.Unlock()
+
+	this.lock.Lock()
+
+	changedWebhooks := this.changedWebhooks.SetToIf(true, false)
+	if !changedWebhooks && len(this.changedDeliveries) == 0 {
+		this.lock.Unlock()
+		return
+	}
+
+	changedDeliveries := this.changedDeliveries
+	this.changedDeliveries = make(map[string]*WebhookDelivery)
+
+	var err error
+	var webhooksMarshal []byte
+	deliveriesMarshal := make(map[string][]byte)
+
+	if changedWebhooks {
+		list := make([]*Webhook, 0)
+		this.webhooks.Range(func(key string, webhook *Webhook) bool {
+			list = append(list, copyWebhook(webhook))
+			return true
+		})
+		webhooksMarshal, err = msgpack.Marshal(list)
+	}
+
+	for id, delivery := range changedDeliveries {
+		if err != nil {
+			break
+		}
+		if delivery == nil {
+			deliveriesMarshal[id] = nil
+		} else {
+			deliveriesMarshal[id], err = msgpack.Marshal(delivery)
+		}
+	}
+
+	this.lock.Unlock()
+
+	if err == nil {
+		err = store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+			if webhooksMarshal != nil {
+				writer.Put("webhooks", webhooksMarshal)
+			}
+			for id, data := range deliveriesMarshal {
+				if data == nil {
+					writer.Delete(getDeliveryKey([]byte(id)))
+				} else {
+					writer.Put(getDeliveryKey([]byte(id)), data)
+				}
+			}
+			return
+		})
+	}
+
+	if err != nil {
+		gui.GUI.Error("Error saving webhooks", err)
+
+		//the changes are saved next time
+		this.lock.Lock()
+		for id, delivery := range changedDeliveries {
+			if _, found := this.changedDeliveries[id]; !found {
+				this.changedDeliveries[id] = delivery
+			}
+		}
+		this.lock.Unlock()
+		if changedWebhooks {
+			this.changedWebhooks.Set()
+		}
+	}
+}
+
+func (this *WebhooksType) load() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if data := reader.Get("webhooks"); data != nil {
+			list := []*Webhook{}
+			if err = msgpack.Unmarshal(data, &list); err != nil {
+				return
+			}
+			for _, webhook := range list {
+				webhook.Status.Pending = 0
+				this.webhooks.Store(string(webhook.Id), webhook)
+			}
+		}
+
+		reader.IteratePrefix("webhooksDelivery:", "", func(key string, value []byte) bool {
+
+			delivery := &WebhookDelivery{}
+			if err = msgpack.Unmarshal(value, delivery); err != nil {
+				return false
+			}
+
+			if webhook, ok := this.webhooks.Load(string(delivery.WebhookId)); ok {
+				webhook.Status.Pending++
+				this.queue = append(this.queue, delivery)
+			} else {
+				this.changedDeliveries[string(delivery.Id)] = nil
+			}
+			return true
+		})
+		if err != nil {
+			return
+		}
+
+		sort.SliceStable(this.queue, func(i, j int) bool {
+			return this.queue[i].NextAttempt.Before(this.queue[j].NextAttempt)
+		})
+
+		return
+	})
+}
+
+// Initialize loads the webhooks and their delivery queue saved in the settings store and starts delivering
+func (this *WebhooksType) Initialize(chain *blockchain.Blockchain, mempool *mempool.Mempool) error {
+
+	if err := this.load(); err != nil {
+		return err
+	}
+
+	gui.GUI.Log("Webhooks loaded " + strconv.Itoa(len(this.GetList())) + " with " + strconv.Itoa(len(this.queue)) + " pending deliveries")
+
+	listeners := subscriptions.AddListeners(chain, mempool)
+	recovery.SafeGo(func() {
+		defer listeners.Remove()
+		listeners.Process(this.has, this.notify)
+	})
+	recovery.SafeGo(this.processDeliveries)
+
+	return nil
+}
+
+func init() {
+	Webhooks = &WebhooksType{
+		&generics.Map[string, *Webhook]{},
+		[]*WebhookDelivery{},
+		make(map[string]*WebhookDelivery),
+		&sync.Mutex{},
+		&sync.Mutex{},
+		make(map[string]*WebhookDelivery),
+		abool.New(),
+		make(chan struct{}, 1),
+		newClient(),
+	}
+}
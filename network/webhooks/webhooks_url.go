@@ -0,0 +1,118 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"pandora-pay/network/network_config"
+	"strings"
+	"time"
+)
+
+// isAllowedHost returns true for the hosts of --webhooks-allowed-hosts. They may resolve to private addresses
+func isAllowedHost(host string) bool {
+	for _, allowed := range network_config.WEBHOOKS_ALLOWED_HOSTS {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIP rejects the loopback, link-local, private (RFC1918 and ULA), unspecified and multicast addresses
+func checkIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsPrivate() || ip.IsUnspecified() {
+		return errors.New("Webhook address " + ip.String() + " is not public")
+	}
+	return nil
+}
+
+// resolve returns the addresses of the host only if all of them are public
+func resolve(ctx context.Context, host string) ([]net.IP, error) {
+
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, checkIP(ip)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, errors.New("Webhook host was not resolved")
+	}
+
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		if err = checkIP(addr.IP); err != nil {
+			return nil, err
+		}
+		ips[i] = addr.IP
+	}
+	return ips, nil
+}
+
+// checkURL validates the webhook url and that its host resolves to public addresses
+func checkURL(urlStr string) error {
+
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("Invalid url")
+	}
+
+	if isAllowedHost(u.Hostname()) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), network_config.WEBHOOKS_TIMEOUT)
+	defer cancel()
+
+	_, err = resolve(ctx, u.Hostname())
+	return err
+}
+
+// dialContext checks again the resolved addresses when connecting, as the DNS may change after the webhook was registered.
+// It is also used for the redirects
+func dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: network_config.WEBHOOKS_TIMEOUT, KeepAlive: 30 * time.Second}
+
+	if isAllowedHost(host) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	ips, err := resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		var conn net.Conn
+		if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+func newClient() *http.Client {
+	return &http.Client{
+		Timeout: network_config.WEBHOOKS_TIMEOUT,
+		Transport: &http.Transport{
+			DialContext:         dialContext,
+			TLSHandshakeTimeout: network_config.WEBHOOKS_TIMEOUT,
+			MaxIdleConns:        network_config.WEBHOOKS_WORKERS,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/network/known_nodes/known_node"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/network/websocks/websock"
 	"sync"
@@ -29,7 +30,7 @@ const (
 var uuidGenerator uint32 //use atomic
 
 type AdvancedConnection struct {
-	Authenticated            *abool.AtomicBool
+	Authenticated            *generics.Value[*network_config_auth.AuthToken]
 	UUID                     advanced_connection_types.UUID
 	Conn                     *websock.Conn
 	Handshake                *ConnectionHandshake
@@ -338,7 +339,7 @@ func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *kno
 	}
 
 	advancedConnection := &AdvancedConnection{
-		abool.New(),
+		&generics.Value[*network_config_auth.AuthToken]{},
 		uuid,
 		conn,
 		nil,
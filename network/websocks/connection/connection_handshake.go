@@ -12,6 +12,7 @@ type ConnectionHandshake struct {
 	Network   uint64                   `json:"network" msgpack:"network"`
 	Consensus config.NodeConsensusType `json:"consensus" msgpack:"consensus"`
 	URL       string                   `json:"url" msgpack:"url"`
+	Pruned    uint64                   `json:"pruned,omitempty" msgpack:"pruned,omitempty"` //number of latest blocks with transactions kept by a pruned node. 0 means all blocks are kept
 }
 
 func (handshake *ConnectionHandshake) ValidateHandshake() (*semver.Version, error) {
@@ -35,3 +36,8 @@ func (handshake *ConnectionHandshake) ValidateHandshake() (*semver.Version, erro
 
 	return &version, nil
 }
+
+// CanProvideBlockComplete returns false in case the block was pruned by the node
+func (handshake *ConnectionHandshake) CanProvideBlockComplete(height, chainHeight uint64) bool {
+	return handshake.Pruned == 0 || height+handshake.Pruned >= chainHeight
+}
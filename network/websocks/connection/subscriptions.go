@@ -5,6 +5,7 @@ import (
 	"errors"
 	"golang.org/x/exp/slices"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_wallet"
 	"pandora-pay/cryptography"
 	"pandora-pay/network/api_code/api_code_types"
 	"pandora-pay/network/network_config"
@@ -20,7 +21,7 @@ type Subscriptions struct {
 	sync.Mutex
 }
 
-func checkSubscriptionLength(key []byte, subscriptionType api_code_types.SubscriptionType) error {
+func CheckSubscriptionLength(key []byte, subscriptionType api_code_types.SubscriptionType) error {
 	var length int
 	switch subscriptionType {
 	case api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_code_types.SUBSCRIPTION_REGISTRATION:
@@ -29,6 +30,10 @@ func checkSubscriptionLength(key []byte, subscriptionType api_code_types.Subscri
 		length = config_coins.ASSET_LENGTH
 	case api_code_types.SUBSCRIPTION_TRANSACTION:
 		length = cryptography.HashSize
+	case api_code_types.SUBSCRIPTION_INVOICE:
+		length = config_wallet.INVOICE_ID_LENGTH
+	case api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT:
+		length = config_wallet.SCHEDULED_PAYMENT_ID_LENGTH
 	}
 	if len(key) != length {
 		return errors.New("Key is invalid")
@@ -42,7 +47,7 @@ func (s *Subscriptions) AddSubscription(subscriptionType api_code_types.Subscrip
 		return errors.New("These subscriptions are automatically. They can't be subsribed manually")
 	}
 
-	if err := checkSubscriptionLength(key, subscriptionType); err != nil {
+	if err := CheckSubscriptionLength(key, subscriptionType); err != nil {
 		return err
 	}
 
@@ -71,7 +76,7 @@ func (s *Subscriptions) AddSubscription(subscriptionType api_code_types.Subscrip
 
 func (s *Subscriptions) RemoveSubscription(subscriptionType api_code_types.SubscriptionType, key []byte) error {
 
-	if err := checkSubscriptionLength(key, subscriptionType); err != nil {
+	if err := CheckSubscriptionLength(key, subscriptionType); err != nil {
 		return err
 	}
 
@@ -7,8 +7,8 @@ import (
 	"pandora-pay/helpers/recovery"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api_code/api_code_types"
-	"pandora-pay/network/api_implementation/api_common/api_types"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/subscriptions"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 )
@@ -23,6 +23,8 @@ type WebsocketSubscriptions struct {
 	accountsTransactionsSubscriptions map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	assetsSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	transactionsSubscriptions         map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	invoicesSubscriptions             map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	scheduledPaymentsSubscriptions    map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 }
 
 func newWebsocketSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Mempool) (subs *WebsocketSubscriptions) {
@@ -35,6 +37,8 @@ func newWebsocketSubscriptions(chain *blockchain.Blockchain, mempool *mempool.Me
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 	}
 
 	if network_config.NETWORK_ENABLE_SUBSCRIPTIONS {
@@ -103,6 +107,10 @@ func (this *WebsocketSubscriptions) getSubsMap(subscriptionType api_code_types.S
 		subsMap = this.assetsSubscriptions
 	case api_code_types.SUBSCRIPTION_TRANSACTION:
 		subsMap = this.transactionsSubscriptions
+	case api_code_types.SUBSCRIPTION_INVOICE:
+		subsMap = this.invoicesSubscriptions
+	case api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT:
+		subsMap = this.scheduledPaymentsSubscriptions
 	}
 	return
 }
@@ -125,16 +133,18 @@ func (this *WebsocketSubscriptions) removeConnection(conn *connection.AdvancedCo
 	}
 }
 
-func (this *WebsocketSubscriptions) processSubscriptions() {
+func (this *WebsocketSubscriptions) has(subscriptionType api_code_types.SubscriptionType, key string) bool {
+	return len(this.getSubsMap(subscriptionType)[key]) > 0
+}
 
-	updateNotificationsCn := this.chain.UpdateSocketsSubscriptionsNotifications.AddListener()
-	defer this.chain.UpdateSocketsSubscriptionsNotifications.RemoveChannel(updateNotificationsCn)
+func (this *WebsocketSubscriptions) notify(subscriptionType api_code_types.SubscriptionType, key []byte, element helpers.SerializableInterface, elementBytes []byte, extra any) {
+	this.send(subscriptionType, []byte("sub/notify"), key, this.getSubsMap(subscriptionType)[string(key)], element, elementBytes, extra)
+}
 
-	updateTransactionsCn := this.chain.UpdateSocketsSubscriptionsTransactions.AddListener()
-	defer this.chain.UpdateSocketsSubscriptionsTransactions.RemoveChannel(updateTransactionsCn)
+func (this *WebsocketSubscriptions) processSubscriptions() {
 
-	updateMempoolTransactionsCn := this.mempool.Txs.UpdateMempoolTransactions.AddListener()
-	defer this.mempool.Txs.UpdateMempoolTransactions.RemoveChannel(updateMempoolTransactionsCn)
+	listeners := subscriptions.AddListeners(this.chain, this.mempool)
+	defer listeners.Remove()
 
 	var subsMap map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 
@@ -167,112 +177,40 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				}
 			}
 
-		case dataStorage := <-updateNotificationsCn:
-
-			accsMap := dataStorage.AccsCollection.GetAllMaps()
-
-			for _, accs := range accsMap {
-				for k, v := range accs.HashMap.Committed {
-					if list := this.accountsSubscriptions[k]; list != nil {
-
-						var index uint64
-						if v.Element != nil {
-							index = v.Element.GetIndex()
-						}
-
-						this.send(api_code_types.SUBSCRIPTION_ACCOUNT, []byte("sub/notify"), []byte(k), list, v.Element, nil, &api_types.APISubscriptionNotificationAccountExtra{
-							accs.Asset,
-							index,
-						})
-					}
-				}
+		case dataStorage, ok := <-listeners.NotificationsCn:
+			if !ok {
+				return
 			}
 
-			for k, v := range dataStorage.PlainAccs.HashMap.Committed {
-				if list := this.accountsSubscriptions[k]; list != nil {
+			subscriptions.NotifyDataStorage(dataStorage, this.has, this.notify)
 
-					var index uint64
-					if v.Element != nil {
-						index = v.Element.GetIndex()
-					}
-
-					this.send(api_code_types.SUBSCRIPTION_PLAIN_ACCOUNT, []byte("sub/notify"), []byte(k), list, v.Element, nil, &api_types.APISubscriptionNotificationPlainAccExtra{
-						index,
-					})
-				}
+		case txsUpdates, ok := <-listeners.TransactionsCn:
+			if !ok {
+				return
 			}
 
-			for k, v := range dataStorage.Asts.HashMap.Committed {
-				if list := this.assetsSubscriptions[k]; list != nil {
+			subscriptions.NotifyTransactions(txsUpdates, this.has, this.notify)
 
-					var index uint64
-					if v.Element != nil {
-						index = v.Element.GetIndex()
-					}
-
-					this.send(api_code_types.SUBSCRIPTION_ASSET, []byte("sub/notify"), []byte(k), list, v.Element, nil, &api_types.APISubscriptionNotificationAssetExtra{
-						index,
-					})
-				}
+		case txUpdate, ok := <-listeners.MempoolTransactionCn:
+			if !ok {
+				return
 			}
 
-			for k, v := range dataStorage.Regs.HashMap.Committed {
-				if list := this.accountsSubscriptions[k]; list != nil {
-
-					var index uint64
-					if v.Element != nil {
-						index = v.Element.GetIndex()
-					}
-
-					this.send(api_code_types.SUBSCRIPTION_REGISTRATION, []byte("sub/notify"), []byte(k), list, v.Element, nil, &api_types.APISubscriptionNotificationRegistrationExtra{
-						index,
-					})
-				}
-			}
+			subscriptions.NotifyMempoolTransaction(txUpdate, this.has, this.notify)
 
-		case txsUpdates, ok := <-updateTransactionsCn:
+		case invoice, ok := <-listeners.InvoicesCn:
 			if !ok {
 				return
 			}
 
-			for _, v := range txsUpdates {
-				for _, key := range v.Keys {
-					if list := this.accountsTransactionsSubscriptions[string(key.PublicKey)]; list != nil {
-						this.send(api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, []byte("sub/notify"), key.PublicKey, list, nil, v.TxHash, &api_types.APISubscriptionNotificationAccountTxExtra{
-							Blockchain: &api_types.APISubscriptionNotificationAccountTxExtraBlockchain{
-								v.Inserted, key.TxsCount, v.BlockHeight, v.BlockTimestamp, v.Height,
-							},
-						})
-					}
-				}
-
-				if list := this.transactionsSubscriptions[v.TxHashStr]; list != nil {
-					this.send(api_code_types.SUBSCRIPTION_TRANSACTION, []byte("sub/notify"), v.TxHash, list, nil, nil, &api_types.APISubscriptionNotificationTxExtra{
-						Blockchain: &api_types.APISubscriptionNotificationTxExtraBlockchain{
-							v.Inserted, v.BlockHeight, v.BlockTimestamp, v.Height,
-						},
-					})
-				}
-			}
+			subscriptions.NotifyInvoice(invoice, this.has, this.notify)
 
-		case txUpdate, ok := <-updateMempoolTransactionsCn:
+		case alert, ok := <-listeners.ScheduledPaymentsCn:
 			if !ok {
 				return
 			}
 
-			for key := range txUpdate.Keys {
-				if list := this.accountsTransactionsSubscriptions[key]; list != nil {
-					this.send(api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, []byte("sub/notify"), []byte(key), list, nil, txUpdate.Tx.Bloom.Hash, &api_types.APISubscriptionNotificationAccountTxExtra{
-						Mempool: &api_types.APISubscriptionNotificationAccountTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
-					})
-				}
-			}
-
-			if list := this.transactionsSubscriptions[txUpdate.Tx.Bloom.HashStr]; list != nil {
-				this.send(api_code_types.SUBSCRIPTION_TRANSACTION, []byte("sub/notify"), txUpdate.Tx.Bloom.Hash, list, nil, nil, &api_types.APISubscriptionNotificationTxExtra{
-					Mempool: &api_types.APISubscriptionNotificationTxExtraMempool{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification},
-				})
-			}
+			subscriptions.NotifyScheduledPayment(alert, this.has, this.notify)
 
 		case conn, ok := <-this.websocketClosedCn:
 			if !ok {
@@ -283,6 +221,8 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_ASSET)
 			this.removeConnection(conn, api_code_types.SUBSCRIPTION_TRANSACTION)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_INVOICE)
+			this.removeConnection(conn, api_code_types.SUBSCRIPTION_SCHEDULED_PAYMENT)
 
 		}
 
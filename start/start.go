@@ -2,6 +2,8 @@ package start
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -17,17 +19,21 @@ import (
 	"pandora-pay/config/config_forging"
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography/crypto/balance_decryptor"
+	"pandora-pay/explorer"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/debugging_pprof"
 	"pandora-pay/mempool"
 	"pandora-pay/network"
 	"pandora-pay/network/network_config"
+	"pandora-pay/network/network_config/network_config_auth"
 	"pandora-pay/settings"
 	"pandora-pay/store"
 	"pandora-pay/testnet"
 	"pandora-pay/txs_builder"
 	"pandora-pay/txs_validator"
 	"pandora-pay/wallet"
+	"pandora-pay/wallet/invoices"
+	"pandora-pay/wallet/scheduled_payments"
 	"runtime"
 	"strconv"
 	"syscall"
@@ -40,6 +46,13 @@ func StartMainNow() (err error) {
 	}
 
 	arguments.VERSION_STRING = config.VERSION_STRING
+
+	if arguments.Arguments["--auth-hash-password"] != nil {
+		fmt.Println(network_config_auth.HashPassword(arguments.Arguments["--auth-hash-password"].(string)))
+		os.Exit(0)
+		return
+	}
+
 	if arguments.Arguments["--pprof"] == true {
 		if err = debugging_pprof.Start(); err != nil {
 			return
@@ -56,6 +69,31 @@ func StartMainNow() (err error) {
 	}
 	globals.MainEvents.BroadcastEvent("main", "database initialized")
 
+	if arguments.Arguments["--store-restore"] != nil {
+		var restored *store.Store
+		var count uint64
+		if restored, count, err = store.RestoreFromFile(arguments.Arguments["--store-restore"].(string)); err != nil {
+			return
+		}
+		gui.GUI.Log(fmt.Sprintf("Store %s restored with %d entries", restored.Name, count))
+	}
+
+	migrateBackupDir := ""
+	if arguments.Arguments["--store-migrate-backup"] != nil {
+		migrateBackupDir = arguments.Arguments["--store-migrate-backup"].(string)
+	}
+	if err = store.StoreBlockchain.Migrate(blockchain.ChainMigrations, arguments.Arguments["--store-migrate-dry-run"] == true, migrateBackupDir); err != nil {
+		return
+	}
+	if arguments.Arguments["--store-migrate-dry-run"] == true {
+		if err = store.DBClose(); err != nil {
+			return
+		}
+		os.Exit(0)
+		return
+	}
+	globals.MainEvents.BroadcastEvent("main", "database migrated")
+
 	if err = txs_validator.NewTxsValidator(); err != nil {
 		return
 	}
@@ -93,10 +131,62 @@ func StartMainNow() (err error) {
 	if err = genesis.GenesisInit(app.Wallet.GetFirstAddressForDevnetGenesisAirdrop); err != nil {
 		return
 	}
+	if arguments.Arguments["--snapshot-import"] != nil {
+		if arguments.Arguments["--snapshot-import-hash"] == nil {
+			return errors.New("--snapshot-import-hash is required to import a snapshot")
+		}
+		var hash []byte
+		if hash, err = hex.DecodeString(arguments.Arguments["--snapshot-import-hash"].(string)); err != nil {
+			return errors.New("--snapshot-import-hash is invalid")
+		}
+		if err = app.Chain.ImportSnapshot(arguments.Arguments["--snapshot-import"].(string), hash); err != nil {
+			return
+		}
+		globals.MainEvents.BroadcastEvent("main", "snapshot imported")
+	}
+
 	if err = app.Chain.InitializeChain(); err != nil {
 		return
 	}
 
+	if arguments.Arguments["--snapshot-export"] != nil {
+		var snapshot *blockchain.Snapshot
+		if snapshot, err = app.Chain.ExportSnapshot(arguments.Arguments["--snapshot-export"].(string)); err != nil {
+			return
+		}
+		gui.GUI.Log(fmt.Sprintf("Snapshot exported at height %d with hash %s", snapshot.Height, hex.EncodeToString(snapshot.Hash)))
+	}
+
+	if arguments.Arguments["--verify-chain"] == true {
+		var verification *blockchain.ChainVerification
+		if verification, err = app.Chain.VerifyChain(); err != nil {
+			return
+		}
+		if verification.Diverged {
+			gui.GUI.Error(fmt.Sprintf("Chain state diverges at height %d. %s", verification.DivergedHeight, verification.Reason))
+		} else {
+			gui.GUI.Log(fmt.Sprintf("Chain verified. %d blocks replayed and %d state entries match", verification.Height, verification.Keys))
+		}
+		if err = store.DBClose(); err != nil {
+			return
+		}
+		if verification.Diverged {
+			os.Exit(1)
+		}
+		os.Exit(0)
+		return
+	}
+
+	if err = app.Mempool.LoadStoredTxs(app.Chain.GetChainData().Height); err != nil {
+		return
+	}
+	globals.MainEvents.BroadcastEvent("main", "mempool stored txs loaded")
+
+	if config.NODE_EXPLORER_INDEXER {
+		app.Explorer = explorer.CreateExplorer(app.Chain)
+		globals.MainEvents.BroadcastEvent("main", "explorer initialized")
+	}
+
 	if runtime.GOARCH != "wasm" && arguments.Arguments["--balance-decryptor-disable-init"] == false {
 		tableSize := 0
 		if arguments.Arguments["--balance-decryptor-table-size"] != nil {
@@ -129,6 +219,16 @@ func StartMainNow() (err error) {
 	}
 	globals.MainEvents.BroadcastEvent("main", "transactions builder initialized")
 
+	if err = scheduled_payments.Initialize(app.Wallet, app.Mempool, app.Chain); err != nil {
+		return
+	}
+	globals.MainEvents.BroadcastEvent("main", "scheduled payments initialized")
+
+	if err = invoices.Initialize(app.Wallet, app.Chain); err != nil {
+		return
+	}
+	globals.MainEvents.BroadcastEvent("main", "invoices initialized")
+
 	app.Forging.InitializeForging(txs_builder.TxsBuilder.CreateForgingTransactions, app.Chain.NextBlockCreatedCn, app.Chain.UpdateNewChainUpdate, app.Chain.ForgingSolutionCn)
 
 	if config_forging.FORGING_ENABLED {
@@ -103,6 +103,80 @@ func (hashMap *HashMap[T]) GetRandom() (T, error) {
 	return hashMap.GetByIndex(index)
 }
 
+// support only for commited data
+// Iterate visits the elements in ascending order of their keys starting after cursor. An empty cursor starts with the first key. The iteration stops once the callback returns false
+func (hashMap *HashMap[T]) Iterate(cursor string, callback func(key string, element T) (bool, error)) (err error) {
+
+	if hashMap.changed {
+		return errors.New("Iterate is supported only when is committed")
+	}
+
+	prefix := hashMap.name + ":map:"
+	if cursor != "" {
+		cursor = prefix + cursor
+	}
+
+	hashMap.Tx.IteratePrefix(prefix, cursor, func(storeKey string, data []byte) bool {
+
+		key := storeKey[len(prefix):]
+
+		var index uint64
+		if hashMap.Indexable {
+			//safe because the bytes will be converted into an integer
+			indexData := hashMap.Tx.Get(hashMap.name + ":listKeys:" + key)
+			if indexData == nil {
+				err = errors.New("Key not found")
+				return false
+			}
+			if index, err = strconv.ParseUint(string(indexData), 10, 64); err != nil {
+				return false
+			}
+		}
+
+		var element T
+		if element, err = hashMap.deserialize([]byte(key), data, index); err != nil {
+			return false
+		}
+
+		var next bool
+		next, err = callback(key, element)
+		return next && err == nil
+	})
+
+	return
+}
+
+// support only for commited data
+// GetKeys returns at most limit keys greater than cursor and the cursor of the next page. The returned cursor is empty when there are no more keys
+func (hashMap *HashMap[T]) GetKeys(cursor string, limit int) ([][]byte, string, error) {
+
+	if hashMap.changed {
+		return nil, "", errors.New("GetKeys is supported only when is committed")
+	}
+	if limit <= 0 {
+		return nil, "", errors.New("Invalid limit")
+	}
+
+	prefix := hashMap.name + ":map:"
+	if cursor != "" {
+		cursor = prefix + cursor
+	}
+
+	keys := make([][]byte, 0, limit)
+	next := ""
+
+	hashMap.Tx.IteratePrefix(prefix, cursor, func(storeKey string, data []byte) bool {
+		if len(keys) == limit {
+			next = string(keys[limit-1])
+			return false
+		}
+		keys = append(keys, []byte(storeKey[len(prefix):]))
+		return true
+	})
+
+	return keys, next, nil
+}
+
 func (hashMap *HashMap[T]) Get(key string) (out T, err error) {
 
 	if hashMap.keyLength != 0 && len(key) != hashMap.keyLength {
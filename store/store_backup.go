@@ -0,0 +1,263 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/crypto/sha3"
+	"hash"
+	"io"
+	"math"
+	"os"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+const STORE_BACKUP_MAGIC = "PANDORA-STORE-BACKUP"
+const STORE_BACKUP_VERSION = uint64(0)
+
+type storeBackupWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (writer *storeBackupWriter) write(data []byte) {
+	if writer.err == nil {
+		_, writer.err = writer.w.Write(data)
+	}
+}
+
+func (writer *storeBackupWriter) writeUvarint(value uint64) {
+	writer.write(binary.AppendUvarint(nil, value))
+}
+
+func (writer *storeBackupWriter) writeVariableBytes(data []byte) {
+	writer.writeUvarint(uint64(len(data)))
+	writer.write(data)
+}
+
+type storeBackupReader struct {
+	r    *bufio.Reader
+	hash hash.Hash
+}
+
+func (reader *storeBackupReader) ReadByte() (byte, error) {
+	b, err := reader.r.ReadByte()
+	if err == nil {
+		reader.hash.Write([]byte{b})
+	}
+	return b, err
+}
+
+func (reader *storeBackupReader) readBytes(count uint64) ([]byte, error) {
+	data := make([]byte, count)
+	if _, err := io.ReadFull(reader.r, data); err != nil {
+		return nil, err
+	}
+	reader.hash.Write(data)
+	return data, nil
+}
+
+func (reader *storeBackupReader) readVariableBytes(limit uint64) ([]byte, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	if length > limit {
+		return nil, errors.New("Variable bytes exceeding maximum length")
+	}
+	return reader.readBytes(length)
+}
+
+// GetStore returns the store which can be backed up by its name
+func GetStore(name string) *Store {
+	for _, store := range []*Store{StoreBlockchain, StoreWallet, StoreSettings, StoreBalancesDecrypted} {
+		if store != nil && store.Name == name {
+			return store
+		}
+	}
+	return nil
+}
+
+// Backup writes all the entries of the store into a gzip compressed archive. The entries are read from a single View, so the backup is consistent.
+// The entries are copied as they are stored. The wallet entries are stored encrypted by the WalletEncryption, so the wallet backups remain encrypted.
+// Archive layout: [magic][version][store name] then [1][key][value] for every entry and [0][count][sha3 of everything before]
+func (store *Store) Backup(w io.Writer) (count uint64, err error) {
+
+	compressed := gzip.NewWriter(w)
+	hash := sha3.New256()
+	writer := &storeBackupWriter{io.MultiWriter(compressed, hash), nil}
+
+	writer.write([]byte(STORE_BACKUP_MAGIC))
+	writer.writeUvarint(STORE_BACKUP_VERSION)
+	writer.writeVariableBytes([]byte(store.Name))
+
+	if err = store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		reader.IterateRange("", "", func(key string, value []byte) bool {
+			writer.write([]byte{1})
+			writer.writeVariableBytes([]byte(key))
+			writer.writeVariableBytes(value)
+			count += 1
+			return writer.err == nil
+		})
+		return writer.err
+	}); err != nil {
+		return
+	}
+
+	writer.write([]byte{0})
+	writer.writeUvarint(count)
+	writer.w = compressed
+	writer.write(hash.Sum(nil))
+
+	if writer.err != nil {
+		return 0, writer.err
+	}
+
+	err = compressed.Close()
+	return
+}
+
+// BackupToFile writes the backup into a temporary file which is renamed once the backup is complete
+func (store *Store) BackupToFile(path string) (count uint64, err error) {
+
+	file, err := os.OpenFile(path+".tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return
+	}
+
+	buf := bufio.NewWriterSize(file, 1<<20)
+	if count, err = store.Backup(buf); err == nil {
+		if err = buf.Flush(); err == nil {
+			err = file.Sync()
+		}
+	}
+
+	if err2 := file.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return 0, err
+	}
+
+	return count, os.Rename(path+".tmp", path)
+}
+
+// Restore replaces all the entries of the store written in the archive. The changes are committed only when the entire archive is verified
+func Restore(r io.Reader) (store *Store, count uint64, err error) {
+
+	compressed, err := gzip.NewReader(r)
+	if err != nil {
+		return
+	}
+	defer compressed.Close()
+
+	reader := &storeBackupReader{bufio.NewReaderSize(compressed, 1<<20), sha3.New256()}
+
+	magic, err := reader.readBytes(uint64(len(STORE_BACKUP_MAGIC)))
+	if err != nil {
+		return
+	}
+	if string(magic) != STORE_BACKUP_MAGIC {
+		return nil, 0, errors.New("Invalid store backup")
+	}
+
+	version, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return
+	}
+	if version != STORE_BACKUP_VERSION {
+		return nil, 0, errors.New("Store backup version is not supported")
+	}
+
+	name, err := reader.readVariableBytes(255)
+	if err != nil {
+		return
+	}
+	if store = GetStore(string(name)); store == nil {
+		return nil, 0, errors.New("Store " + string(name) + " can not be restored")
+	}
+
+	//the error is kept separately as not all the stores return the error of the callback
+	var restoreErr error
+	if err = store.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		restoreErr = func() error {
+
+			keys := []string{}
+			writer.IterateRange("", "", func(key string, value []byte) bool {
+				keys = append(keys, key)
+				return true
+			})
+			for _, key := range keys {
+				writer.Delete(key)
+			}
+
+			for {
+				flag, err := reader.ReadByte()
+				if err != nil {
+					return err
+				}
+				if flag == 0 {
+					break
+				}
+				if flag != 1 {
+					return errors.New("Invalid store backup entry")
+				}
+
+				key, err := reader.readVariableBytes(math.MaxUint32)
+				if err != nil {
+					return err
+				}
+				value, err := reader.readVariableBytes(math.MaxUint32)
+				if err != nil {
+					return err
+				}
+
+				writer.Put(string(key), value)
+				count += 1
+			}
+
+			expectedCount, err := binary.ReadUvarint(reader)
+			if err != nil {
+				return err
+			}
+			if expectedCount != count {
+				return errors.New("Store backup entries count doesn't match")
+			}
+
+			checksum := reader.hash.Sum(nil)
+			stored := make([]byte, len(checksum))
+			if _, err = io.ReadFull(reader.r, stored); err != nil {
+				return err
+			}
+			if !bytes.Equal(checksum, stored) {
+				return errors.New("Store backup checksum doesn't match")
+			}
+
+			return nil
+		}()
+
+		return restoreErr
+	}); err != nil {
+		return nil, 0, err
+	}
+	if restoreErr != nil {
+		return nil, 0, restoreErr
+	}
+
+	return
+}
+
+func RestoreFromFile(path string) (*Store, uint64, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	return Restore(file)
+}
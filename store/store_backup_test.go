@@ -0,0 +1,69 @@
+package store
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_bunt"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"testing"
+)
+
+func TestStoreBackupRestore(t *testing.T) {
+
+	db, err := store_db_bunt.CreateStoreDBBunt("/wallet", true)
+	assert.NoError(t, err)
+	StoreWallet, err = createStore("/wallet", db)
+	assert.NoError(t, err)
+	defer func() {
+		StoreWallet = nil
+	}()
+
+	expected := make(map[string][]byte)
+	assert.NoError(t, StoreWallet.DB.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		for i := 0; i < 100; i++ {
+			key := "key" + strconv.Itoa(i)
+			expected[key] = helpers.RandomBytes(i)
+			tx.Put(key, expected[key])
+		}
+		return nil
+	}))
+
+	buffer := &bytes.Buffer{}
+	count, err := StoreWallet.Backup(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), count)
+
+	//the entries which are not in the backup are removed
+	assert.NoError(t, StoreWallet.DB.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("extra", []byte{1})
+		tx.Delete("key5")
+		return nil
+	}))
+
+	//a corrupted archive doesn't change the store
+	corrupted := append([]byte{}, buffer.Bytes()[:buffer.Len()-20]...)
+	_, _, err = Restore(bytes.NewReader(corrupted))
+	assert.Error(t, err)
+	assert.NoError(t, StoreWallet.DB.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.True(t, tx.Exists("extra"))
+		return nil
+	}))
+
+	restored, count, err := Restore(bytes.NewReader(buffer.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, StoreWallet, restored)
+	assert.Equal(t, uint64(100), count)
+
+	assert.NoError(t, StoreWallet.DB.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		found := 0
+		tx.IterateRange("", "", func(key string, value []byte) bool {
+			assert.Equal(t, expected[key], value)
+			found += 1
+			return true
+		})
+		assert.Equal(t, len(expected), found)
+		return nil
+	}))
+}
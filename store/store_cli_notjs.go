@@ -0,0 +1,37 @@
+//go:build !wasm
+// +build !wasm
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"pandora-pay/gui"
+)
+
+func cliBackupStore(cmd string, ctx context.Context) (err error) {
+
+	list := []*Store{StoreBlockchain, StoreWallet, StoreSettings, StoreBalancesDecrypted}
+	for i, store := range list {
+		gui.GUI.OutputWrite(fmt.Sprintf("%d) %s", i, store.Name))
+	}
+
+	index := gui.GUI.OutputReadInt("Select Store to backup", false, 0, func(value int) bool {
+		return value >= 0 && value < len(list)
+	})
+
+	filename := gui.GUI.OutputReadFilename("Path to backup", "backup", false)
+
+	count, err := list[index].BackupToFile(filename)
+	if err != nil {
+		return
+	}
+
+	gui.GUI.OutputWrite(fmt.Sprintf("Backup with %d entries written to: %s", count, filename))
+	gui.GUI.OutputWrite("It can be restored with --store-restore")
+	return
+}
+
+func initCLI() {
+	gui.GUI.CommandDefineCallback("Backup Store", cliBackupStore, true)
+}
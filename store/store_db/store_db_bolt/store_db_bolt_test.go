@@ -0,0 +1,22 @@
+package store_db_bolt
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"pandora-pay/store/store_db/store_db_conformance"
+	"testing"
+)
+
+func TestStoreDBBolt(t *testing.T) {
+
+	dir, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(dir)
+
+	store, err := CreateStoreDBBolt("/test")
+	assert.NoError(t, err)
+	defer store.Close()
+
+	store_db_conformance.TestStoreDB(t, store, true)
+}
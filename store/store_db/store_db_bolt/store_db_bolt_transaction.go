@@ -36,3 +36,17 @@ func (tx *StoreDBBoltTransaction) Exists(key string) bool {
 func (tx *StoreDBBoltTransaction) Delete(key string) {
 	tx.bucket.Delete([]byte(key))
 }
+
+func (tx *StoreDBBoltTransaction) IterateRange(start, end string, callback func(key string, value []byte) bool) {
+	c := tx.bucket.Cursor()
+	for k, v := c.Seek([]byte(start)); k != nil && (end == "" || string(k) < end); k, v = c.Next() {
+		if !callback(string(k), helpers.CloneBytes(v)) {
+			return
+		}
+	}
+}
+
+func (tx *StoreDBBoltTransaction) IteratePrefix(prefix, cursor string, callback func(key string, value []byte) bool) {
+	start, end := store_db_interface.PrefixRange(prefix, cursor)
+	tx.IterateRange(start, end, callback)
+}
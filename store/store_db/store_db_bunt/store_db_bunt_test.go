@@ -0,0 +1,16 @@
+package store_db_bunt
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/store/store_db/store_db_conformance"
+	"testing"
+)
+
+func TestStoreDBBunt(t *testing.T) {
+
+	store, err := CreateStoreDBBunt("/test", true)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	store_db_conformance.TestStoreDB(t, store, true)
+}
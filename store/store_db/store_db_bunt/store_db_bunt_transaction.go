@@ -41,7 +41,29 @@ func (tx *StoreDBBuntTransaction) Exists(key string) bool {
 
 func (tx *StoreDBBuntTransaction) Delete(key string) {
 	_, err := tx.buntTx.Delete(key)
-	if err != buntdb.ErrNotFound {
+	if err != nil && err != buntdb.ErrNotFound {
 		panic(err)
 	}
 }
+
+func (tx *StoreDBBuntTransaction) IterateRange(start, end string, callback func(key string, value []byte) bool) {
+
+	iterator := func(key, value string) bool {
+		return callback(key, []byte(value))
+	}
+
+	var err error
+	if end == "" {
+		err = tx.buntTx.AscendGreaterOrEqual("", start, iterator)
+	} else {
+		err = tx.buntTx.AscendRange("", start, end, iterator)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (tx *StoreDBBuntTransaction) IteratePrefix(prefix, cursor string, callback func(key string, value []byte) bool) {
+	start, end := store_db_interface.PrefixRange(prefix, cursor)
+	tx.IterateRange(start, end, callback)
+}
@@ -0,0 +1,73 @@
+package store_db_conformance
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/store/store_db/store_db_interface"
+	"testing"
+)
+
+func iteratePrefix(tx store_db_interface.StoreDBTransactionInterface, prefix, cursor string) (keys []string) {
+	tx.IteratePrefix(prefix, cursor, func(key string, value []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return
+}
+
+// TestStoreDB is called by the tests of every store to check that the empty store behaves like the other StoreDBInterface. binaryKeys is false for the stores which keep the keys as text
+func TestStoreDB(t *testing.T, store store_db_interface.StoreDBInterface, binaryKeys bool) {
+
+	last := "b~"
+	if binaryKeys {
+		last = "b\xff"
+	}
+
+	assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.True(t, tx.IsWritable())
+		for _, key := range []string{"b:3", "a:1", "b:1", "c:1", "b:2", last} {
+			tx.Put(key, []byte(key))
+		}
+		return nil
+	}))
+
+	assert.NoError(t, store.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.False(t, tx.IsWritable())
+		assert.Equal(t, []byte("b:1"), tx.Get("b:1"))
+		assert.True(t, tx.Exists("b:1"))
+		assert.Nil(t, tx.Get("b:4"))
+		assert.False(t, tx.Exists("b:4"))
+		return nil
+	}))
+
+	assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+
+		assert.Equal(t, []string{"b:1", "b:2", "b:3"}, iteratePrefix(tx, "b:", ""))
+		assert.Equal(t, []string{"b:3"}, iteratePrefix(tx, "b:", "b:2"))
+		assert.Empty(t, iteratePrefix(tx, "b:", "b:3"))
+		assert.Equal(t, []string{"b:1", "b:2", "b:3", last}, iteratePrefix(tx, "b", ""))
+		assert.Empty(t, iteratePrefix(tx, "d", ""))
+
+		//the changes of the transaction are visible
+		tx.Delete("b:2")
+		tx.Put("b:0", []byte("b:0"))
+		assert.False(t, tx.Exists("b:2"))
+		assert.Equal(t, []string{"b:0", "b:1", "b:3"}, iteratePrefix(tx, "b:", ""))
+
+		visited := 0
+		tx.IterateRange("a", "c", func(key string, value []byte) bool {
+			assert.Equal(t, key, string(value))
+			visited += 1
+			return visited < 2
+		})
+		assert.Equal(t, 2, visited)
+
+		return nil
+	}))
+
+	assert.NoError(t, store.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []string{"a:1", "b:0", "b:1", "b:3", last, "c:1"}, iteratePrefix(tx, "", ""))
+		assert.Nil(t, tx.Get("b:2"))
+		return nil
+	}))
+
+}
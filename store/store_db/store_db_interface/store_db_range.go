@@ -0,0 +1,26 @@
+package store_db_interface
+
+// PrefixRange returns the range [start, end) of the keys which start with prefix and are greater than cursor
+func PrefixRange(prefix, cursor string) (start, end string) {
+
+	start = prefix
+	if cursor != "" && cursor >= prefix {
+		start = cursor + "\x00" //the smallest key greater than the cursor
+	}
+
+	//the smallest key greater than all the keys which start with prefix
+	end = prefix
+	for len(end) > 0 {
+		if last := end[len(end)-1]; last != 0xff {
+			end = end[:len(end)-1] + string([]byte{last + 1})
+			return
+		}
+		end = end[:len(end)-1]
+	}
+
+	return
+}
+
+func InRange(key, start, end string) bool {
+	return key >= start && (end == "" || key < end)
+}
@@ -6,4 +6,9 @@ type StoreDBTransactionInterface interface {
 	Exists(key string) bool
 	Delete(key string)
 	IsWritable() bool
+	// IterateRange visits in ascending order the keys between start (inclusive) and end (exclusive). An empty end doesn't limit the range.
+	// The iteration stops once the callback returns false. The store must not be modified inside the callback
+	IterateRange(start, end string, callback func(key string, value []byte) bool)
+	// IteratePrefix visits in ascending order the keys which start with prefix and are greater than cursor. An empty cursor starts with the first key
+	IteratePrefix(prefix, cursor string, callback func(key string, value []byte) bool)
 }
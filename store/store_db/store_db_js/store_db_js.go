@@ -62,6 +62,9 @@ func CreateStoreDBJS(name string) (*StoreDBJS, error) {
 	if out.IsNull() || out.IsUndefined() {
 		return nil, errors.New("`createStore` returned a null value")
 	}
+	if out.Get("keysRange").Type() != js.TypeFunction {
+		return nil, errors.New("`createStore` returned a store without `keysRange`")
+	}
 
 	return &StoreDBJS{
 		Name:    []byte(name),
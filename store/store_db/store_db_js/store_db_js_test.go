@@ -0,0 +1,78 @@
+package store_db_js
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/store/store_db/store_db_conformance"
+	"pandora-pay/store/store_db/store_db_interface"
+	"syscall/js"
+	"testing"
+)
+
+// in memory PandoraStorage which behaves like the one of the wallet
+const testPandoraStorage = `globalThis.PandoraStorage = {
+	createStore(name) {
+		const items = new Map()
+		return {
+			getItem: async (key) => items.has(key) ? items.get(key) : null,
+			setItem: async (key, value) => { items.set(key, value) },
+			removeItem: async (key) => { items.delete(key) },
+			keysRange: async (start, end, limit) => [...items.keys()].filter(key => key >= start && (end === "" || key < end)).sort().slice(0, limit),
+		}
+	},
+}`
+
+func iteratePrefix(tx store_db_interface.StoreDBTransactionInterface, prefix, cursor string) (keys []string) {
+	tx.IteratePrefix(prefix, cursor, func(key string, value []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return
+}
+
+func TestStoreDBJS(t *testing.T) {
+
+	js.Global().Call("eval", testPandoraStorage)
+
+	store, err := CreateStoreDBJS("test")
+	assert.NoError(t, err)
+
+	//the keys are stored as js strings
+	store_db_conformance.TestStoreDB(t, store, false)
+}
+
+func TestStoreDBJSIterateRangePages(t *testing.T) {
+
+	js.Global().Call("eval", testPandoraStorage)
+
+	store, err := CreateStoreDBJS("pages")
+	assert.NoError(t, err)
+
+	count := 2*ITERATE_RANGE_PAGE + 10
+
+	assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		for i := 0; i < count; i++ {
+			tx.Put(fmt.Sprintf("k:%05d", 2*i), []byte{1})
+		}
+		return nil
+	}))
+
+	assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+
+		//the changes of the transaction are merged in every page
+		tx.Delete(fmt.Sprintf("k:%05d", 2*ITERATE_RANGE_PAGE))
+		tx.Put(fmt.Sprintf("k:%05d", 2*ITERATE_RANGE_PAGE+1), []byte{1})
+		tx.Put(fmt.Sprintf("k:%05d", 2*count+1), []byte{1})
+
+		keys := iteratePrefix(tx, "k:", "")
+		assert.Len(t, keys, count+1)
+		for i := 1; i < len(keys); i++ {
+			assert.Less(t, keys[i-1], keys[i])
+		}
+		assert.NotContains(t, keys, fmt.Sprintf("k:%05d", 2*ITERATE_RANGE_PAGE))
+		assert.Contains(t, keys, fmt.Sprintf("k:%05d", 2*ITERATE_RANGE_PAGE+1))
+		assert.Equal(t, fmt.Sprintf("k:%05d", 2*count+1), keys[len(keys)-1])
+
+		return nil
+	}))
+}
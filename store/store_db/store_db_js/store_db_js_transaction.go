@@ -6,9 +6,13 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 	"syscall/js"
 )
 
+// ITERATE_RANGE_PAGE is the maximum number of keys read from the js store at once while iterating
+const ITERATE_RANGE_PAGE = 256
+
 type StoreDBJSTransactionData struct {
 	value     []byte
 	operation string
@@ -88,6 +92,98 @@ func (tx *StoreDBJSTransaction) Delete(key string) {
 	tx.local.Store(key, &StoreDBJSTransactionData{nil, "del"})
 }
 
+// keysRange returns at most limit keys of the js store which are in the range [start, end), sorted ascending
+func (tx *StoreDBJSTransaction) keysRange(start, end string, limit int) []string {
+
+	respCh := make(chan []string)
+	defer close(respCh)
+
+	errCh := make(chan error)
+	defer close(errCh)
+
+	promise := tx.jsStore.Call("keysRange", start, end, limit)
+
+	promise.Call("then", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		var result []string
+		if !args[0].IsNull() && !args[0].IsUndefined() {
+			result = make([]string, args[0].Length())
+			for i := range result {
+				result[i] = args[0].Index(i).String()
+			}
+		}
+
+		respCh <- result
+		return nil
+	}), js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		errCh <- fmt.Errorf("error reading keys from js db %s", args[0].Get("message").String())
+		return nil
+	}))
+
+	select {
+	case resp := <-respCh:
+		return resp
+	case <-errCh:
+		return nil
+	}
+}
+
+// IterateRange reads the keys of the js store in pages of ITERATE_RANGE_PAGE keys and merges every page with the changes made by the transaction. The values are read only for the visited keys
+func (tx *StoreDBJSTransaction) IterateRange(start, end string, callback func(key string, value []byte) bool) {
+
+	for {
+
+		stored := tx.keysRange(start, end, ITERATE_RANGE_PAGE)
+
+		//the page covers [start, pageEnd)
+		pageEnd := end
+		if len(stored) == ITERATE_RANGE_PAGE {
+			pageEnd = stored[len(stored)-1] + "\x00"
+		}
+
+		found := make(map[string]bool)
+		for _, key := range stored {
+			if store_db_interface.InRange(key, start, pageEnd) {
+				found[key] = true
+			}
+		}
+
+		tx.local.Range(func(key string, data *StoreDBJSTransactionData) bool {
+			if store_db_interface.InRange(key, start, pageEnd) {
+				if data.operation == "del" {
+					delete(found, key)
+				} else if data.operation == "put" {
+					found[key] = true
+				}
+			}
+			return true
+		})
+
+		keys := make([]string, 0, len(found))
+		for key := range found {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if value := tx.Get(key); value != nil {
+				if !callback(key, helpers.CloneBytes(value)) {
+					return
+				}
+			}
+		}
+
+		if len(stored) < ITERATE_RANGE_PAGE {
+			return
+		}
+		start = pageEnd
+	}
+}
+
+func (tx *StoreDBJSTransaction) IteratePrefix(prefix, cursor string, callback func(key string, value []byte) bool) {
+	start, end := store_db_interface.PrefixRange(prefix, cursor)
+	tx.IterateRange(start, end, callback)
+}
+
 func (tx *StoreDBJSTransaction) writeTx() error {
 
 	if !tx.write {
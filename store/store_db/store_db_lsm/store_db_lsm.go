@@ -0,0 +1,495 @@
+package store_db_lsm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"os"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store/store_db/store_db_interface"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const lsmMemtableMaxSize = 64 << 20           //bytes written in the log before they are flushed into a table
+const lsmCompactionTables = 4                 //tables of the same level which are merged into a table of the next level
+const lsmFlushRetryInterval = 5 * time.Second //interval after which a failed flush is retried
+
+type lsmManifest struct {
+	NextTableId uint64   `msgpack:"nextTableId"`
+	Tables      []uint64 `msgpack:"tables"` //newest first
+	Levels      []uint32 `msgpack:"levels"` //level of every table
+}
+
+// StoreDBLSM is an append only store. The batches are appended to a log and kept in memory until a background worker flushes them into sorted tables.
+// The tables are compacted by levels: the flushed tables are on level 0 and every lsmCompactionTables tables of a level are merged into a single table of the next level, so every key is rewritten only once per level
+type StoreDBLSM struct {
+	store_db_interface.StoreDBInterface
+	Name            []byte
+	path            string
+	memtable        map[string]*lsmValue
+	memtableSize    int
+	memtableMaxSize int
+	tables          []*lsmTable //newest first, so their levels are ascending
+	nextTableId     uint64
+	log             *lsmLog
+	workCn          chan struct{}
+	closeCn         chan struct{}
+	closeOnce       *sync.Once
+	workerWg        *sync.WaitGroup
+	compactionLock  *sync.Mutex
+	rwmutex         *sync.RWMutex
+}
+
+func (store *StoreDBLSM) Close() (err error) {
+
+	store.closeOnce.Do(func() {
+
+		close(store.closeCn)
+		store.workerWg.Wait()
+
+		store.rwmutex.Lock()
+		defer store.rwmutex.Unlock()
+
+		err = store.closeFiles()
+	})
+
+	return
+}
+
+func (store *StoreDBLSM) closeFiles() (err error) {
+	if store.log != nil {
+		err = store.log.close()
+	}
+	for _, table := range store.tables {
+		if err2 := table.close(); err2 != nil && err == nil {
+			err = err2
+		}
+	}
+	return
+}
+
+func (store *StoreDBLSM) View(callback func(dbTx store_db_interface.StoreDBTransactionInterface) error) error {
+	store.rwmutex.RLock()
+	defer store.rwmutex.RUnlock()
+
+	tx := &StoreDBLSMTransaction{
+		store: store,
+		local: &generics.Map[string, *lsmValue]{},
+	}
+	return callback(tx)
+}
+
+func (store *StoreDBLSM) Update(callback func(dbTx store_db_interface.StoreDBTransactionInterface) error) error {
+	store.rwmutex.Lock()
+	defer store.rwmutex.Unlock()
+
+	tx := &StoreDBLSMTransaction{
+		store: store,
+		local: &generics.Map[string, *lsmValue]{},
+		write: true,
+	}
+
+	if err := callback(tx); err != nil {
+		return err
+	}
+
+	return store.commit(tx.local)
+}
+
+func (store *StoreDBLSM) tablePath(id uint64) string {
+	return filepath.Join(store.path, fmt.Sprintf("%08d.table", id))
+}
+
+// is locked before
+func (store *StoreDBLSM) get(key string) ([]byte, error) {
+
+	if data := store.memtable[key]; data != nil {
+		if data.deleted {
+			return nil, nil
+		}
+		return data.value, nil
+	}
+
+	for _, table := range store.tables {
+		value, deleted, found, err := table.get(key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			if deleted {
+				return nil, nil
+			}
+			return value, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// is locked before
+func (store *StoreDBLSM) apply(key string, value []byte, deleted bool) {
+	store.memtable[key] = &lsmValue{value, deleted}
+	store.memtableSize += len(key) + len(value)
+}
+
+// commit writes the changes of the transaction as a single batch in the log. The transaction is durable once the batch is written, so the memtable is flushed later by the worker
+func (store *StoreDBLSM) commit(local *generics.Map[string, *lsmValue]) (err error) {
+
+	var batch []byte
+	local.Range(func(key string, data *lsmValue) bool {
+		batch = appendEntry(batch, key, data.value, data.deleted)
+		return true
+	})
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err = store.log.write(batch); err != nil {
+		return
+	}
+
+	local.Range(func(key string, data *lsmValue) bool {
+		store.apply(key, data.value, data.deleted)
+		return true
+	})
+
+	if store.memtableSize >= store.memtableMaxSize {
+		store.notifyWorker()
+	}
+
+	return
+}
+
+func (store *StoreDBLSM) notifyWorker() {
+	select {
+	case store.workCn <- struct{}{}:
+	default:
+	}
+}
+
+// flushMemtable flushes the memtable once it exceeds its maximum size
+func (store *StoreDBLSM) flushMemtable() error {
+
+	store.rwmutex.Lock()
+	defer store.rwmutex.Unlock()
+
+	if store.memtableSize < store.memtableMaxSize {
+		return nil
+	}
+	return store.flush()
+}
+
+// is locked before
+func (store *StoreDBLSM) flush() (err error) {
+
+	if len(store.memtable) == 0 {
+		return
+	}
+
+	id := store.nextTableId
+	writer, err := createTableWriter(store.tablePath(id), id)
+	if err != nil {
+		return
+	}
+
+	//the deleted keys are required only to hide the older tables
+	hasOlder := len(store.tables) > 0
+
+	if err = mergeCursors([]*lsmCursor{valuesCursor(store.memtable, "", "")}, func(key string, value []byte, deleted bool) (bool, error) {
+		if deleted && !hasOlder {
+			return true, nil
+		}
+		return true, writer.add(key, value, deleted)
+	}); err != nil {
+		return writer.abort(err)
+	}
+
+	table, err := writer.finish(0)
+	if err != nil {
+		return
+	}
+
+	tables := append([]*lsmTable{table}, store.tables...)
+	if err = store.writeManifest(id+1, tables); err != nil {
+		table.close()
+		os.Remove(store.tablePath(id))
+		return
+	}
+
+	store.tables = tables
+	store.nextTableId = id + 1
+	store.memtable = make(map[string]*lsmValue)
+	store.memtableSize = 0
+
+	return store.log.reset()
+}
+
+// compactionRun returns the tables [from, to) of the lowest level which has enough tables to be merged
+func compactionRun(tables []*lsmTable) (from, to int) {
+	for from < len(tables) {
+		to = from
+		for to < len(tables) && tables[to].level == tables[from].level {
+			to += 1
+		}
+		if to-from >= lsmCompactionTables {
+			return
+		}
+		from = to
+	}
+	return 0, 0
+}
+
+// compact merges the levels until every level has less than lsmCompactionTables tables
+func (store *StoreDBLSM) compact() (err error) {
+
+	store.compactionLock.Lock()
+	defer store.compactionLock.Unlock()
+
+	for {
+		var compacted bool
+		if compacted, err = store.compactLevel(); err != nil || !compacted {
+			return
+		}
+	}
+}
+
+// compactLevel merges the tables of a level into a single table of the next level. The deleted keys are dropped when there are no older tables
+func (store *StoreDBLSM) compactLevel() (compacted bool, err error) {
+
+	store.rwmutex.Lock()
+	from, to := compactionRun(store.tables)
+	if from == to {
+		store.rwmutex.Unlock()
+		return
+	}
+	tables := append([]*lsmTable{}, store.tables[from:to]...)
+	count := len(store.tables)
+	id := store.nextTableId
+	store.nextTableId += 1
+	store.rwmutex.Unlock()
+
+	hasOlder := to < count
+
+	//the tables are immutable and can be read without the lock
+	writer, err := createTableWriter(store.tablePath(id), id)
+	if err != nil {
+		return
+	}
+
+	cursors := make([]*lsmCursor, len(tables))
+	for i, table := range tables {
+		if cursors[i], err = table.cursor("", ""); err != nil {
+			return false, writer.abort(err)
+		}
+	}
+
+	if err = mergeCursors(cursors, func(key string, value []byte, deleted bool) (bool, error) {
+		if deleted && !hasOlder {
+			return true, nil
+		}
+		return true, writer.add(key, value, deleted)
+	}); err != nil {
+		return false, writer.abort(err)
+	}
+
+	merged, err := writer.finish(tables[0].level + 1)
+	if err != nil {
+		return
+	}
+
+	store.rwmutex.Lock()
+
+	//the flushes only add newer tables meanwhile
+	from += len(store.tables) - count
+	list := append(append(append([]*lsmTable{}, store.tables[:from]...), merged), store.tables[from+len(tables):]...)
+	if err = store.writeManifest(store.nextTableId, list); err != nil {
+		store.rwmutex.Unlock()
+		merged.close()
+		os.Remove(store.tablePath(id))
+		return
+	}
+	store.tables = list
+
+	store.rwmutex.Unlock()
+
+	for _, table := range tables {
+		table.close()
+		os.Remove(store.tablePath(table.id))
+	}
+
+	return true, nil
+}
+
+// worker flushes the memtable and compacts the tables. A failed flush is retried, as the batches are kept in the log meanwhile
+func (store *StoreDBLSM) worker() {
+
+	defer store.workerWg.Done()
+
+	var retryCn <-chan time.Time
+	for {
+		select {
+		case <-store.closeCn:
+			return
+		case <-store.workCn:
+		case <-retryCn:
+		}
+		retryCn = nil
+
+		if err := store.flushMemtable(); err != nil {
+			gui.GUI.Error("Error flushing the store", string(store.Name), err)
+			retryCn = time.After(lsmFlushRetryInterval)
+			continue
+		}
+
+		if err := store.compact(); err != nil {
+			gui.GUI.Error("Error compacting the store", string(store.Name), err)
+		}
+	}
+}
+
+// writeManifest replaces atomically the list of the tables
+func (store *StoreDBLSM) writeManifest(nextTableId uint64, tables []*lsmTable) (err error) {
+
+	manifest := &lsmManifest{nextTableId, make([]uint64, len(tables)), make([]uint32, len(tables))}
+	for i, table := range tables {
+		manifest.Tables[i] = table.id
+		manifest.Levels[i] = table.level
+	}
+
+	data, err := msgpack.Marshal(manifest)
+	if err != nil {
+		return
+	}
+
+	tmpPath := filepath.Join(store.path, "MANIFEST.tmp")
+
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return
+	}
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return
+	}
+	if err = file.Close(); err != nil {
+		return
+	}
+
+	if err = os.Rename(tmpPath, filepath.Join(store.path, "MANIFEST")); err != nil {
+		return
+	}
+
+	dir, err := os.Open(store.path)
+	if err != nil {
+		return
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+// open loads the tables of the manifest, removes the files left by an interrupted flush or compaction and replays the log
+func (store *StoreDBLSM) open() (err error) {
+
+	manifest := &lsmManifest{}
+
+	data, err := os.ReadFile(filepath.Join(store.path, "MANIFEST"))
+	if err == nil {
+		if err = msgpack.Unmarshal(data, manifest); err != nil {
+			return
+		}
+	} else if !os.IsNotExist(err) {
+		return
+	}
+
+	store.nextTableId = manifest.NextTableId
+
+	if len(manifest.Levels) != len(manifest.Tables) {
+		return errors.New("Manifest is invalid")
+	}
+
+	used := make(map[string]bool)
+	for i, id := range manifest.Tables {
+		if id >= store.nextTableId || (i > 0 && manifest.Levels[i] < manifest.Levels[i-1]) {
+			return errors.New("Manifest is invalid")
+		}
+		var table *lsmTable
+		if table, err = openTable(store.tablePath(id), id, manifest.Levels[i]); err != nil {
+			return
+		}
+		store.tables = append(store.tables, table)
+		used[store.tablePath(id)] = true
+	}
+
+	files, err := os.ReadDir(store.path)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		path := filepath.Join(store.path, file.Name())
+		if (strings.HasSuffix(file.Name(), ".table") && !used[path]) || file.Name() == "MANIFEST.tmp" {
+			if err = os.Remove(path); err != nil {
+				return
+			}
+		}
+	}
+
+	if store.log, err = openLog(filepath.Join(store.path, "log"), store.apply); err != nil {
+		return
+	}
+
+	if from, to := compactionRun(store.tables); from != to || store.memtableSize >= store.memtableMaxSize {
+		store.notifyWorker()
+	}
+
+	return
+}
+
+func CreateStoreDBLSM(name string) (*StoreDBLSM, error) {
+
+	var err error
+
+	prefix := "./store"
+	if _, err = os.Stat(prefix); os.IsNotExist(err) {
+		if err = os.Mkdir(prefix, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	store := &StoreDBLSM{
+		Name:            []byte(name),
+		path:            prefix + name + "_store" + ".lsm",
+		memtable:        make(map[string]*lsmValue),
+		memtableMaxSize: lsmMemtableMaxSize,
+		workCn:          make(chan struct{}, 1),
+		closeCn:         make(chan struct{}),
+		closeOnce:       &sync.Once{},
+		workerWg:        &sync.WaitGroup{},
+		compactionLock:  &sync.Mutex{},
+		rwmutex:         &sync.RWMutex{},
+	}
+
+	if err = os.MkdirAll(store.path, 0755); err != nil {
+		return nil, err
+	}
+
+	if err = store.open(); err != nil {
+		store.closeFiles()
+		return nil, err
+	}
+
+	store.workerWg.Add(1)
+	recovery.SafeGo(store.worker)
+
+	return store, nil
+}
@@ -0,0 +1,93 @@
+package store_db_lsm
+
+import (
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+)
+
+type lsmValue struct {
+	value   []byte
+	deleted bool
+}
+
+// lsmCursor walks a run of entries sorted by their keys. The entries are loaded in batches, so a table is read block by block
+type lsmCursor struct {
+	entries []*lsmEntry
+	pos     int
+	end     string
+	next    func() ([]*lsmEntry, error) //returns nil when there are no more entries
+}
+
+func (cursor *lsmCursor) valid() bool {
+	return cursor.pos < len(cursor.entries) && (cursor.end == "" || cursor.entries[cursor.pos].key < cursor.end)
+}
+
+func (cursor *lsmCursor) entry() *lsmEntry {
+	return cursor.entries[cursor.pos]
+}
+
+// load reads the next batches until one of them is not empty
+func (cursor *lsmCursor) load() (err error) {
+	for cursor.pos >= len(cursor.entries) {
+		if cursor.entries, err = cursor.next(); err != nil || cursor.entries == nil {
+			return
+		}
+		cursor.pos = 0
+	}
+	return
+}
+
+func (cursor *lsmCursor) advance() error {
+	cursor.pos += 1
+	return cursor.load()
+}
+
+func valuesCursor(values map[string]*lsmValue, start, end string) *lsmCursor {
+
+	entries := make([]*lsmEntry, 0, len(values))
+	for key, data := range values {
+		if store_db_interface.InRange(key, start, end) {
+			entries = append(entries, &lsmEntry{key, data.value, data.deleted})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	return &lsmCursor{entries, 0, end, func() ([]*lsmEntry, error) {
+		return nil, nil
+	}}
+}
+
+// mergeCursors visits the keys of all the cursors in ascending order. When more cursors have the same key, the first one of them is visited
+func mergeCursors(cursors []*lsmCursor, callback func(key string, value []byte, deleted bool) (bool, error)) error {
+
+	for {
+
+		var winner *lsmEntry
+		for _, cursor := range cursors {
+			if cursor.valid() {
+				if entry := cursor.entry(); winner == nil || entry.key < winner.key {
+					winner = entry
+				}
+			}
+		}
+
+		if winner == nil {
+			return nil
+		}
+
+		for _, cursor := range cursors {
+			if cursor.valid() && cursor.entry().key == winner.key {
+				if err := cursor.advance(); err != nil {
+					return err
+				}
+			}
+		}
+
+		next, err := callback(winner.key, winner.value, winner.deleted)
+		if err != nil || !next {
+			return err
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package store_db_lsm
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+const lsmLogHeaderSize = 8
+
+// lsmLog is the append only log of the batches which are not flushed into a table yet.
+// Every batch is a single record [length][crc32][entries] so a batch interrupted by a crash is discarded entirely
+type lsmLog struct {
+	file *os.File
+	size int64
+}
+
+func appendEntry(buf []byte, key string, value []byte, deleted bool) []byte {
+	if deleted {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(key)))
+	buf = append(buf, key...)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func readEntry(reader *advanced_buffers.BufferReader) (key string, value []byte, deleted bool, err error) {
+
+	var flag byte
+	if flag, err = reader.ReadByte(); err != nil {
+		return
+	}
+	deleted = flag == 1
+
+	var keyBytes []byte
+	if keyBytes, err = reader.ReadVariableBytes(math.MaxInt32); err != nil {
+		return
+	}
+	key = string(keyBytes)
+
+	value, err = reader.ReadVariableBytes(math.MaxInt32)
+	return
+}
+
+// write appends the batch and syncs it to the disk
+func (log *lsmLog) write(batch []byte) (err error) {
+
+	record := make([]byte, lsmLogHeaderSize, lsmLogHeaderSize+len(batch))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(batch)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(batch))
+	record = append(record, batch...)
+
+	if _, err = log.file.WriteAt(record, log.size); err != nil {
+		return
+	}
+	if err = log.file.Sync(); err != nil {
+		return
+	}
+
+	log.size += int64(len(record))
+	return
+}
+
+// reset empties the log once its batches were flushed into a table
+func (log *lsmLog) reset() (err error) {
+	if err = log.file.Truncate(0); err != nil {
+		return
+	}
+	if err = log.file.Sync(); err != nil {
+		return
+	}
+	log.size = 0
+	return
+}
+
+func (log *lsmLog) close() error {
+	return log.file.Close()
+}
+
+// openLog replays the batches of the log. A torn or corrupted record at the end of the log is truncated
+func openLog(path string, apply func(key string, value []byte, deleted bool)) (*lsmLog, error) {
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	var position int64
+	for int64(len(data))-position >= lsmLogHeaderSize {
+
+		length := int64(binary.LittleEndian.Uint32(data[position : position+4]))
+		checksum := binary.LittleEndian.Uint32(data[position+4 : position+8])
+		if int64(len(data))-position-lsmLogHeaderSize < length {
+			break
+		}
+
+		batch := data[position+lsmLogHeaderSize : position+lsmLogHeaderSize+length]
+		if crc32.ChecksumIEEE(batch) != checksum {
+			break
+		}
+
+		reader := advanced_buffers.NewBufferReader(batch)
+		for reader.Position < len(batch) {
+			key, value, deleted, err := readEntry(reader)
+			if err != nil {
+				file.Close()
+				return nil, errors.New("Invalid log entry")
+			}
+			apply(key, value, deleted)
+		}
+
+		position += lsmLogHeaderSize + length
+	}
+
+	if position != int64(len(data)) {
+		if err = file.Truncate(position); err != nil {
+			file.Close()
+			return nil, err
+		}
+		if err = file.Sync(); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	return &lsmLog{file, position}, nil
+}
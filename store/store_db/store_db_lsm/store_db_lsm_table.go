@@ -0,0 +1,313 @@
+package store_db_lsm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"os"
+	"pandora-pay/helpers/advanced_buffers"
+	"sort"
+)
+
+const lsmTableFooterSize = 16
+const lsmTableMagic = 0x324d534c //LSM2
+const lsmTableBlockSize = 4096   //bytes of entries after which a new block is started
+
+type lsmEntry struct {
+	key     string
+	value   []byte
+	deleted bool
+}
+
+// lsmTableBlock is the in memory index of a block of entries
+type lsmTableBlock struct {
+	key      string //first key of the block
+	offset   uint64
+	length   uint64
+	checksum uint32
+}
+
+// lsmTable is an immutable file of entries sorted by their keys and grouped in blocks. Only the first key of every block is kept in memory, the blocks are read from the disk.
+// File layout: [blocks][index][index offset][index crc32][magic]
+type lsmTable struct {
+	id     uint64
+	level  uint32
+	file   *os.File
+	blocks []*lsmTableBlock
+}
+
+// search returns the block which may contain the key or -1 if the key is before the first block
+func (table *lsmTable) search(key string) int {
+	return sort.Search(len(table.blocks), func(i int) bool {
+		return table.blocks[i].key > key
+	}) - 1
+}
+
+func (table *lsmTable) readBlock(i int) ([]*lsmEntry, error) {
+
+	block := table.blocks[i]
+
+	data := make([]byte, block.length)
+	if _, err := table.file.ReadAt(data, int64(block.offset)); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(data) != block.checksum {
+		return nil, errors.New("Table block is corrupted")
+	}
+
+	var entries []*lsmEntry
+
+	reader := advanced_buffers.NewBufferReader(data)
+	for reader.Position < len(data) {
+		key, value, deleted, err := readEntry(reader)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &lsmEntry{key, value, deleted})
+	}
+
+	return entries, nil
+}
+
+// get returns found=true also for a deleted key, so the older tables are not checked anymore
+func (table *lsmTable) get(key string) (value []byte, deleted, found bool, err error) {
+
+	i := table.search(key)
+	if i < 0 {
+		return
+	}
+
+	entries, err := table.readBlock(i)
+	if err != nil {
+		return
+	}
+
+	j := sort.Search(len(entries), func(j int) bool {
+		return entries[j].key >= key
+	})
+	if j == len(entries) || entries[j].key != key {
+		return
+	}
+
+	return entries[j].value, entries[j].deleted, true, nil
+}
+
+// cursor reads the blocks of the range one by one
+func (table *lsmTable) cursor(start, end string) (*lsmCursor, error) {
+
+	i := table.search(start)
+	if i < 0 {
+		i = 0
+	}
+
+	next := func() ([]*lsmEntry, error) {
+		if i >= len(table.blocks) || (end != "" && table.blocks[i].key >= end) {
+			return nil, nil
+		}
+		i += 1
+		return table.readBlock(i - 1)
+	}
+
+	cursor := &lsmCursor{nil, 0, end, next}
+	if err := cursor.load(); err != nil {
+		return nil, err
+	}
+
+	//the first block may contain keys smaller than start
+	for cursor.pos < len(cursor.entries) && cursor.entries[cursor.pos].key < start {
+		if err := cursor.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	return cursor, nil
+}
+
+func (table *lsmTable) close() error {
+	return table.file.Close()
+}
+
+type lsmTableWriter struct {
+	id     uint64
+	file   *os.File
+	buf    *bufio.Writer
+	offset uint64
+	block  []byte
+	key    string //first key of the current block
+	blocks []*lsmTableBlock
+}
+
+// add requires the keys to be added in ascending order
+func (writer *lsmTableWriter) add(key string, value []byte, deleted bool) error {
+
+	if len(writer.block) == 0 {
+		writer.key = key
+	}
+	writer.block = appendEntry(writer.block, key, value, deleted)
+
+	if len(writer.block) >= lsmTableBlockSize {
+		return writer.writeBlock()
+	}
+	return nil
+}
+
+func (writer *lsmTableWriter) writeBlock() error {
+
+	if len(writer.block) == 0 {
+		return nil
+	}
+
+	if _, err := writer.buf.Write(writer.block); err != nil {
+		return err
+	}
+
+	writer.blocks = append(writer.blocks, &lsmTableBlock{writer.key, writer.offset, uint64(len(writer.block)), crc32.ChecksumIEEE(writer.block)})
+	writer.offset += uint64(len(writer.block))
+	writer.block = writer.block[:0]
+	return nil
+}
+
+// finish writes the index and syncs the table to the disk
+func (writer *lsmTableWriter) finish(level uint32) (*lsmTable, error) {
+
+	if err := writer.writeBlock(); err != nil {
+		return nil, writer.abort(err)
+	}
+
+	index := binary.AppendUvarint(nil, uint64(len(writer.blocks)))
+	for _, block := range writer.blocks {
+		index = binary.AppendUvarint(index, uint64(len(block.key)))
+		index = append(index, block.key...)
+		index = binary.AppendUvarint(index, block.offset)
+		index = binary.AppendUvarint(index, block.length)
+		index = binary.LittleEndian.AppendUint32(index, block.checksum)
+	}
+
+	footer := make([]byte, lsmTableFooterSize)
+	binary.LittleEndian.PutUint64(footer[0:8], writer.offset)
+	binary.LittleEndian.PutUint32(footer[8:12], crc32.ChecksumIEEE(index))
+	binary.LittleEndian.PutUint32(footer[12:16], lsmTableMagic)
+
+	if _, err := writer.buf.Write(index); err != nil {
+		return nil, writer.abort(err)
+	}
+	if _, err := writer.buf.Write(footer); err != nil {
+		return nil, writer.abort(err)
+	}
+	if err := writer.buf.Flush(); err != nil {
+		return nil, writer.abort(err)
+	}
+	if err := writer.file.Sync(); err != nil {
+		return nil, writer.abort(err)
+	}
+
+	return &lsmTable{writer.id, level, writer.file, writer.blocks}, nil
+}
+
+func (writer *lsmTableWriter) abort(err error) error {
+	writer.file.Close()
+	os.Remove(writer.file.Name())
+	return err
+}
+
+func createTableWriter(path string, id uint64) (*lsmTableWriter, error) {
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lsmTableWriter{id, file, bufio.NewWriterSize(file, 1<<20), 0, make([]byte, 0, 2*lsmTableBlockSize), "", nil}, nil
+}
+
+func openTable(path string, id uint64, level uint32) (*lsmTable, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := readTableIndex(file, id, level)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return table, nil
+}
+
+func readTableIndex(file *os.File, id uint64, level uint32) (*lsmTable, error) {
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() < lsmTableFooterSize {
+		return nil, errors.New("Table is too small")
+	}
+
+	footer := make([]byte, lsmTableFooterSize)
+	if _, err = file.ReadAt(footer, stat.Size()-lsmTableFooterSize); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(footer[12:16]) != lsmTableMagic {
+		return nil, errors.New("Table magic is invalid")
+	}
+
+	indexOffset := binary.LittleEndian.Uint64(footer[0:8])
+	if indexOffset > uint64(stat.Size()-lsmTableFooterSize) {
+		return nil, errors.New("Table index offset is invalid")
+	}
+
+	index := make([]byte, uint64(stat.Size()-lsmTableFooterSize)-indexOffset)
+	if _, err = file.ReadAt(index, int64(indexOffset)); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(index) != binary.LittleEndian.Uint32(footer[8:12]) {
+		return nil, errors.New("Table index is corrupted")
+	}
+
+	reader := advanced_buffers.NewBufferReader(index)
+
+	count, err := reader.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(len(index)) {
+		return nil, errors.New("Table index is invalid")
+	}
+
+	blocks := make([]*lsmTableBlock, count)
+	for i := range blocks {
+
+		block := &lsmTableBlock{}
+
+		var key []byte
+		if key, err = reader.ReadVariableBytes(math.MaxInt32); err != nil {
+			return nil, err
+		}
+		block.key = string(key)
+
+		if block.offset, err = reader.ReadUvarint(); err != nil {
+			return nil, err
+		}
+		if block.length, err = reader.ReadUvarint(); err != nil {
+			return nil, err
+		}
+		if block.offset+block.length > indexOffset {
+			return nil, errors.New("Table block is invalid")
+		}
+
+		var checksum []byte
+		if checksum, err = reader.ReadBytes(4); err != nil {
+			return nil, err
+		}
+		block.checksum = binary.LittleEndian.Uint32(checksum)
+
+		blocks[i] = block
+	}
+
+	return &lsmTable{id, level, file, blocks}, nil
+}
@@ -0,0 +1,292 @@
+package store_db_lsm
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"os"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_bolt"
+	"pandora-pay/store/store_db/store_db_bunt"
+	"pandora-pay/store/store_db/store_db_conformance"
+	"pandora-pay/store/store_db/store_db_interface"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// the stores are created relative to the working directory
+func useTempDir(tb testing.TB) {
+	dir, err := os.Getwd()
+	assert.NoError(tb, err)
+	assert.NoError(tb, os.Chdir(tb.TempDir()))
+	tb.Cleanup(func() {
+		os.Chdir(dir)
+	})
+}
+
+func verifyStore(t *testing.T, store *StoreDBLSM, expected map[string][]byte) {
+
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	assert.NoError(t, store.View(func(tx store_db_interface.StoreDBTransactionInterface) error {
+
+		for key, value := range expected {
+			assert.Equal(t, value, tx.Get(key))
+		}
+		assert.Nil(t, tx.Get("missing"))
+
+		iterated := []string{}
+		tx.IterateRange("", "", func(key string, value []byte) bool {
+			assert.Equal(t, expected[key], value)
+			iterated = append(iterated, key)
+			return true
+		})
+		assert.Equal(t, keys, iterated)
+
+		return nil
+	}))
+}
+
+func TestStoreDBLSMInterface(t *testing.T) {
+
+	useTempDir(t)
+
+	store, err := CreateStoreDBLSM("/test")
+	assert.NoError(t, err)
+	defer store.Close()
+
+	store_db_conformance.TestStoreDB(t, store, true)
+}
+
+func TestStoreDBLSM(t *testing.T) {
+
+	useTempDir(t)
+
+	store, err := CreateStoreDBLSM("/test")
+	assert.NoError(t, err)
+	store.memtableMaxSize = 2048
+
+	expected := make(map[string][]byte)
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+			for j := 0; j < 20; j++ {
+				key := "key" + strconv.Itoa(rand.Intn(300))
+				if rand.Intn(4) == 0 {
+					tx.Delete(key)
+					delete(expected, key)
+				} else {
+					value := helpers.RandomBytes(rand.Intn(50))
+					tx.Put(key, value)
+					expected[key] = value
+				}
+			}
+			return nil
+		}))
+	}
+
+	//the memtable is flushed and the tables are compacted meanwhile by the worker
+	verifyStore(t, store, expected)
+	assert.NoError(t, store.flushMemtable())
+	store.rwmutex.RLock()
+	assert.Greater(t, store.nextTableId, uint64(0))
+	store.rwmutex.RUnlock()
+	verifyStore(t, store, expected)
+
+	assert.NoError(t, store.compact())
+	verifyStore(t, store, expected)
+
+	assert.NoError(t, store.Close())
+
+	//a torn batch at the end of the log is discarded
+	log, err := os.OpenFile(filepath.Join(store.path, "log"), os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+	_, err = log.Write([]byte{100, 0, 0, 0, 1, 2, 3})
+	assert.NoError(t, err)
+	assert.NoError(t, log.Close())
+
+	store, err = CreateStoreDBLSM("/test")
+	assert.NoError(t, err)
+	verifyStore(t, store, expected)
+
+	//a failed transaction is not written
+	assert.Error(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("key0", []byte{1})
+		return os.ErrInvalid
+	}))
+	verifyStore(t, store, expected)
+
+	assert.NoError(t, store.Close())
+	assert.NoError(t, store.Close(), "closing again does nothing")
+}
+
+func TestStoreDBLSMLevels(t *testing.T) {
+
+	useTempDir(t)
+
+	store, err := CreateStoreDBLSM("/test")
+	assert.NoError(t, err)
+	store.memtableMaxSize = 512
+
+	expected := make(map[string][]byte)
+	for i := 0; i < 200; i++ {
+		assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+			for j := 0; j < 10; j++ {
+				key := "key" + strconv.Itoa(rand.Intn(500))
+				if rand.Intn(4) == 0 {
+					tx.Delete(key)
+					delete(expected, key)
+				} else {
+					value := helpers.RandomBytes(rand.Intn(50))
+					tx.Put(key, value)
+					expected[key] = value
+				}
+			}
+			return nil
+		}))
+		assert.NoError(t, store.flushMemtable())
+	}
+	assert.NoError(t, store.compact())
+
+	//every level has less tables than the compaction threshold and the newer tables are on the lower levels
+	store.rwmutex.RLock()
+	assert.Greater(t, store.nextTableId, uint64(50))
+	levels := make(map[uint32]int)
+	for i, table := range store.tables {
+		levels[table.level] += 1
+		if i > 0 {
+			assert.LessOrEqual(t, store.tables[i-1].level, table.level)
+		}
+	}
+	assert.Less(t, len(store.tables), len(levels)*lsmCompactionTables)
+	for _, count := range levels {
+		assert.Less(t, count, lsmCompactionTables)
+	}
+	store.rwmutex.RUnlock()
+
+	verifyStore(t, store, expected)
+	assert.NoError(t, store.Close())
+
+	//the levels are kept by the manifest
+	store, err = CreateStoreDBLSM("/test")
+	assert.NoError(t, err)
+	verifyStore(t, store, expected)
+	assert.NoError(t, store.Close())
+}
+
+func TestStoreDBLSMFlushRetry(t *testing.T) {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive()
+	useTempDir(t)
+
+	store, err := CreateStoreDBLSM("/test")
+	assert.NoError(t, err)
+	store.memtableMaxSize = 64
+
+	//the tables can't be created
+	store.rwmutex.Lock()
+	path := store.path
+	store.path = filepath.Join(path, "missing")
+	store.rwmutex.Unlock()
+
+	expected := map[string][]byte{"key0": helpers.RandomBytes(100)}
+	assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("key0", expected["key0"])
+		return nil
+	}), "the batch is durable once it is written in the log")
+	assert.Error(t, store.flushMemtable())
+	verifyStore(t, store, expected)
+
+	store.rwmutex.Lock()
+	store.path = path
+	store.rwmutex.Unlock()
+
+	expected["key1"] = helpers.RandomBytes(100)
+	assert.NoError(t, store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("key1", expected["key1"])
+		return nil
+	}))
+
+	assert.Eventually(t, func() bool {
+		store.rwmutex.RLock()
+		defer store.rwmutex.RUnlock()
+		return store.memtableSize == 0 && len(store.tables) == 1
+	}, 2*lsmFlushRetryInterval, 10*time.Millisecond)
+	verifyStore(t, store, expected)
+
+	assert.NoError(t, store.Close())
+}
+
+// benchmarkSync writes a synthetic chain. Every block stores its header, its transactions and updates the accounts of the transactions
+func benchmarkSync(b *testing.B, store store_db_interface.StoreDBInterface) {
+
+	const txsPerBlock = 20
+	const accounts = 10000
+
+	accountValue := helpers.RandomBytes(200)
+	txValue := helpers.RandomBytes(1500)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := store.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+
+			height := strconv.Itoa(i)
+			hash := string(helpers.RandomBytes(32))
+
+			tx.Put("blockHash_ByHeight"+height, []byte(hash))
+			tx.Put("block_ByHash"+hash, helpers.RandomBytes(400))
+			for t := 0; t < txsPerBlock; t++ {
+				txHash := string(helpers.RandomBytes(32))
+				tx.Put("tx:"+txHash, txValue)
+				tx.Put("txHash_ByHeight"+height+"_"+strconv.Itoa(t), []byte(txHash))
+				for a := 0; a < 3; a++ {
+					tx.Put("accounts:map:"+strconv.Itoa(rand.Intn(accounts)), accountValue)
+				}
+			}
+			tx.Put("chainInfo", []byte(height))
+
+			return nil
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	if err := store.Close(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkSyncLSM(b *testing.B) {
+	useTempDir(b)
+	store, err := CreateStoreDBLSM("/benchmark")
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkSync(b, store)
+}
+
+func BenchmarkSyncBolt(b *testing.B) {
+	useTempDir(b)
+	store, err := store_db_bolt.CreateStoreDBBolt("/benchmark")
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkSync(b, store)
+}
+
+func BenchmarkSyncBunt(b *testing.B) {
+	useTempDir(b)
+	store, err := store_db_bunt.CreateStoreDBBunt("/benchmark", false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchmarkSync(b, store)
+}
@@ -0,0 +1,94 @@
+package store_db_lsm
+
+import (
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type StoreDBLSMTransaction struct {
+	store_db_interface.StoreDBTransactionInterface
+	store *StoreDBLSM
+	write bool
+	local *generics.Map[string, *lsmValue]
+}
+
+func (tx *StoreDBLSMTransaction) IsWritable() bool {
+	return tx.write
+}
+
+func (tx *StoreDBLSMTransaction) Put(key string, value []byte) {
+	if !tx.write {
+		panic("Transaction is not writeable")
+	}
+	tx.local.Store(key, &lsmValue{helpers.CloneBytes(value), false})
+}
+
+func (tx *StoreDBLSMTransaction) Get(key string) []byte {
+
+	if data, ok := tx.local.Load(key); ok {
+		if data.deleted {
+			return nil
+		}
+		return helpers.CloneBytes(data.value)
+	}
+
+	value, err := tx.store.get(key)
+	if err != nil {
+		panic(err)
+	}
+	return helpers.CloneBytes(value)
+}
+
+func (tx *StoreDBLSMTransaction) Exists(key string) bool {
+	if data, ok := tx.local.Load(key); ok {
+		return !data.deleted
+	}
+
+	value, err := tx.store.get(key)
+	if err != nil {
+		panic(err)
+	}
+	return value != nil
+}
+
+func (tx *StoreDBLSMTransaction) Delete(key string) {
+	if !tx.write {
+		panic("Transaction is not writeable")
+	}
+	tx.local.Store(key, &lsmValue{nil, true})
+}
+
+// IterateRange merges the changes of the transaction, the memtable and the tables
+func (tx *StoreDBLSMTransaction) IterateRange(start, end string, callback func(key string, value []byte) bool) {
+
+	local := make(map[string]*lsmValue)
+	tx.local.Range(func(key string, data *lsmValue) bool {
+		local[key] = data
+		return true
+	})
+
+	cursors := make([]*lsmCursor, 0, len(tx.store.tables)+2)
+	cursors = append(cursors, valuesCursor(local, start, end), valuesCursor(tx.store.memtable, start, end))
+	for _, table := range tx.store.tables {
+		cursor, err := table.cursor(start, end)
+		if err != nil {
+			panic(err)
+		}
+		cursors = append(cursors, cursor)
+	}
+
+	if err := mergeCursors(cursors, func(key string, value []byte, deleted bool) (bool, error) {
+		if deleted {
+			return true, nil
+		}
+		return callback(key, helpers.CloneBytes(value)), nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func (tx *StoreDBLSMTransaction) IteratePrefix(prefix, cursor string, callback func(key string, value []byte) bool) {
+	start, end := store_db_interface.PrefixRange(prefix, cursor)
+	tx.IterateRange(start, end, callback)
+}
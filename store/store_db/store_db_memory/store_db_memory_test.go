@@ -0,0 +1,15 @@
+package store_db_memory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/store/store_db/store_db_conformance"
+	"testing"
+)
+
+func TestStoreDBMemory(t *testing.T) {
+
+	store, err := CreateStoreDBMemory("test")
+	assert.NoError(t, err)
+
+	store_db_conformance.TestStoreDB(t, store, true)
+}
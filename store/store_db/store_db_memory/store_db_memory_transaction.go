@@ -5,6 +5,7 @@ import (
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
 )
 
 type StoreDBMemoryTransactionData struct {
@@ -60,6 +61,45 @@ func (tx *StoreDBMemoryTransaction) Delete(key string) {
 	tx.local.Store(key, &StoreDBMemoryTransactionData{nil, "del"})
 }
 
+// IterateRange merges the committed keys with the changes made by the transaction
+func (tx *StoreDBMemoryTransaction) IterateRange(start, end string, callback func(key string, value []byte) bool) {
+
+	values := make(map[string][]byte)
+	for key, value := range tx.store {
+		if store_db_interface.InRange(key, start, end) {
+			values[key] = value
+		}
+	}
+
+	tx.local.Range(func(key string, data *StoreDBMemoryTransactionData) bool {
+		if store_db_interface.InRange(key, start, end) {
+			if data.operation == "del" {
+				delete(values, key)
+			} else if data.operation == "put" {
+				values[key] = data.value
+			}
+		}
+		return true
+	})
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !callback(key, helpers.CloneBytes(values[key])) {
+			return
+		}
+	}
+}
+
+func (tx *StoreDBMemoryTransaction) IteratePrefix(prefix, cursor string, callback func(key string, value []byte) bool) {
+	start, end := store_db_interface.PrefixRange(prefix, cursor)
+	tx.IterateRange(start, end, callback)
+}
+
 func (tx *StoreDBMemoryTransaction) writeTx() error {
 
 	if !tx.write {
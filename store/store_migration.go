@@ -0,0 +1,181 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"pandora-pay/gui"
+	"pandora-pay/store/store_db/store_db_interface"
+	"path/filepath"
+	"strings"
+)
+
+const STORE_VERSION_KEY = "storeVersion"
+
+// StoreMigration changes the layout of the data of a store. Migrate runs inside a single Update and the store records its Version in the same transaction
+type StoreMigration struct {
+	Version     uint64
+	Description string
+	Migrate     func(tx store_db_interface.StoreDBTransactionInterface, progress func(done, total uint64)) error
+}
+
+var errMigrationDryRun = errors.New("Migration dry run")
+
+// migrationWriter counts the entries changed by the migrations
+type migrationWriter struct {
+	store_db_interface.StoreDBTransactionInterface
+	changes uint64
+}
+
+func (writer *migrationWriter) Put(key string, value []byte) {
+	writer.changes += 1
+	writer.StoreDBTransactionInterface.Put(key, value)
+}
+
+func (writer *migrationWriter) Delete(key string) {
+	writer.changes += 1
+	writer.StoreDBTransactionInterface.Delete(key)
+}
+
+// GetVersion returns the schema version of the store. The stores created before the versioning have no version and are considered version 0
+func (store *Store) GetVersion(reader store_db_interface.StoreDBTransactionInterface) (version uint64, found bool, err error) {
+
+	data := reader.Get(STORE_VERSION_KEY)
+	if data == nil {
+		return
+	}
+
+	var n int
+	if version, n = binary.Uvarint(data); n <= 0 {
+		return 0, false, errors.New("Store version is invalid")
+	}
+	return version, true, nil
+}
+
+func (store *Store) setVersion(writer store_db_interface.StoreDBTransactionInterface, version uint64) {
+	writer.Put(STORE_VERSION_KEY, binary.AppendUvarint(nil, version))
+}
+
+func (store *Store) runMigration(writer *migrationWriter, migration *StoreMigration, index, count int) error {
+
+	gui.GUI.Info(fmt.Sprintf("Store %s migration %d/%d to version %d: %s", store.Name, index+1, count, migration.Version, migration.Description))
+
+	if err := migration.Migrate(writer, func(done, total uint64) {
+		if total > 0 {
+			gui.GUI.Info2Update("Migration", fmt.Sprintf("%d/%d %d%%", index+1, count, done*100/total))
+		}
+	}); err != nil {
+		return fmt.Errorf("Store %s migration to version %d failed: %s", store.Name, migration.Version, err)
+	}
+
+	return nil
+}
+
+// Migrate runs in order the migrations newer than the version of the store. Every migration runs in its own transaction.
+// The dry run runs all the migrations in a single transaction which is discarded. The backup is written in the backupDir before the migrations change the store
+func (store *Store) Migrate(list []*StoreMigration, dryRun bool, backupDir string) (err error) {
+
+	var latest uint64
+	for _, migration := range list {
+		if migration.Version <= latest {
+			return errors.New("Migrations versions must be ascending and greater than zero")
+		}
+		latest = migration.Version
+	}
+
+	var version uint64
+	var found, empty bool
+	if err = store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		if version, found, err = store.GetVersion(reader); err != nil {
+			return
+		}
+		empty = true
+		reader.IterateRange("", "", func(key string, value []byte) bool {
+			empty = false
+			return false
+		})
+		return
+	}); err != nil {
+		return
+	}
+
+	if version > latest {
+		return fmt.Errorf("Store %s has the version %d which is newer than the supported version %d", store.Name, version, latest)
+	}
+
+	//a new store has already the latest layout
+	if empty {
+		version = latest
+	}
+
+	pending := []*StoreMigration{}
+	for _, migration := range list {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+
+	if dryRun {
+
+		if len(pending) == 0 {
+			gui.GUI.Log(fmt.Sprintf("Store %s version %d has no pending migrations", store.Name, version))
+			return
+		}
+
+		//the error is kept separately as not all the stores return the error of the callback
+		var migrationErr error
+		if err = store.DB.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+			for i, migration := range pending {
+				writer := &migrationWriter{tx, 0}
+				if migrationErr = store.runMigration(writer, migration, i, len(pending)); migrationErr != nil {
+					return migrationErr
+				}
+				gui.GUI.Log(fmt.Sprintf("Dry run: store %s migration to version %d changes %d entries", store.Name, migration.Version, writer.changes))
+			}
+			return errMigrationDryRun
+		}); err != nil && err != errMigrationDryRun {
+			return
+		}
+
+		return migrationErr
+	}
+
+	if len(pending) == 0 {
+		if !found {
+			err = store.DB.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+				store.setVersion(tx, version)
+				return nil
+			})
+		}
+		return
+	}
+
+	if backupDir != "" {
+		path := filepath.Join(backupDir, strings.TrimPrefix(store.Name, "/")+"_v"+fmt.Sprint(version)+".backup")
+		var count uint64
+		if count, err = store.BackupToFile(path); err != nil {
+			return
+		}
+		gui.GUI.Log(fmt.Sprintf("Store %s backup with %d entries written in %s", store.Name, count, path))
+	}
+
+	for i, migration := range pending {
+		var migrationErr error
+		if err = store.DB.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+			writer := &migrationWriter{tx, 0}
+			if migrationErr = store.runMigration(writer, migration, i, len(pending)); migrationErr != nil {
+				return migrationErr
+			}
+			store.setVersion(tx, migration.Version)
+			return nil
+		}); err != nil {
+			return
+		}
+		if migrationErr != nil {
+			return migrationErr
+		}
+		gui.GUI.Log(fmt.Sprintf("Store %s migrated to version %d", store.Name, migration.Version))
+	}
+
+	return
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store/store_db/store_db_bunt"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"path/filepath"
+	"testing"
+)
+
+func createTestMigrationStore(t *testing.T, db store_db_interface.StoreDBInterface) *Store {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive()
+
+	store, err := createStore("/wallet", db)
+	assert.NoError(t, err)
+
+	//a store created before the versioning
+	assert.NoError(t, store.DB.Update(func(tx store_db_interface.StoreDBTransactionInterface) error {
+		tx.Put("old:1", []byte{1})
+		tx.Put("old:2", []byte{2})
+		return nil
+	}))
+
+	return store
+}
+
+// renameMigration moves the old entries under the new prefix
+func renameMigration(version uint64) *StoreMigration {
+	return &StoreMigration{version, "Rename the entries", func(tx store_db_interface.StoreDBTransactionInterface, progress func(done, total uint64)) error {
+		keys := []string{}
+		tx.IteratePrefix("old:", "", func(key string, value []byte) bool {
+			keys = append(keys, key)
+			return true
+		})
+		for _, key := range keys {
+			tx.Put("new:"+key[len("old:"):], tx.Get(key))
+			tx.Delete(key)
+		}
+		return nil
+	}}
+}
+
+func getTestVersion(t *testing.T, store *Store) (version uint64, found bool) {
+	assert.NoError(t, store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		version, found, err = store.GetVersion(reader)
+		return
+	}))
+	return
+}
+
+func TestStoreMigrate(t *testing.T) {
+
+	db, err := store_db_bunt.CreateStoreDBBunt("/wallet", true)
+	assert.NoError(t, err)
+	store := createTestMigrationStore(t, db)
+
+	migrations := []*StoreMigration{renameMigration(1)}
+
+	//the dry run doesn't change the store
+	assert.NoError(t, store.Migrate(migrations, true, ""))
+	_, found := getTestVersion(t, store)
+	assert.False(t, found)
+	assert.NoError(t, store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []byte{1}, reader.Get("old:1"))
+		assert.Nil(t, reader.Get("new:1"))
+		return nil
+	}))
+
+	//the backup is written before the migration
+	backupDir := t.TempDir()
+	assert.NoError(t, store.Migrate(migrations, false, backupDir))
+
+	version, found := getTestVersion(t, store)
+	assert.True(t, found)
+	assert.Equal(t, uint64(1), version)
+	assert.NoError(t, store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.Nil(t, reader.Get("old:1"))
+		assert.Equal(t, []byte{2}, reader.Get("new:2"))
+		return nil
+	}))
+
+	path := filepath.Join(backupDir, "wallet_v0.backup")
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+
+	StoreWallet = store
+	defer func() {
+		StoreWallet = nil
+	}()
+
+	restored, count, err := RestoreFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, store, restored)
+	assert.Equal(t, uint64(2), count)
+	assert.NoError(t, store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []byte{1}, reader.Get("old:1"))
+		assert.Nil(t, reader.Get("new:1"))
+		return nil
+	}))
+
+	//the restored store has the old version and is migrated again
+	assert.NoError(t, store.Migrate(migrations, false, ""))
+	version, _ = getTestVersion(t, store)
+	assert.Equal(t, uint64(1), version)
+	assert.NoError(t, store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []byte{1}, reader.Get("new:1"))
+		return nil
+	}))
+}
+
+func TestStoreMigrateFailed(t *testing.T) {
+
+	//the memory store doesn't return the error of the callback
+	db, err := store_db_memory.CreateStoreDBMemory("/wallet")
+	assert.NoError(t, err)
+	store := createTestMigrationStore(t, db)
+
+	failed := &StoreMigration{2, "Fail", func(tx store_db_interface.StoreDBTransactionInterface, progress func(done, total uint64)) error {
+		tx.Put("partial", []byte{1})
+		return errors.New("failed")
+	}}
+	migrations := []*StoreMigration{renameMigration(1), failed}
+
+	assert.Error(t, store.Migrate(migrations, true, ""))
+	_, found := getTestVersion(t, store)
+	assert.False(t, found)
+
+	assert.Error(t, store.Migrate(migrations, false, ""))
+
+	//the migrations before the failed one are kept
+	version, found := getTestVersion(t, store)
+	assert.True(t, found)
+	assert.Equal(t, uint64(1), version)
+	assert.NoError(t, store.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.Equal(t, []byte{1}, reader.Get("new:1"))
+		assert.Nil(t, reader.Get("partial"))
+		return nil
+	}))
+
+	//the versions of the migrations must be ascending
+	assert.Error(t, store.Migrate([]*StoreMigration{renameMigration(2), renameMigration(1)}, false, ""))
+}
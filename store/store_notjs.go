@@ -9,6 +9,7 @@ import (
 	"pandora-pay/store/store_db/store_db_bolt"
 	"pandora-pay/store/store_db/store_db_bunt"
 	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_lsm"
 	"pandora-pay/store/store_db/store_db_memory"
 )
 
@@ -26,6 +27,8 @@ func createStoreNow(name, storeType string) (*Store, error) {
 		db, err = store_db_bunt.CreateStoreDBBunt(name, true)
 	case "memory":
 		db, err = store_db_memory.CreateStoreDBMemory(name)
+	case "lsm":
+		db, err = store_db_lsm.CreateStoreDBLSM(name)
 	default:
 		err = errors.New("Invalid --store-type argument")
 	}
@@ -47,8 +50,9 @@ func create_db() (err error) {
 	var prefix = ""
 
 	allowedStores := map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "memory": true}
+	allowedChainStores := map[string]bool{"bolt": true, "bunt": true, "bunt-memory": true, "memory": true, "lsm": true}
 
-	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", getStoreType(arguments.Arguments["--store-chain-type"].(string), allowedStores)); err != nil {
+	if StoreBlockchain, err = createStoreNow(prefix+"/blockchain", getStoreType(arguments.Arguments["--store-chain-type"].(string), allowedChainStores)); err != nil {
 		return
 	}
 	if StoreWallet, err = createStoreNow(prefix+"/wallet", getStoreType(arguments.Arguments["--store-wallet-type"].(string), allowedStores)); err != nil {
@@ -64,5 +68,7 @@ func create_db() (err error) {
 		return
 	}
 
+	initCLI()
+
 	return
 }
@@ -74,7 +74,7 @@ func (testnet *TestnetType) testnetCreateTransfersNewWallets(blockHeight uint64,
 			},
 			config_coins.NATIVE_ASSET_FULL,
 			config_stake.GetRequiredStake(blockHeight),
-			0, nil, 0, nil, nil, nil,
+			0, nil, 0, nil, nil, nil, false,
 		}))
 	}
 
@@ -104,7 +104,7 @@ func (testnet *TestnetType) testnetCreateClaimTx(senderAddr *wallet_address.Wall
 			},
 			config_coins.NATIVE_ASSET_FULL,
 			sendAmount,
-			0, nil, 0, nil, nil, nil,
+			0, nil, 0, nil, nil, nil, false,
 		})},
 	}
 
@@ -147,7 +147,7 @@ func (testnet *TestnetType) testnetCreateTransfers(senderAddr *wallet_address.Wa
 				},
 				config_coins.NATIVE_ASSET_FULL,
 				amount,
-				0, nil, 0, nil, nil, nil,
+				0, nil, 0, nil, nil, nil, false,
 			})},
 	}
 
@@ -1,13 +1,23 @@
 package txs_builder
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/data_storage/plain_accounts"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_data"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/config/config_fees"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/mempool"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/store"
@@ -33,6 +43,34 @@ func (builder *TxsBuilderType) getNonce(nonce uint64, publicKey []byte, accNonce
 	return builder.mempool.GetNonce(publicKey, accNonce)
 }
 
+// estimateFee replaces the static fee per byte of the automatic fees with the fee estimated from the mempool and the recent blocks. Fees paid in other assets are converted using the fee rate
+func (builder *TxsBuilderType) estimateFee(fee *wizard.WizardTransactionFee, zether bool, feeRate uint64, feeLeadingZeros byte) {
+
+	if !fee.PerByteAuto || fee.PerByte != 0 || fee.Fixed != 0 || builder.mempool == nil {
+		return
+	}
+
+	estimate, err := builder.mempool.FeeEstimator.Estimate(config_fees.FEE_ESTIMATOR_TARGET_DEFAULT)
+	if err != nil {
+		return
+	}
+
+	perByte := estimate.FeePerByte
+	if zether {
+		perByte = estimate.FeePerByteZether
+	}
+
+	if feeRate != 0 {
+		if err = helpers.SafeUint64Mul(&perByte, helpers.Pow10(feeLeadingZeros)); err != nil {
+			return
+		}
+		perByte = (perByte + feeRate - 1) / feeRate
+	}
+
+	fee.PerByte = perByte
+	fee.PerByteExtraSpace = estimate.FeePerByteExtraSpace
+}
+
 func (builder *TxsBuilderType) convertFloatAmounts(amounts []float64, ast *asset.Asset) ([]uint64, error) {
 
 	var err error
@@ -59,8 +97,8 @@ func (builder *TxsBuilderType) getWalletAddresses(senders []string) ([]*wallet_a
 		if sendersWalletAddress[i], err = builder.wallet.GetWalletAddressByEncodedAddress(senderAddress, true); err != nil {
 			return nil, err
 		}
-		if sendersWalletAddress[i].PrivateKey == nil {
-			return nil, fmt.Errorf("Can't be used for transactions as the private key is missing for sender %s", senderAddress)
+		if sendersWalletAddress[i].IsWatchOnly() {
+			return nil, fmt.Errorf("Can't be used for transactions as the address is watch-only for sender %s", senderAddress)
 		}
 	}
 
@@ -69,12 +107,17 @@ func (builder *TxsBuilderType) getWalletAddresses(senders []string) ([]*wallet_a
 
 func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, propagateTx, awaitAnswer, awaitBroadcast, validateTx bool, ctx context.Context, statusCallback func(status string)) (*transaction.Transaction, error) {
 
+	if err := builder.wallet.Encryption.CheckUnlocked(); err != nil {
+		return nil, err
+	}
+
 	if txData.Data == nil {
 		txData.Data = &wizard.WizardTransactionData{nil, false}
 	}
 	if txData.Fee == nil {
 		txData.Fee = &wizard.WizardTransactionFee{0, 0, 0, true}
 	}
+	builder.estimateFee(txData.Fee, false, 0, 0)
 
 	var sendersWalletAddresses []*wallet_address.WalletAddress
 	var err error
@@ -83,6 +126,9 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 			return nil, err
 		}
 	}
+	if len(txData.MultisigPublicKeys) > 0 && txData.Sender != "" {
+		return nil, errors.New("Sender and Multisig can not be used together")
+	}
 
 	builder.lock.Lock()
 	defer builder.lock.Unlock()
@@ -95,19 +141,31 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 		txData.Fee,
 		txData.Nonce,
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	var tx *transaction.Transaction
 	var plainAcc *plain_account.PlainAccount
 	var chainHeight uint64
 
+	var plainAccPublicKey []byte
 	if len(sendersWalletAddresses) > 0 {
+		plainAccPublicKey = sendersWalletAddresses[0].PublicKey
+	} else if len(txData.MultisigPublicKeys) > 0 {
+		plainAccPublicKey = txData.MultisigAccount
+	}
+
+	if plainAccPublicKey != nil {
 
 		if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
 			plainAccs := plain_accounts.NewPlainAccounts(reader)
 
-			if plainAcc, err = plainAccs.Get(string(sendersWalletAddresses[0].PublicKey)); err != nil {
+			if plainAcc, err = plainAccs.Get(string(plainAccPublicKey)); err != nil {
 				return
 			}
 			if plainAcc == nil {
@@ -120,8 +178,27 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 		}
 
 		statusCallback("Getting Nonce from Mempool")
-		transfer.Nonce = builder.getNonce(txData.Nonce, sendersWalletAddresses[0].PublicKey, plainAcc.Nonce)
-		transfer.Key = sendersWalletAddresses[0].PrivateKey.Key
+		transfer.Nonce = builder.getNonce(txData.Nonce, plainAccPublicKey, plainAcc.Nonce)
+	}
+
+	if len(sendersWalletAddresses) > 0 {
+		if sendersWalletAddresses[0].PrivateKey != nil {
+			transfer.Key = sendersWalletAddresses[0].PrivateKey.Key
+		} else {
+			transfer.PublicKey = sendersWalletAddresses[0].PublicKey
+			if transfer.Sign, err = sendersWalletAddresses[0].GetSigner(transfer.PublicKey); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(txData.MultisigPublicKeys) > 0 {
+		if err = plainAcc.Multisig.VerifySigners(txData.MultisigPublicKeys); err != nil {
+			return nil, err
+		}
+		transfer.MultisigPublicKey = txData.MultisigAccount
+		transfer.MultisigPublicKeys = txData.MultisigPublicKeys
+		transfer.MultisigSigns, _ = builder.getMultisigSigns(txData.MultisigPublicKeys, nil)
 	}
 
 	if tx, err = wizard.CreateSimpleTx(transfer, false, statusCallback); err != nil {
@@ -129,6 +206,11 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 	}
 	statusCallback("Transaction Created")
 
+	if tx.Bloom == nil {
+		statusCallback("Transaction requires the signatures of the co-signers")
+		return tx, nil
+	}
+
 	if propagateTx {
 		if err = builder.mempool.AddTxToMempool(tx, chainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
 			return nil, err
@@ -138,6 +220,180 @@ func (builder *TxsBuilderType) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, p
 	return tx, nil
 }
 
+// BumpSimpleTx replaces a pending TX_SIMPLE with the same tx signed again with a higher fee. The fee is raised to the minimum accepted by the mempool for a replacement
+func (builder *TxsBuilderType) BumpSimpleTx(txData *TxBuilderBumpSimpleTx, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context, statusCallback func(status string)) (*transaction.Transaction, error) {
+
+	if err := builder.wallet.Encryption.CheckUnlocked(); err != nil {
+		return nil, err
+	}
+
+	pending := builder.mempool.Txs.Get(string(txData.TxId))
+	if pending == nil {
+		return nil, errors.New("Transaction is not pending in the mempool")
+	}
+	if pending.Tx.Version != transaction_type.TX_SIMPLE {
+		return nil, errors.New("Only simple transactions can be bumped")
+	}
+
+	base := pending.Tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+	if !base.HasVin() {
+		return nil, errors.New("Transaction has no input and can not be replaced")
+	}
+
+	if txData.Fee == nil {
+		txData.Fee = &wizard.WizardTransactionFee{0, 0, 0, true}
+	}
+	builder.estimateFee(txData.Fee, false, 0, 0)
+
+	transfer := &wizard.WizardTxSimpleTransfer{
+		nil,
+		&wizard.WizardTransactionData{base.Data, base.DataVersion == transaction_data.TX_DATA_ENCRYPTED},
+		txData.Fee,
+		base.Nonce,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	}
+
+	switch extra := base.Extra.(type) {
+	case *transaction_simple_extra.TransactionSimpleExtraUpdateAssetFeeLiquidity:
+		transfer.Extra = &wizard.WizardTxSimpleExtraUpdateAssetFeeLiquidity{nil, extra.Liquidities, extra.NewCollector, extra.Collector}
+	case *transaction_simple_extra.TransactionSimpleExtraUpdatePlainAccountMultisig:
+		transfer.Extra = &wizard.WizardTxSimpleExtraUpdatePlainAccountMultisig{nil, extra.Threshold, extra.PublicKeys}
+	case *transaction_simple_extra.TransactionSimpleExtraResolutionConditionalPayment:
+		transfer.Extra = &wizard.WizardTxSimpleExtraResolutionConditionalPayment{nil, extra.TxId, extra.PayloadIndex, extra.Resolution, extra.MultisigPublicKeys, extra.Signatures}
+	default:
+		return nil, errors.New("Transaction script can not be bumped")
+	}
+
+	getPrivateKey := func(publicKey []byte) ([]byte, error) {
+		addr := builder.wallet.GetWalletAddressByPublicKey(publicKey, true)
+		if addr == nil || addr.PrivateKey == nil {
+			return nil, fmt.Errorf("Private key is missing for %s. Watch-only addresses can't be used for transactions", base64.StdEncoding.EncodeToString(publicKey))
+		}
+		return addr.PrivateKey.Key, nil
+	}
+
+	var err error
+	if base.Vin.IsMultisig() {
+		transfer.MultisigPublicKey = base.Vin.PublicKey
+		transfer.MultisigPublicKeys = base.Vin.MultisigPublicKeys
+		transfer.MultisigSigns, _ = builder.getMultisigSigns(base.Vin.MultisigPublicKeys, nil)
+	} else if addr := builder.wallet.GetWalletAddressByPublicKey(base.Vin.PublicKey, true); addr != nil && addr.PrivateKey == nil && addr.ExternalSigner != nil {
+		transfer.PublicKey = base.Vin.PublicKey
+		if transfer.Sign, err = addr.GetSigner(transfer.PublicKey); err != nil {
+			return nil, err
+		}
+	} else if transfer.Key, err = getPrivateKey(base.Vin.PublicKey); err != nil {
+		return nil, err
+	}
+
+	builder.lock.Lock()
+	defer builder.lock.Unlock()
+
+	statusCallback("Wallet Addresses Found")
+
+	var tx *transaction.Transaction
+	if tx, err = wizard.CreateSimpleTx(transfer, false, statusCallback); err != nil {
+		return nil, err
+	}
+
+	if minimumFee := config_fees.ComputeReplaceByFee(base.Fee); tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple).Fee < minimumFee {
+		statusCallback("Fee raised to replace the pending transaction")
+		transfer.Fee = &wizard.WizardTransactionFee{minimumFee, 0, 0, false}
+		if tx, err = wizard.CreateSimpleTx(transfer, false, statusCallback); err != nil {
+			return nil, err
+		}
+	}
+	statusCallback("Transaction Created")
+
+	if tx.Bloom == nil {
+		statusCallback("Transaction requires the signatures of the co-signers")
+		return tx, nil
+	}
+
+	if propagateTx {
+		if err = builder.mempool.AddTxToMempool(tx, pending.ChainHeight, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// getMultisigSigns returns the signers kept by the wallet for the multisig public keys. The signatures already included and the co-signers missing from the wallet are skipped
+func (builder *TxsBuilderType) getMultisigSigns(publicKeys, signatures [][]byte) ([]wizard.WizardSign, int) {
+
+	signs := make([]wizard.WizardSign, len(publicKeys))
+	count := 0
+	for i, publicKey := range publicKeys {
+		if signatures != nil && !bytes.Equal(signatures[i], make([]byte, cryptography.SignatureSize)) {
+			continue
+		}
+		addr := builder.wallet.GetWalletAddressByPublicKey(publicKey, true)
+		if addr == nil {
+			continue
+		}
+		sign, err := addr.GetSigner(publicKey)
+		if err != nil {
+			continue
+		}
+		signs[i] = sign
+		count += 1
+	}
+
+	return signs, count
+}
+
+// SignSimpleTxMultisig adds the signatures of the wallet to a partially signed multisig TX_SIMPLE exported by a co-signer.
+// The tx is propagated once all the signatures are included. Otherwise, the partially signed tx is returned to be given to the next co-signer
+func (builder *TxsBuilderType) SignSimpleTxMultisig(txData *TxBuilderSignSimpleTxMultisig, propagateTx, awaitAnswer, awaitBroadcast bool, ctx context.Context, statusCallback func(status string)) (*transaction.Transaction, bool, error) {
+
+	if err := builder.wallet.Encryption.CheckUnlocked(); err != nil {
+		return nil, false, err
+	}
+
+	tx := &transaction.Transaction{}
+	if err := tx.Deserialize(advanced_buffers.NewBufferReader(txData.Tx)); err != nil {
+		return nil, false, err
+	}
+
+	base, ok := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+	if !ok || !base.HasVin() || !base.Vin.IsMultisig() {
+		return nil, false, errors.New("Transaction is not a multisig simple transaction")
+	}
+	if err := base.Vin.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	signs, count := builder.getMultisigSigns(base.Vin.MultisigPublicKeys, base.Vin.MultisigSignatures)
+	if count == 0 {
+		return nil, false, errors.New("The wallet has no signer which is missing from the transaction")
+	}
+
+	builder.lock.Lock()
+	defer builder.lock.Unlock()
+
+	complete, err := wizard.SignSimpleTxMultisig(tx, signs, statusCallback)
+	if err != nil {
+		return nil, false, err
+	}
+	if !complete {
+		return tx, false, nil
+	}
+
+	if propagateTx {
+		if err = builder.mempool.AddTxToMempool(tx, 0, true, awaitAnswer, awaitBroadcast, advanced_connection_types.UUID_ALL, ctx); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return tx, true, nil
+}
+
 func TxsBuilderInit(wallet *wallet.Wallet, mempool *mempool.Mempool) error {
 
 	TxsBuilder = &TxsBuilderType{
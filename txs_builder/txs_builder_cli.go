@@ -12,9 +12,11 @@ import (
 	"pandora-pay/blockchain/data_storage/assets"
 	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/config"
 	"pandora-pay/config/config_assets"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
@@ -186,6 +188,52 @@ func (builder *TxsBuilderType) readAsset(text string, allowEmptyAsset bool) []by
 	return assetId
 }
 
+func (builder *TxsBuilderType) readPublicKeys(text string) [][]byte {
+
+	publicKeys := [][]byte{}
+	unique := make(map[string]bool)
+	for len(publicKeys) < config.TRANSACTIONS_MULTISIG_MAX {
+		pubKey := gui.GUI.OutputReadBytes(fmt.Sprintf("%s %d. Use enter to continue", text, len(publicKeys)), func(val []byte) bool {
+			return len(val) == 0 || len(val) == cryptography.PublicKeySize
+		})
+		if len(pubKey) == 0 {
+			break
+		}
+		if unique[string(pubKey)] {
+			gui.GUI.OutputWrite("PublicKey already included")
+			continue
+		}
+		unique[string(pubKey)] = true
+		publicKeys = append(publicKeys, pubKey)
+	}
+
+	return publicKeys
+}
+
+// readSimpleTxSender reads the sender address or the multisig plain account with its co-signers
+func (builder *TxsBuilderType) readSimpleTxSender(txData *TxBuilderCreateSimpleTx, text string, ctx context.Context) (err error) {
+
+	if gui.GUI.OutputReadBool("Multisig Plain Account? y/n. Leave empty for no", true, false) {
+		txData.MultisigAccount = gui.GUI.OutputReadBytes("Multisig Plain Account PublicKey", func(val []byte) bool {
+			return len(val) == cryptography.PublicKeySize
+		})
+		txData.MultisigPublicKeys = builder.readPublicKeys("PublicKey of the co-signer")
+		return
+	}
+
+	_, txData.Sender, _, err = builder.wallet.CliSelectAddress(text, ctx)
+	return
+}
+
+// showSimpleTxCLI shows the created tx or the partially signed multisig tx which is given to the next co-signer
+func (builder *TxsBuilderType) showSimpleTxCLI(tx *transaction.Transaction, cmd string) {
+	if tx.Bloom == nil {
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx partially signed. Give it to the next co-signer: %s", base64.StdEncoding.EncodeToString(tx.SerializeManualToBytes())))
+		return
+	}
+	gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+}
+
 func (builder *TxsBuilderType) initCLI() {
 
 	cliPrivateTransfer := func(cmd string, ctx context.Context) (err error) {
@@ -201,12 +249,19 @@ func (builder *TxsBuilderType) initCLI() {
 
 		txData.Payloads[0].Asset = builder.readAsset("Asset. Leave empty for Native Asset", true)
 
-		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Recipient Address", txData.Payloads[0].Asset, false); err != nil {
+		var recipient *addresses.Address
+		if recipient, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Recipient Address", txData.Payloads[0].Asset, false); err != nil {
 			return
 		}
 
+		if recipient.IsIntegratedPaymentID() {
+			txData.Payloads[0].PayInvoice = gui.GUI.OutputReadBool("Pay the invoice of the address? The PaymentID is sent as the message. y/n. Leave empty for yes", true, true)
+		}
+
 		builder.readZetherRingConfiguration(txData.Payloads[0])
-		txData.Payloads[0].Data = builder.readData()
+		if !txData.Payloads[0].PayInvoice {
+			txData.Payloads[0].Data = builder.readData()
+		}
 		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
@@ -278,14 +333,14 @@ func (builder *TxsBuilderType) initCLI() {
 		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
 
 		assetId := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads[0].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetCreate).GetAssetId(tx.Bloom.Hash, 0)
-		gui.GUI.OutputWrite(fmt.Sprintf("Asset Id: %s", base64.StdEncoding.EncodeToString(assetId), cmd))
+		gui.GUI.OutputWrite(fmt.Sprintf("Asset Id: %s", base64.StdEncoding.EncodeToString(assetId)))
 
 		if updatePrivKey != nil || supplyPrivKey != nil {
 
 			if filename := gui.GUI.OutputReadFilename("Path to export Asset Private Keys", "keys", true); len(filename) > 0 {
 				if err = files.WriteFile(filename,
 					fmt.Sprintf("Asset ID: %s", base64.StdEncoding.EncodeToString(assetId)),
-					fmt.Sprintf("Asset name: %s", extra.Asset.Name, extra.Asset.Ticker),
+					fmt.Sprintf("Asset name: %s (%s)", extra.Asset.Name, extra.Asset.Ticker),
 					fmt.Sprintf("Supply Private Key: %s", base64.StdEncoding.EncodeToString(supplyPrivKey.Key)),
 					fmt.Sprintf("Update Private Key: %s", base64.StdEncoding.EncodeToString(updatePrivKey.Key)),
 				); err != nil {
@@ -347,6 +402,124 @@ func (builder *TxsBuilderType) initCLI() {
 		return
 	}
 
+	cliPrivateAssetSupplyDecrease := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		extra := &wizard.WizardZetherPayloadExtraAssetSupplyDecrease{}
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: extra,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will burn the asset", ctx); err != nil {
+			return
+		}
+
+		txData.Payloads[0].Asset = builder.readAsset("Asset", false)
+
+		extra.AssetSupplyPrivateKey = gui.GUI.OutputReadBytes("Asset Supply Update Private Key", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		})
+
+		if txData.Payloads[0].Burn, err = builder.readAmount(txData.Payloads[0].Asset, "Burn Amount"); err != nil {
+			return
+		}
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", txData.Payloads[0].Asset, true); err != nil {
+			return
+		}
+
+		builder.readZetherRingConfiguration(txData.Payloads[0])
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return
+	}
+
+	cliPrivateAssetUpdate := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Address which will update the asset", ctx); err != nil {
+			return
+		}
+
+		assetId := builder.readAsset("Asset", false)
+
+		assetUpdatePrivateKey := gui.GUI.OutputReadBytes("Asset Update Private Key", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		})
+
+		gui.GUI.OutputWrite("0 - Pause")
+		gui.GUI.OutputWrite("1 - Unpause")
+		gui.GUI.OutputWrite("2 - Freeze Supply")
+		gui.GUI.OutputWrite("3 - Change Keys")
+		update := gui.GUI.OutputReadInt("Select Update", false, 0, func(value int) bool {
+			return value >= 0 && value <= 3
+		})
+
+		switch update {
+		case 0, 1:
+			txData.Payloads[0].Extra = &wizard.WizardZetherPayloadExtraAssetPause{
+				AssetId:               assetId,
+				Paused:                update == 0,
+				AssetUpdatePrivateKey: assetUpdatePrivateKey,
+			}
+		case 2:
+			txData.Payloads[0].Extra = &wizard.WizardZetherPayloadExtraAssetFreeze{
+				AssetId:               assetId,
+				AssetUpdatePrivateKey: assetUpdatePrivateKey,
+			}
+		case 3:
+			extra := &wizard.WizardZetherPayloadExtraAssetUpdateKeys{
+				AssetId:               assetId,
+				AssetUpdatePrivateKey: assetUpdatePrivateKey,
+			}
+			validateKey := func(value []byte) bool {
+				return len(value) == 0 || len(value) == cryptography.PublicKeySize
+			}
+			extra.NewUpdatePublicKey = gui.GUI.OutputReadBytes("New Asset Update Public Key. Leave empty to keep it", validateKey)
+			extra.NewSupplyPublicKey = gui.GUI.OutputReadBytes("New Asset Supply Public Key. Leave empty to keep it", validateKey)
+			txData.Payloads[0].Extra = extra
+		}
+
+		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Transfer Address", config_coins.NATIVE_ASSET_FULL, true); err != nil {
+			return
+		}
+
+		builder.readZetherRingConfiguration(txData.Payloads[0])
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(config_coins.NATIVE_ASSET_FULL)
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+
+		return
+	}
+
 	cliPrivatePlainAccountFund := func(cmd string, ctx context.Context) (err error) {
 		builder.showWarningIfNotSyncCLI()
 
@@ -484,7 +657,7 @@ func (builder *TxsBuilderType) initCLI() {
 			FeeVersion: true,
 		}
 
-		if _, txData.Sender, _, err = builder.wallet.CliSelectAddress("Select Address to Publicly Update Asset Fee Liquidity", ctx); err != nil {
+		if err = builder.readSimpleTxSender(txData, "Select Address to Publicly Update Asset Fee Liquidity", ctx); err != nil {
 			return
 		}
 
@@ -524,7 +697,47 @@ func (builder *TxsBuilderType) initCLI() {
 			return
 		}
 
-		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+		builder.showSimpleTxCLI(tx, cmd)
+		return
+	}
+
+	cliUpdatePlainAccountMultisig := func(cmd string, ctx context.Context) (err error) {
+
+		builder.showWarningIfNotSyncCLI()
+
+		txExtra := &wizard.WizardTxSimpleExtraUpdatePlainAccountMultisig{}
+		txData := &TxBuilderCreateSimpleTx{
+			Extra:      txExtra,
+			FeeVersion: true,
+		}
+
+		if err = builder.readSimpleTxSender(txData, "Select Address to Publicly Update Plain Account Multisig", ctx); err != nil {
+			return
+		}
+
+		txExtra.Threshold = byte(gui.GUI.OutputReadUint64("Threshold. Use 0 to remove the multisig", true, 0, func(val uint64) bool {
+			return val <= config.TRANSACTIONS_MULTISIG_MAX
+		}))
+
+		txExtra.PublicKeys = [][]byte{}
+		if txExtra.Threshold > 0 {
+			txExtra.PublicKeys = builder.readPublicKeys("PublicKey used in multisig")
+		}
+
+		txData.Nonce = gui.GUI.OutputReadUint64("Nonce. Leave empty for automatically detection", true, 0, nil)
+		txData.Data = builder.readData()
+		txData.Fee = builder.readFee(config_coins.NATIVE_ASSET_FULL)
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateSimpleTx(txData, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		builder.showSimpleTxCLI(tx, cmd)
 		return
 	}
 
@@ -600,12 +813,63 @@ func (builder *TxsBuilderType) initCLI() {
 		return
 	}
 
+	cliBumpSimpleTx := func(cmd string, ctx context.Context) (err error) {
+
+		builder.showWarningIfNotSyncCLI()
+
+		txData := &TxBuilderBumpSimpleTx{}
+
+		txData.TxId = gui.GUI.OutputReadBytes("Pending TxId", func(val []byte) bool {
+			return len(val) == cryptography.HashSize
+		})
+		txData.Fee = builder.readFee(config_coins.NATIVE_ASSET_FULL)
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.BumpSimpleTx(txData, propagate, true, true, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		builder.showSimpleTxCLI(tx, cmd)
+		return
+	}
+
+	cliSignSimpleTxMultisig := func(cmd string, ctx context.Context) (err error) {
+
+		builder.showWarningIfNotSyncCLI()
+
+		txData := &TxBuilderSignSimpleTxMultisig{}
+		txData.Tx = gui.GUI.OutputReadBytes("Partially signed Tx", func(val []byte) bool {
+			return len(val) > 0
+		})
+
+		propagate := gui.GUI.OutputReadBool("Propagate once all the co-signers signed? y/n. Leave empty for yes", true, true)
+
+		tx, _, err := builder.SignSimpleTxMultisig(txData, propagate, true, true, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		builder.showSimpleTxCLI(tx, cmd)
+		return
+	}
+
 	gui.GUI.CommandDefineCallback("Private Transfer", cliPrivateTransfer, true)
 	gui.GUI.CommandDefineCallback("Private Asset Create", cliPrivateAssetCreate, true)
 	gui.GUI.CommandDefineCallback("Private Asset Supply Increase", cliPrivateAssetSupplyIncrease, true)
+	gui.GUI.CommandDefineCallback("Private Asset Supply Decrease", cliPrivateAssetSupplyDecrease, true)
+	gui.GUI.CommandDefineCallback("Private Asset Update", cliPrivateAssetUpdate, true)
 	gui.GUI.CommandDefineCallback("Private Plain Account Fund", cliPrivatePlainAccountFund, true)
 	gui.GUI.CommandDefineCallback("Private Conditional Payment", cliPrivateConditionalPayment, true)
 	gui.GUI.CommandDefineCallback("Public Update Asset Fee Liquidity", cliUpdateAssetFeeLiquidity, true)
 	gui.GUI.CommandDefineCallback("Public Resolution Conditional Payment", cliResolutionConditionalPayment, true)
+	gui.GUI.CommandDefineCallback("Public Update Plain Account Multisig", cliUpdatePlainAccountMultisig, true)
+	gui.GUI.CommandDefineCallback("Public Bump Transaction Fee", cliBumpSimpleTx, true)
+	gui.GUI.CommandDefineCallback("Public Multisig Sign Transaction", cliSignSimpleTxMultisig, true)
 
 }
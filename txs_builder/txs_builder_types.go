@@ -9,4 +9,16 @@ type TxBuilderCreateSimpleTx struct {
 	Fee        *wizard.WizardTransactionFee  `json:"fee" msgpack:"fee"`
 	FeeVersion bool                          `json:"feeVersion" msgpack:"feeVersion"`
 	Extra      wizard.WizardTxSimpleExtra    `json:"extra" msgpack:"sender"`
+	//multisig plain account used instead of Sender. The signers kept by the wallet sign and the other co-signers sign later with SignSimpleTxMultisig
+	MultisigAccount    []byte   `json:"multisigAccount,omitempty" msgpack:"multisigAccount,omitempty"`
+	MultisigPublicKeys [][]byte `json:"multisigPublicKeys,omitempty" msgpack:"multisigPublicKeys,omitempty"`
+}
+
+type TxBuilderSignSimpleTxMultisig struct {
+	Tx []byte `json:"tx" msgpack:"tx"` //partially signed tx
+}
+
+type TxBuilderBumpSimpleTx struct {
+	TxId []byte                       `json:"txId" msgpack:"txId"`
+	Fee  *wizard.WizardTransactionFee `json:"fee" msgpack:"fee"`
 }
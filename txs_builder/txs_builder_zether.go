@@ -22,6 +22,7 @@ import (
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
+	"pandora-pay/helpers"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -291,6 +292,26 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 			payload.Fee = &wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, false, 0, 0}
 		}
 
+		//the payment id of the invoice is sent as the message to let the recipient identify the payment
+		if payload.PayInvoice {
+			recipient, err := addresses.DecodeAddr(payload.Recipient)
+			if err != nil {
+				return nil, nil, nil, nil, nil, nil, 0, nil, err
+			}
+			if !recipient.IsIntegratedPaymentID() {
+				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("Recipient is not the address of an invoice")
+			}
+			if recipient.IsIntegratedPaymentAsset() && !bytes.Equal(recipient.PaymentAsset, payload.Asset) {
+				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("Asset is different than the one requested by the recipient")
+			}
+			if recipient.IsIntegratedAmount() && payload.Amount == 0 {
+				payload.Amount = recipient.PaymentAmount
+			}
+			if len(payload.Data.Data) == 0 {
+				payload.Data = &wizard.WizardTransactionData{helpers.CloneBytes(recipient.PaymentID), true}
+			}
+		}
+
 		sendAssets[t] = payload.Asset
 		if payload.Sender == "" {
 
@@ -308,6 +329,9 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 				return nil, nil, nil, nil, nil, nil, 0, nil, err
 			}
 
+			if addr.IsWatchOnly() {
+				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("Can't be used for transactions as the address is watch-only")
+			}
 			if addr.PrivateKey == nil {
 				return nil, nil, nil, nil, nil, nil, 0, nil, errors.New("Can't be used for transactions as the private key is missing")
 			}
@@ -431,6 +455,12 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 				payload.Fee.LeadingZeros = assetFeeLiquidity.LeadingZeros
 			}
 
+			if bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) {
+				builder.estimateFee(payload.Fee.WizardTransactionFee, true, 0, 0)
+			} else {
+				builder.estimateFee(payload.Fee.WizardTransactionFee, true, payload.Fee.Rate, payload.Fee.LeadingZeros)
+			}
+
 			transfers[t] = &wizard.WizardZetherTransfer{
 				Asset:            payload.Asset,
 				SenderPrivateKey: sendersPrivateKeys[t].Key[:],
@@ -527,13 +557,20 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 				if sender {
 					if reg != nil && len(reg.SpendPublicKey) > 0 && payload.Extra == nil {
 						transfers[t].SenderSpendRequired = true
-						if sendersWalletAddresses[t].SpendPrivateKey == nil {
+						if sendersWalletAddresses[t].SpendPrivateKey == nil && sendersWalletAddresses[t].ExternalSigner == nil {
 							return errors.New("Spend Private Key is missing")
 						}
 						if !bytes.Equal(sendersWalletAddresses[t].SpendPublicKey, reg.SpendPublicKey) {
 							return errors.New("Wallet Spend Public Key is not matching")
 						}
-						transfers[t].SenderSpendPrivateKey = sendersWalletAddresses[t].SpendPrivateKey.Key
+						if sendersWalletAddresses[t].SpendPrivateKey != nil {
+							transfers[t].SenderSpendPrivateKey = sendersWalletAddresses[t].SpendPrivateKey.Key
+						} else {
+							transfers[t].SenderSpendPublicKey = reg.SpendPublicKey
+							if transfers[t].SenderSpendSign, err = sendersWalletAddresses[t].GetSigner(reg.SpendPublicKey); err != nil {
+								return
+							}
+						}
 					}
 				}
 
@@ -611,6 +648,10 @@ func (builder *TxsBuilderType) prebuild(txData *TxBuilderCreateZetherTxData, pen
 
 func (builder *TxsBuilderType) CreateZetherTx(txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, propagateTx, awaitAnswer, awaitBroadcast bool, validateTx bool, ctx context.Context, statusCallback func(string)) (*transaction.Transaction, error) {
 
+	if err := builder.wallet.Encryption.CheckUnlocked(); err != nil {
+		return nil, err
+	}
+
 	if pendingTxs == nil {
 		pendingTxs = builder.mempool.Txs.GetTxsOnlyList()
 	}
@@ -689,6 +730,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 				nil,
 				&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, false}, false, 0, 0},
 				&wizard.WizardZetherPayloadExtraStaking{},
+				false,
 			},
 			{
 				txs_builder_zether_helper.TxsBuilderZetherTxPayloadBase{
@@ -705,6 +747,7 @@ func (builder *TxsBuilderType) CreateForgingTransactions(blkComplete *block_comp
 				nil,
 				&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, false}, false, 0, 0},
 				&wizard.WizardZetherPayloadExtraStakingReward{nil, finalForgerReward},
+				false,
 			},
 		},
 	}
@@ -0,0 +1,390 @@
+package txs_builder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"golang.org/x/exp/slices"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account/asset_fee_liquidity"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_fees"
+	"pandora-pay/config/config_wallet"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder/txs_builder_zether_helper"
+	"pandora-pay/txs_builder/wizard"
+	"pandora-pay/wallet/wallet_address"
+)
+
+type batchPayout struct {
+	index              int
+	sender             *wallet_address.WalletAddress
+	recipient          string
+	recipientPublicKey []byte
+	asset              []byte
+	amount             uint64
+	data               *wizard.WizardTransactionData
+}
+
+// estimateZetherPayloadSize is an upper bound of the serialized payload as all the ring members are considered unregistered
+func estimateZetherPayloadSize(ringSize int) uint64 {
+	return 1536 + 150*uint64(ringSize)
+}
+
+func (ringConfiguration *ZetherRingConfiguration) clone() *ZetherRingConfiguration {
+	senderRingType := *ringConfiguration.SenderRingType
+	senderRingType.IncludeMembers = slices.Clone(senderRingType.IncludeMembers)
+	recipientRingType := *ringConfiguration.RecipientRingType
+	recipientRingType.IncludeMembers = slices.Clone(recipientRingType.IncludeMembers)
+	return &ZetherRingConfiguration{&senderRingType, &recipientRingType}
+}
+
+func cloneZetherFee(fee *wizard.WizardZetherTransactionFee) *wizard.WizardZetherTransactionFee {
+	return &wizard.WizardZetherTransactionFee{
+		WizardTransactionFee: fee.WizardTransactionFee.Clone(),
+		Auto:                 fee.Auto,
+		Rate:                 fee.Rate,
+		LeadingZeros:         fee.LeadingZeros,
+	}
+}
+
+func (builder *TxsBuilderType) validateBatchPayout(txData *TxBuilderBatchPayoutData, recipient *TxBuilderBatchPayoutRecipient, index int) (*batchPayout, error) {
+
+	if recipient == nil {
+		return nil, errors.New("Recipient is missing")
+	}
+
+	sender := recipient.Sender
+	if sender == "" {
+		sender = txData.Sender
+	}
+
+	senders, err := builder.getWalletAddresses([]string{sender})
+	if err != nil {
+		return nil, err
+	}
+	if senders[0].PrivateKey == nil {
+		return nil, errors.New("Can't be used for transactions as the private key is missing")
+	}
+
+	addr, err := addresses.DecodeAddr(recipient.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	payout := &batchPayout{index, senders[0], recipient.Recipient, addr.PublicKey, recipient.Asset, recipient.Amount, recipient.Data}
+	if len(payout.asset) == 0 {
+		payout.asset = config_coins.NATIVE_ASSET_FULL
+	}
+	if len(payout.asset) != config_coins.ASSET_LENGTH {
+		return nil, errors.New("Invalid asset")
+	}
+	if bytes.Equal(addr.PublicKey, payout.sender.PublicKey) {
+		return nil, errors.New("Sender and Recipient are identical")
+	}
+	if addr.IsIntegratedPaymentAsset() && !bytes.Equal(addr.PaymentAsset, payout.asset) {
+		return nil, errors.New("Asset is different than the one requested by the recipient")
+	}
+	if addr.IsIntegratedAmount() && payout.amount == 0 {
+		payout.amount = addr.PaymentAmount
+	}
+	if payout.amount == 0 {
+		return nil, errors.New("Amount must be positive")
+	}
+
+	return payout, nil
+}
+
+// getBatchPayoutFunds returns the balance of every sender and asset including the pending transactions, the estimated fee of a payload and the number of accounts of every asset
+func (builder *TxsBuilderType) getBatchPayoutFunds(payouts []*batchPayout, fee *wizard.WizardZetherTransactionFee, payloadSize uint64, ctx context.Context, statusCallback func(string)) (map[string]uint64, map[string]uint64, map[string]uint64, error) {
+
+	pendingTxs := builder.mempool.Txs.GetTxsOnlyList()
+
+	senders := make(map[string]map[string]*wallet_address.WalletAddress)
+	for _, payout := range payouts {
+		if senders[string(payout.asset)] == nil {
+			senders[string(payout.asset)] = make(map[string]*wallet_address.WalletAddress)
+		}
+		senders[string(payout.asset)][string(payout.sender.PublicKey)] = payout.sender
+	}
+
+	encryptedBalances := make(map[string][]byte)
+	fees := make(map[string]uint64)
+	accountsCount := make(map[string]uint64)
+
+	if err := store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		for assetStr, list := range senders {
+
+			asset := []byte(assetStr)
+
+			var accs *accounts.Accounts
+			if accs, err = dataStorage.AccsCollection.GetMap(asset); err != nil {
+				return
+			}
+			accountsCount[assetStr] = accs.Count
+
+			publicKeys := make([][]byte, 0, len(list))
+			balancesInit := make([]*crypto.ElGamal, 0, len(list))
+			hasRollovers := make([]bool, 0, len(list))
+
+			for publicKey := range list {
+
+				var acc *account.Account
+				if acc, err = accs.Get(publicKey); err != nil {
+					return
+				}
+				var reg *registration.Registration
+				if reg, err = dataStorage.Regs.Get(publicKey); err != nil {
+					return
+				}
+
+				var balance *crypto.ElGamal
+				if acc != nil {
+					balance = acc.Balance.Amount
+				}
+
+				publicKeys = append(publicKeys, []byte(publicKey))
+				balancesInit = append(balancesInit, balance)
+				hasRollovers = append(hasRollovers, reg != nil && bytes.Equal(asset, config_coins.NATIVE_ASSET_FULL) && reg.Staked)
+			}
+
+			var balances []*crypto.ElGamal
+			if balances, err = wizard.GetZetherBalanceMultiple(publicKeys, balancesInit, asset, hasRollovers, pendingTxs); err != nil {
+				return
+			}
+			for i, balance := range balances {
+				if balance != nil {
+					encryptedBalances[assetStr+string(publicKeys[i])] = balance.Serialize()
+				}
+			}
+
+			assetFee := cloneZetherFee(fee)
+			if !bytes.Equal(asset, config_coins.NATIVE_ASSET_FULL) && assetFee.Auto {
+				var assetFeeLiquidity *asset_fee_liquidity.AssetFeeLiquidity
+				if assetFeeLiquidity, err = dataStorage.GetAssetFeeLiquidityTop(asset); err != nil {
+					return
+				}
+				if assetFeeLiquidity == nil {
+					return errors.New("There is no Asset Fee Liquidity for this asset")
+				}
+				assetFee.Rate = assetFeeLiquidity.Rate
+				assetFee.LeadingZeros = assetFeeLiquidity.LeadingZeros
+			}
+			builder.estimateFee(assetFee.WizardTransactionFee, true, assetFee.Rate, assetFee.LeadingZeros)
+
+			if assetFee.Fixed > 0 {
+				fees[assetStr] = assetFee.Fixed
+			} else {
+				perByte := assetFee.PerByte
+				if perByte == 0 && assetFee.PerByteAuto {
+					perByte = config_fees.FEE_PER_BYTE_ZETHER
+				}
+				fees[assetStr] = payloadSize * perByte
+			}
+		}
+
+		return
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+
+	funds := make(map[string]uint64)
+	for assetStr, list := range senders {
+		for publicKey, addr := range list {
+			if encryptedBalances[assetStr+publicKey] == nil {
+				continue
+			}
+			decrypted, err := builder.wallet.DecryptBalance(addr, encryptedBalances[assetStr+publicKey], []byte(assetStr), false, 0, true, ctx, statusCallback)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			funds[assetStr+publicKey] = decrypted
+		}
+	}
+
+	return funds, fees, accountsCount, nil
+}
+
+// fundBatchPayouts returns the payouts which can be paid in order. The funds are decreased by the amounts and the fees of the returned payouts
+func fundBatchPayouts(payouts []*batchPayout, funds, fees map[string]uint64, results []*TxBuilderBatchPayoutRecipientResult) []*batchPayout {
+
+	funded := make([]*batchPayout, 0, len(payouts))
+	for _, payout := range payouts {
+
+		key := string(payout.asset) + string(payout.sender.PublicKey)
+
+		required := payout.amount
+		if err := helpers.SafeUint64Add(&required, fees[string(payout.asset)]); err != nil || funds[key] < required {
+			results[payout.index] = &TxBuilderBatchPayoutRecipientResult{BATCH_PAYOUT_INSUFFICIENT_FUNDS, nil, "Not enough funds"}
+			continue
+		}
+
+		funds[key] -= required
+		funded = append(funded, payout)
+	}
+
+	return funded
+}
+
+// packBatchPayouts places every payout in the first transaction with room for it. A sender can't pay the same recipient twice in a transaction as the rings would be identical.
+// The ring members of a transaction are distinct, so the rings of a transaction can't need more members than the accounts of its assets
+func packBatchPayouts(payouts []*batchPayout, maxPayloads, ringSize int, accountsCount map[string]uint64) [][]*batchPayout {
+
+	chunks := [][]*batchPayout{}
+	pairs := []map[string]bool{}
+	accounts := []uint64{} //the fewest accounts of the assets of the transaction
+
+	for _, payout := range payouts {
+
+		pair := string(payout.sender.PublicKey) + string(payout.recipientPublicKey)
+
+		found := false
+		for i := range chunks {
+
+			count := accounts[i]
+			if accountsCount[string(payout.asset)] < count {
+				count = accountsCount[string(payout.asset)]
+			}
+
+			if len(chunks[i]) < maxPayloads && !pairs[i][pair] && uint64((len(chunks[i])+1)*ringSize) <= count {
+				chunks[i] = append(chunks[i], payout)
+				pairs[i][pair] = true
+				accounts[i] = count
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			chunks = append(chunks, []*batchPayout{payout})
+			pairs = append(pairs, map[string]bool{pair: true})
+			accounts = append(accounts, accountsCount[string(payout.asset)])
+		}
+	}
+
+	return chunks
+}
+
+// CreateZetherBatchPayout packs the payouts in as few zether transactions as possible. The transactions are submitted in order, each one waiting for the mempool answer, so the next ones spend the balances left by them
+func (builder *TxsBuilderType) CreateZetherBatchPayout(txData *TxBuilderBatchPayoutData, awaitBroadcast bool, ctx context.Context, statusCallback func(string)) ([]*transaction.Transaction, []*TxBuilderBatchPayoutRecipientResult, error) {
+
+	if err := builder.wallet.Encryption.CheckUnlocked(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(txData.Recipients) == 0 {
+		return nil, nil, errors.New("There are no recipients")
+	}
+	if len(txData.Recipients) > config_wallet.BATCH_PAYOUTS_MAX_RECIPIENTS {
+		return nil, nil, errors.New("Too many recipients")
+	}
+
+	if txData.Fee == nil {
+		txData.Fee = &wizard.WizardZetherTransactionFee{
+			WizardTransactionFee: &wizard.WizardTransactionFee{PerByteAuto: true},
+			Auto:                 true,
+		}
+	}
+	if txData.Fee.WizardTransactionFee == nil {
+		txData.Fee.WizardTransactionFee = &wizard.WizardTransactionFee{PerByteAuto: true}
+	}
+	if txData.RingConfiguration == nil {
+		txData.RingConfiguration = &ZetherRingConfiguration{&ZetherSenderRingType{false, false, nil, 0}, &ZetherRecipientRingType{false, false, nil, -1}}
+	}
+	if txData.RingConfiguration.SenderRingType == nil || txData.RingConfiguration.RecipientRingType == nil {
+		return nil, nil, errors.New("Invalid ring configuration")
+	}
+
+	//the ring size is chosen once as the payloads of the same sender share the sender ring
+	preset := &TxBuilderCreateZetherTxPayload{RingConfiguration: txData.RingConfiguration.clone()}
+	preset.RingSize = txData.RingSize
+	if err := builder.presetZetherRing(preset); err != nil {
+		return nil, nil, err
+	}
+	ringSize := preset.RingSize
+
+	payloadSize := estimateZetherPayloadSize(ringSize)
+	maxPayloads := int(config_wallet.BATCH_PAYOUTS_TX_MAX_SIZE / payloadSize)
+	if maxPayloads > config_wallet.BATCH_PAYOUTS_MAX_PAYLOADS {
+		maxPayloads = config_wallet.BATCH_PAYOUTS_MAX_PAYLOADS
+	}
+	if maxPayloads == 0 {
+		return nil, nil, errors.New("Ring size is too big")
+	}
+
+	results := make([]*TxBuilderBatchPayoutRecipientResult, len(txData.Recipients))
+
+	payouts := make([]*batchPayout, 0, len(txData.Recipients))
+	for i, recipient := range txData.Recipients {
+		payout, err := builder.validateBatchPayout(txData, recipient, i)
+		if err != nil {
+			results[i] = &TxBuilderBatchPayoutRecipientResult{BATCH_PAYOUT_INVALID, nil, err.Error()}
+			continue
+		}
+		payouts = append(payouts, payout)
+	}
+
+	statusCallback("Batch payouts validated")
+
+	funds, fees, accountsCount, err := builder.getBatchPayoutFunds(payouts, txData.Fee, payloadSize, ctx, statusCallback)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statusCallback("Balances decoded")
+
+	funded := fundBatchPayouts(payouts, funds, fees, results)
+
+	chunks := packBatchPayouts(funded, maxPayloads, ringSize, accountsCount)
+	txs := make([]*transaction.Transaction, 0, len(chunks))
+
+	for _, chunk := range chunks {
+
+		if err = ctx.Err(); err == nil {
+
+			data := &TxBuilderCreateZetherTxData{
+				Payloads: make([]*TxBuilderCreateZetherTxPayload, len(chunk)),
+			}
+			for i, payout := range chunk {
+				data.Payloads[i] = &TxBuilderCreateZetherTxPayload{
+					TxsBuilderZetherTxPayloadBase: txs_builder_zether_helper.TxsBuilderZetherTxPayloadBase{
+						Sender:    payout.sender.GetAddress(true),
+						Recipient: payout.recipient,
+						RingSize:  ringSize,
+					},
+					Asset:             payout.asset,
+					Amount:            payout.amount,
+					RingConfiguration: txData.RingConfiguration.clone(),
+					Data:              payout.data,
+					Fee:               cloneZetherFee(txData.Fee),
+				}
+			}
+
+			var tx *transaction.Transaction
+			if tx, err = builder.CreateZetherTx(data, nil, true, true, awaitBroadcast, false, ctx, statusCallback); err == nil {
+				txs = append(txs, tx)
+				for _, payout := range chunk {
+					results[payout.index] = &TxBuilderBatchPayoutRecipientResult{BATCH_PAYOUT_SUBMITTED, tx.Bloom.Hash, ""}
+				}
+				continue
+			}
+		}
+
+		for _, payout := range chunk {
+			results[payout.index] = &TxBuilderBatchPayoutRecipientResult{BATCH_PAYOUT_FAILED, nil, err.Error()}
+		}
+	}
+
+	return txs, results, nil
+}
@@ -0,0 +1,100 @@
+package txs_builder
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+	"pandora-pay/wallet/wallet_address"
+	"testing"
+)
+
+func newTestBatchPayouts(senders []*wallet_address.WalletAddress, recipients [][]byte, asset []byte, amount uint64) []*batchPayout {
+	payouts := make([]*batchPayout, len(recipients))
+	for i := range recipients {
+		payouts[i] = &batchPayout{i, senders[i%len(senders)], "", recipients[i], asset, amount, nil}
+	}
+	return payouts
+}
+
+func TestPackBatchPayouts(t *testing.T) {
+
+	sender := &wallet_address.WalletAddress{PublicKey: helpers.RandomBytes(33)}
+	recipients := make([][]byte, 10)
+	for i := range recipients {
+		recipients[i] = helpers.RandomBytes(33)
+	}
+
+	accountsCount := map[string]uint64{string(config_coins.NATIVE_ASSET_FULL): 1000}
+
+	chunks := packBatchPayouts(newTestBatchPayouts([]*wallet_address.WalletAddress{sender}, recipients, config_coins.NATIVE_ASSET_FULL, 1), 4, 8, accountsCount)
+	assert.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], 4)
+	assert.Len(t, chunks[1], 4)
+	assert.Len(t, chunks[2], 2)
+
+	//the same sender and recipient can't be in the same transaction
+	same := [][]byte{recipients[0], recipients[0], recipients[0], recipients[1]}
+	chunks = packBatchPayouts(newTestBatchPayouts([]*wallet_address.WalletAddress{sender}, same, config_coins.NATIVE_ASSET_FULL, 1), 4, 8, accountsCount)
+	assert.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], 2)
+	assert.Len(t, chunks[1], 1)
+	assert.Len(t, chunks[2], 1)
+
+	//the rings of a transaction can't need more members than the accounts of the asset
+	accountsCount[string(config_coins.NATIVE_ASSET_FULL)] = 20
+	chunks = packBatchPayouts(newTestBatchPayouts([]*wallet_address.WalletAddress{sender}, recipients, config_coins.NATIVE_ASSET_FULL, 1), 4, 8, accountsCount)
+	assert.Len(t, chunks, 5)
+	for _, chunk := range chunks {
+		assert.Len(t, chunk, 2)
+	}
+
+	//a single payout is always packed
+	accountsCount[string(config_coins.NATIVE_ASSET_FULL)] = 2
+	chunks = packBatchPayouts(newTestBatchPayouts([]*wallet_address.WalletAddress{sender}, recipients[:2], config_coins.NATIVE_ASSET_FULL, 1), 4, 8, accountsCount)
+	assert.Len(t, chunks, 2)
+
+	//the asset with the fewest accounts limits the transaction
+	asset := helpers.RandomBytes(config_coins.ASSET_LENGTH)
+	accountsCount[string(config_coins.NATIVE_ASSET_FULL)] = 1000
+	accountsCount[string(asset)] = 16
+	payouts := append(newTestBatchPayouts([]*wallet_address.WalletAddress{sender}, recipients[:2], config_coins.NATIVE_ASSET_FULL, 1), newTestBatchPayouts([]*wallet_address.WalletAddress{sender}, recipients[2:4], asset, 1)...)
+	chunks = packBatchPayouts(payouts, 4, 8, accountsCount)
+	assert.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], 2)
+	assert.Len(t, chunks[1], 2)
+}
+
+func TestFundBatchPayouts(t *testing.T) {
+
+	senders := []*wallet_address.WalletAddress{{PublicKey: helpers.RandomBytes(33)}, {PublicKey: helpers.RandomBytes(33)}}
+	recipients := make([][]byte, 6)
+	for i := range recipients {
+		recipients[i] = helpers.RandomBytes(33)
+	}
+
+	payouts := newTestBatchPayouts(senders, recipients, config_coins.NATIVE_ASSET_FULL, 100)
+
+	key0 := string(config_coins.NATIVE_ASSET_FULL) + string(senders[0].PublicKey)
+	key1 := string(config_coins.NATIVE_ASSET_FULL) + string(senders[1].PublicKey)
+	funds := map[string]uint64{key0: 250, key1: 1000}
+	fees := map[string]uint64{string(config_coins.NATIVE_ASSET_FULL): 10}
+	results := make([]*TxBuilderBatchPayoutRecipientResult, len(payouts))
+
+	funded := fundBatchPayouts(payouts, funds, fees, results)
+
+	//the first sender pays only two payouts
+	assert.Len(t, funded, 5)
+	assert.Nil(t, results[0])
+	assert.Nil(t, results[2])
+	assert.Equal(t, BATCH_PAYOUT_INSUFFICIENT_FUNDS, results[4].Status)
+
+	//the change of the senders
+	assert.Equal(t, uint64(250-2*110), funds[key0])
+	assert.Equal(t, uint64(1000-3*110), funds[key1])
+
+	//a sender without balance pays nothing
+	payouts = newTestBatchPayouts([]*wallet_address.WalletAddress{{PublicKey: helpers.RandomBytes(33)}}, recipients[:1], config_coins.NATIVE_ASSET_FULL, 1)
+	results = make([]*TxBuilderBatchPayoutRecipientResult, 1)
+	assert.Len(t, fundBatchPayouts(payouts, funds, fees, results), 0)
+	assert.Equal(t, BATCH_PAYOUT_INSUFFICIENT_FUNDS, results[0].Status)
+}
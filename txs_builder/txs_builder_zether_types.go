@@ -34,8 +34,55 @@ type TxBuilderCreateZetherTxPayload struct {
 	Data              *wizard.WizardTransactionData      `json:"data" msgpack:"data"`
 	Fee               *wizard.WizardZetherTransactionFee `json:"fee" msgpack:"fee"`
 	Extra             wizard.WizardZetherPayloadExtra    `json:"extra" msgpack:"extra"`
+	PayInvoice        bool                               `json:"payInvoice" msgpack:"payInvoice"` //the recipient is the integrated address of an invoice
 }
 
 type TxBuilderCreateZetherTxData struct {
 	Payloads []*TxBuilderCreateZetherTxPayload `json:"payloads" msgpack:"payloads"`
 }
+
+type TxBuilderBatchPayoutRecipient struct {
+	Sender    string                        `json:"sender,omitempty" msgpack:"sender,omitempty"` //empty uses the sender of the batch
+	Recipient string                        `json:"recipient" msgpack:"recipient"`
+	Asset     []byte                        `json:"asset" msgpack:"asset"`
+	Amount    uint64                        `json:"amount" msgpack:"amount"`
+	Data      *wizard.WizardTransactionData `json:"data" msgpack:"data"`
+}
+
+type TxBuilderBatchPayoutData struct {
+	Sender            string                             `json:"sender" msgpack:"sender"`
+	Recipients        []*TxBuilderBatchPayoutRecipient   `json:"recipients" msgpack:"recipients"`
+	RingSize          int                                `json:"ringSize" msgpack:"ringSize"` //-1 for random. All payloads use the same ring size as the payloads of a sender share the ring
+	RingConfiguration *ZetherRingConfiguration           `json:"ringConfiguration" msgpack:"ringConfiguration"`
+	Fee               *wizard.WizardZetherTransactionFee `json:"fee" msgpack:"fee"`
+}
+
+type TxBuilderBatchPayoutStatus byte
+
+const (
+	BATCH_PAYOUT_SUBMITTED TxBuilderBatchPayoutStatus = iota
+	BATCH_PAYOUT_INVALID
+	BATCH_PAYOUT_INSUFFICIENT_FUNDS
+	BATCH_PAYOUT_FAILED
+)
+
+func (s TxBuilderBatchPayoutStatus) String() string {
+	switch s {
+	case BATCH_PAYOUT_SUBMITTED:
+		return "submitted"
+	case BATCH_PAYOUT_INVALID:
+		return "invalid"
+	case BATCH_PAYOUT_INSUFFICIENT_FUNDS:
+		return "insufficient funds"
+	case BATCH_PAYOUT_FAILED:
+		return "failed"
+	default:
+		return "Unknown Batch Payout Status"
+	}
+}
+
+type TxBuilderBatchPayoutRecipientResult struct {
+	Status TxBuilderBatchPayoutStatus `json:"status" msgpack:"status"`
+	TxHash []byte                     `json:"txHash,omitempty" msgpack:"txHash,omitempty"`
+	Error  string                     `json:"error,omitempty" msgpack:"error,omitempty"`
+}
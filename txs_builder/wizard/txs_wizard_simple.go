@@ -1,7 +1,9 @@
 package wizard
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
@@ -9,6 +11,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_parts"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers"
 )
 
@@ -57,18 +60,59 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 		}
 		txBase.TxScript = transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT
 		transfer.Fee = &WizardTransactionFee{0, 0, 0, false}
+	case *WizardTxSimpleExtraUpdatePlainAccountMultisig:
+		txBase.Extra = &transaction_simple_extra.TransactionSimpleExtraUpdatePlainAccountMultisig{nil,
+			txExtra.Threshold,
+			txExtra.PublicKeys,
+		}
+		txBase.TxScript = transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG
+
+		spaceExtra += 2 + len(txExtra.PublicKeys)*cryptography.PublicKeySize
 	}
 
 	var privateKey *addresses.PrivateKey
 
 	switch txBase.TxScript {
-	case transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY:
-		if privateKey, err = addresses.NewPrivateKey(transfer.Key); err != nil {
-			return nil, err
-		}
+	case transaction_simple.SCRIPT_UPDATE_ASSET_FEE_LIQUIDITY, transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG:
 
-		txBase.Vin = &transaction_simple_parts.TransactionSimpleInput{
-			PublicKey: privateKey.GeneratePublicKey(),
+		if len(transfer.MultisigPublicKeys) > 0 {
+
+			if len(transfer.MultisigPublicKey) != cryptography.PublicKeySize {
+				return nil, errors.New("Multisig Public Key is invalid")
+			}
+			if len(transfer.MultisigSigns) != len(transfer.MultisigPublicKeys) {
+				return nil, errors.New("Multisig signers and Public Keys mismatch")
+			}
+
+			//the signatures are empty until every signer signs
+			txBase.Vin = &transaction_simple_parts.TransactionSimpleInput{
+				PublicKey:          transfer.MultisigPublicKey,
+				MultisigPublicKeys: transfer.MultisigPublicKeys,
+				MultisigSignatures: make([][]byte, len(transfer.MultisigPublicKeys)),
+			}
+			for i := range txBase.Vin.MultisigSignatures {
+				txBase.Vin.MultisigSignatures[i] = make([]byte, cryptography.SignatureSize)
+			}
+
+		} else if transfer.Sign != nil {
+
+			if len(transfer.PublicKey) != cryptography.PublicKeySize {
+				return nil, errors.New("Public Key is invalid")
+			}
+
+			txBase.Vin = &transaction_simple_parts.TransactionSimpleInput{
+				PublicKey: transfer.PublicKey,
+			}
+
+		} else {
+
+			if privateKey, err = addresses.NewPrivateKey(transfer.Key); err != nil {
+				return nil, err
+			}
+
+			txBase.Vin = &transaction_simple_parts.TransactionSimpleInput{
+				PublicKey: privateKey.GeneratePublicKey(),
+			}
 		}
 
 	case transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT:
@@ -84,6 +128,9 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 	statusCallback("Transaction Created")
 
 	extraBytes := cryptography.SignatureSize
+	if txBase.Vin != nil && txBase.Vin.IsMultisig() {
+		extraBytes = 0 //the empty signatures are serialized
+	}
 	txBase.Fee = setFee(tx, extraBytes, transfer.Fee.Clone(), true)
 	statusCallback("Transaction Fee set")
 
@@ -94,9 +141,23 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 			return nil, err
 		}
 		statusCallback("Transaction Signed")
+	} else if txBase.Vin != nil && transfer.Sign != nil && !txBase.Vin.IsMultisig() {
+		statusCallback("Waiting for the External Signer...")
+		if txBase.Vin.Signature, err = transfer.Sign(tx.SerializeForSigning()); err != nil {
+			return nil, err
+		}
+		statusCallback("Transaction Signed")
 	}
 
-	if err = bloomAllTx(tx, statusCallback); err != nil {
+	if txBase.Vin != nil && txBase.Vin.IsMultisig() {
+		var complete bool
+		if complete, err = SignSimpleTxMultisig(tx, transfer.MultisigSigns, statusCallback); err != nil {
+			return nil, err
+		}
+		if !complete {
+			return tx, nil
+		}
+	} else if err = bloomAllTx(tx, statusCallback); err != nil {
 		return
 	}
 
@@ -115,3 +176,51 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 
 	return tx, nil
 }
+
+// SignSimpleTxMultisig adds the signatures of the multisig signers. signs has a signer for every multisig public key and the nil signers are skipped.
+// The tx is bloomed once all the signatures are included. Until then, the tx is a partially signed tx which is exported to the co-signers
+func SignSimpleTxMultisig(tx *transaction.Transaction, signs []WizardSign, statusCallback func(string)) (complete bool, err error) {
+
+	txBase, ok := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+	if !ok || txBase.Vin == nil || !txBase.Vin.IsMultisig() {
+		return false, errors.New("Transaction is not a multisig simple transaction")
+	}
+	if len(signs) != len(txBase.Vin.MultisigPublicKeys) {
+		return false, errors.New("Multisig signers and Public Keys mismatch")
+	}
+
+	hashForSignature := tx.SerializeForSigning()
+
+	for i, sign := range signs {
+		if sign == nil {
+			continue
+		}
+
+		var signature []byte
+		if signature, err = sign(hashForSignature); err != nil {
+			return
+		}
+		if !crypto.VerifySignature(hashForSignature, signature, txBase.Vin.MultisigPublicKeys[i]) {
+			return false, fmt.Errorf("Multisig signature %d is invalid", i)
+		}
+
+		txBase.Vin.MultisigSignatures[i] = signature
+		statusCallback(fmt.Sprintf("Transaction Multisig Signature %d added", i))
+	}
+
+	for i := range txBase.Vin.MultisigSignatures {
+		if bytes.Equal(txBase.Vin.MultisigSignatures[i], make([]byte, cryptography.SignatureSize)) {
+			statusCallback("Transaction Partially Signed")
+			return false, nil
+		}
+	}
+
+	//the signatures changed the serialized tx
+	tx.Bloom = nil
+	txBase.Bloom = nil
+	if err = bloomAllTx(tx, statusCallback); err != nil {
+		return
+	}
+
+	return true, nil
+}
@@ -1 +1,91 @@
 package wizard
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+	"testing"
+)
+
+func TestCreateSimpleTxMultisigPartial(t *testing.T) {
+
+	account := addresses.GenerateNewPrivateKey()
+	signers := []*addresses.PrivateKey{addresses.GenerateNewPrivateKey(), addresses.GenerateNewPrivateKey()}
+
+	publicKeys := make([][]byte, len(signers))
+	for i := range signers {
+		publicKeys[i] = signers[i].GeneratePublicKey()
+	}
+
+	transfer := &WizardTxSimpleTransfer{
+		&WizardTxSimpleExtraUpdatePlainAccountMultisig{nil, 1, [][]byte{signers[0].GeneratePublicKey()}},
+		&WizardTransactionData{nil, false},
+		&WizardTransactionFee{0, 0, 0, true},
+		1,
+		nil,
+		account.GeneratePublicKey(),
+		publicKeys,
+		[]WizardSign{signers[0].Sign, nil},
+		nil,
+		nil,
+	}
+
+	tx, err := CreateSimpleTx(transfer, false, func(string) {})
+	assert.NoError(t, err)
+	assert.Nil(t, tx.Bloom, "partially signed tx should not be bloomed")
+
+	//the co-signer imports the exported tx
+	partial := &transaction.Transaction{}
+	assert.NoError(t, partial.Deserialize(advanced_buffers.NewBufferReader(tx.SerializeManualToBytes())))
+
+	base := partial.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
+	assert.True(t, base.Vin.IsMultisig())
+	assert.Equal(t, base.Vin.MultisigPublicKeys, publicKeys)
+	assert.Equal(t, base.Vin.MultisigSignatures[1], make([]byte, cryptography.SignatureSize))
+
+	_, err = SignSimpleTxMultisig(partial, []WizardSign{nil, signers[0].Sign}, func(string) {})
+	assert.Error(t, err, "signature of another signer should be rejected")
+
+	complete, err := SignSimpleTxMultisig(partial, []WizardSign{nil, signers[1].Sign}, func(string) {})
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.NotNil(t, partial.Bloom)
+	assert.True(t, partial.VerifySignatureManually())
+}
+
+func TestCreateSimpleTxSingleSignatureLayout(t *testing.T) {
+
+	sender := addresses.GenerateNewPrivateKey()
+
+	transfer := &WizardTxSimpleTransfer{
+		&WizardTxSimpleExtraUpdatePlainAccountMultisig{nil, 0, [][]byte{}},
+		&WizardTransactionData{nil, false},
+		&WizardTransactionFee{0, 0, 0, true},
+		1,
+		sender.Key,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	}
+
+	tx, err := CreateSimpleTx(transfer, true, func(string) {})
+	assert.NoError(t, err)
+
+	//the single signature Vin has no multisig flag and no signers count
+	serialized := tx.SerializeManualToBytes()
+	r := advanced_buffers.NewBufferReader(serialized)
+	_, _ = r.ReadUvarint() //tx version
+	_, _ = r.ReadUvarint() //space extra
+	script, err := r.ReadUvarint()
+	assert.NoError(t, err)
+	assert.Equal(t, script, uint64(transaction_simple.SCRIPT_UPDATE_PLAIN_ACCOUNT_MULTISIG))
+
+	decoded := &transaction.Transaction{}
+	assert.NoError(t, decoded.Deserialize(advanced_buffers.NewBufferReader(serialized)))
+	assert.True(t, decoded.VerifySignatureManually())
+}
@@ -23,10 +23,23 @@ type WizardTxSimpleExtraResolutionConditionalPayment struct {
 	Signatures          [][]byte `json:"signatures" msgpack:"signatures"`
 }
 
+type WizardTxSimpleExtraUpdatePlainAccountMultisig struct {
+	WizardTxSimpleExtra `json:"-"  msgpack:"-"`
+	Threshold           byte     `json:"threshold" msgpack:"threshold"`
+	PublicKeys          [][]byte `json:"publicKeys" msgpack:"publicKeys"`
+}
+
 type WizardTxSimpleTransfer struct {
 	Extra WizardTxSimpleExtra    `json:"extra" msgpack:"extra"`
 	Data  *WizardTransactionData `json:"data" msgpack:"data"`
 	Fee   *WizardTransactionFee  `json:"fee" msgpack:"fee"`
 	Nonce uint64                 `json:"nonce" msgpack:"nonce"`
 	Key   []byte                 `json:"key" msgpack:"key"`
+	//used for multisig plain accounts instead of Key. MultisigSigns has a signer for every multisig public key. The nil signers are co-signers which sign later with SignSimpleTxMultisig
+	MultisigPublicKey  []byte       `json:"multisigPublicKey,omitempty" msgpack:"multisigPublicKey,omitempty"`
+	MultisigPublicKeys [][]byte     `json:"multisigPublicKeys,omitempty" msgpack:"multisigPublicKeys,omitempty"`
+	MultisigSigns      []WizardSign `json:"-" msgpack:"-"`
+	//used with an external signer instead of Key
+	PublicKey []byte     `json:"publicKey,omitempty" msgpack:"publicKey,omitempty"`
+	Sign      WizardSign `json:"-" msgpack:"-"`
 }
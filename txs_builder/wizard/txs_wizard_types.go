@@ -5,6 +5,9 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_data"
 )
 
+// WizardSign signs the hash with a private key that is not given to the wizard, like the keys kept by an external signer
+type WizardSign func(message []byte) ([]byte, error)
+
 type WizardTransactionFee struct {
 	Fixed             uint64 `json:"fixed,omitempty" msgpack:"fixed,omitempty"`
 	PerByte           uint64 `json:"perByte,omitempty" msgpack:"perByte,omitempty"`
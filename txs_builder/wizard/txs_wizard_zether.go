@@ -236,6 +236,7 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 	payloads := make([]*transaction_zether_payload.TransactionZetherPayload, len(transfers))
 	privateKeysForSign := make([]*addresses.PrivateKey, len(transfers))
+	externalSigns := make([]WizardSign, len(transfers))
 
 	spaceExtra := 0
 
@@ -281,11 +282,22 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_SPEND
 
-				if privateKeysForSign[t], err = addresses.NewPrivateKey(transfer.SenderSpendPrivateKey); err != nil {
-					return
+				var senderSpendPublicKey *bn256.G1
+				if transfer.SenderSpendSign != nil {
+					senderSpendPublicKey = new(bn256.G1)
+					if err = senderSpendPublicKey.DecodeCompressed(transfer.SenderSpendPublicKey); err != nil {
+						return
+					}
+					externalSigns[t] = transfer.SenderSpendSign
+				} else {
+					if privateKeysForSign[t], err = addresses.NewPrivateKey(transfer.SenderSpendPrivateKey); err != nil {
+						return
+					}
+					senderSpendPublicKey = privateKeysForSign[t].GeneratePublicKeyPoint()
 				}
+
 				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend{nil,
-					privateKeysForSign[t].GeneratePublicKeyPoint(),
+					senderSpendPublicKey,
 					nil,
 				}
 
@@ -304,6 +316,52 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 				spaceExtra += 1 + len(payloadExtra.ReceiverPublicKey) + 66
 
+			case *WizardZetherPayloadExtraAssetSupplyDecrease:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetSupplyPrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease{nil,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetPause:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_PAUSE
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetUpdatePrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause{nil,
+					payloadExtra.AssetId,
+					payloadExtra.Paused,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetFreeze:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_FREEZE
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetUpdatePrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze{nil,
+					payloadExtra.AssetId,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
+			case *WizardZetherPayloadExtraAssetUpdateKeys:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_ASSET_UPDATE_KEYS
+				if privateKeysForSign[t], err = addresses.NewPrivateKey(payloadExtra.AssetUpdatePrivateKey); err != nil {
+					return
+				}
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdateKeys{nil,
+					payloadExtra.AssetId,
+					payloadExtra.NewUpdatePublicKey,
+					payloadExtra.NewSupplyPublicKey,
+					privateKeysForSign[t].GeneratePublicKey(),
+					helpers.EmptyBytes(cryptography.SignatureSize),
+				}
+
 			case *WizardZetherPayloadExtraPlainAccountFund:
 				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND
 				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraPlainAccountFund{
@@ -636,10 +694,14 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 	}
 
 	for t := range transfers {
-		if privateKeysForSign[t] != nil {
+		if privateKeysForSign[t] != nil || externalSigns[t] != nil {
 
 			var signature []byte
-			if signature, err = privateKeysForSign[t].Sign(tx.SerializeForSigning()); err != nil {
+			if externalSigns[t] != nil {
+				if signature, err = externalSigns[t](tx.SerializeForSigning()); err != nil {
+					return
+				}
+			} else if signature, err = privateKeysForSign[t].Sign(tx.SerializeForSigning()); err != nil {
 				return
 			}
 
@@ -648,6 +710,14 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyIncrease).AssetSignature = signature
 			case transaction_zether_payload_script.SCRIPT_SPEND:
 				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend).SenderSpendSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_DECREASE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetSupplyDecrease).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_PAUSE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetPause).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_FREEZE:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetFreeze).AssetSignature = signature
+			case transaction_zether_payload_script.SCRIPT_ASSET_UPDATE_KEYS:
+				txBase.Payloads[t].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetUpdateKeys).AssetSignature = signature
 			}
 
 		}
@@ -661,7 +731,11 @@ func CreateZetherTx(transfers []*WizardZetherTransfer, emap map[string]map[strin
 
 	for i, transfer := range transfers {
 		if transfer.SenderSpendRequired {
-			if len(transfer.SenderSpendPrivateKey) != cryptography.PrivateKeySize {
+			if transfer.SenderSpendSign != nil {
+				if len(transfer.SenderSpendPublicKey) != cryptography.PublicKeySize {
+					return nil, fmt.Errorf("SpendPublicKey is invalid for payload %d", i)
+				}
+			} else if len(transfer.SenderSpendPrivateKey) != cryptography.PrivateKeySize {
 				return nil, fmt.Errorf("SpendPrivateKey is invalid for payload %d", i)
 			}
 			if transfer.PayloadExtra != nil {
@@ -30,6 +30,32 @@ type WizardZetherPayloadExtraAssetSupplyIncrease struct {
 	AssetSupplyPrivateKey    []byte `json:"assetSupplyPublicKey" msgpack:"assetSupplyPublicKey"`
 }
 
+type WizardZetherPayloadExtraAssetSupplyDecrease struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetSupplyPrivateKey    []byte `json:"assetSupplyPrivateKey" msgpack:"assetSupplyPrivateKey"`
+}
+
+type WizardZetherPayloadExtraAssetPause struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	Paused                   bool   `json:"paused" msgpack:"paused"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+}
+
+type WizardZetherPayloadExtraAssetFreeze struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+}
+
+type WizardZetherPayloadExtraAssetUpdateKeys struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+	AssetId                  []byte `json:"assetId" msgpack:"assetId"`
+	NewUpdatePublicKey       []byte `json:"newUpdatePublicKey" msgpack:"newUpdatePublicKey"`
+	NewSupplyPublicKey       []byte `json:"newSupplyPublicKey" msgpack:"newSupplyPublicKey"`
+	AssetUpdatePrivateKey    []byte `json:"assetUpdatePrivateKey" msgpack:"assetUpdatePrivateKey"`
+}
+
 type WizardZetherPayloadExtraPlainAccountFund struct {
 	WizardZetherPayloadExtra `json:"-" msgpack:""`
 	PlainAccountPublicKey    []byte `json:"plainAccountPublicKey" msgpack:"plainAccountPublicKey"`
@@ -52,6 +78,8 @@ type WizardZetherTransfer struct {
 	SenderDecryptedBalance uint64                   `json:"senderDecryptedBalance" msgpack:"senderDecryptedBalance"`
 	SenderSpendRequired    bool                     `json:"senderSpendRequired" msgpack:"senderSpendRequired"`
 	SenderSpendPrivateKey  []byte                   `json:"senderSpendPrivateKey" msgpack:"senderSpendPrivateKey"`
+	SenderSpendPublicKey   []byte                   `json:"senderSpendPublicKey,omitempty" msgpack:"senderSpendPublicKey,omitempty"` //used with SenderSpendSign instead of SenderSpendPrivateKey
+	SenderSpendSign        WizardSign               `json:"-" msgpack:"-"`
 	Recipient              string                   `json:"recipient" msgpack:"recipient"`
 	Amount                 uint64                   `json:"amount" msgpack:"amount"`
 	Burn                   uint64                   `json:"burn" msgpack:"burn"`
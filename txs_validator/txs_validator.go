@@ -141,5 +141,7 @@ func NewTxsValidator() error {
 
 	go TxsValidator.runRemoveExpiredTransactions()
 
+	TxsValidator.initMetrics()
+
 	return nil
 }
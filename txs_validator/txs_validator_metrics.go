@@ -0,0 +1,19 @@
+package txs_validator
+
+import (
+	"pandora-pay/helpers/metrics"
+	"sync/atomic"
+)
+
+func (validator *TxsValidatorType) initMetrics() {
+	metrics.RegisterGauge("pandora_txs_validator_queue", "Transactions waiting to be validated", func() float64 {
+		count := 0
+		validator.all.Range(func(key string, work *txValidatedWork) bool {
+			if atomic.LoadInt32(&work.status) == TX_VALIDATED_INIT {
+				count++
+			}
+			return true
+		})
+		return float64(count)
+	})
+}
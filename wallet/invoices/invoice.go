@@ -0,0 +1,68 @@
+package invoices
+
+import (
+	"pandora-pay/helpers"
+	"time"
+)
+
+type InvoiceStatus byte
+
+const (
+	INVOICE_PENDING InvoiceStatus = iota
+	INVOICE_PARTIALLY_PAID
+	INVOICE_PAID
+	INVOICE_EXPIRED
+)
+
+func (s InvoiceStatus) String() string {
+	switch s {
+	case INVOICE_PENDING:
+		return "pending"
+	case INVOICE_PARTIALLY_PAID:
+		return "partially paid"
+	case INVOICE_PAID:
+		return "paid"
+	case INVOICE_EXPIRED:
+		return "expired"
+	default:
+		return "Unknown Invoice Status"
+	}
+}
+
+type InvoicePayment struct {
+	TxHash      helpers.Base64 `json:"txHash" msgpack:"txHash"`
+	BlockHeight uint64         `json:"blockHeight" msgpack:"blockHeight"`
+	Amount      uint64         `json:"amount" msgpack:"amount"`
+}
+
+type Invoice struct {
+	Id          helpers.Base64    `json:"id" msgpack:"id"`
+	PublicKey   helpers.Base64    `json:"publicKey" msgpack:"publicKey"`
+	PaymentID   helpers.Base64    `json:"paymentID" msgpack:"paymentID"`
+	Asset       helpers.Base64    `json:"asset" msgpack:"asset"`
+	Amount      uint64            `json:"amount" msgpack:"amount"` //zero accepts any amount
+	Description string            `json:"description,omitempty" msgpack:"description,omitempty"`
+	Address     string            `json:"address" msgpack:"address"` //integrated address which is given to the payer
+	Timestamp   time.Time         `json:"timestamp" msgpack:"timestamp"`
+	Expires     time.Time         `json:"expires,omitempty" msgpack:"expires,omitempty"` //zero never expires
+	Status      InvoiceStatus     `json:"status" msgpack:"status"`
+	Received    uint64            `json:"received" msgpack:"received"`
+	Payments    []*InvoicePayment `json:"payments" msgpack:"payments"`
+}
+
+func (invoice *Invoice) GetId() []byte {
+	return invoice.Id
+}
+
+func (invoice *Invoice) computeStatus(now time.Time) InvoiceStatus {
+	if len(invoice.Payments) > 0 && invoice.Received >= invoice.Amount {
+		return INVOICE_PAID
+	}
+	if !invoice.Expires.IsZero() && now.After(invoice.Expires) {
+		return INVOICE_EXPIRED
+	}
+	if invoice.Received > 0 {
+		return INVOICE_PARTIALLY_PAID
+	}
+	return INVOICE_PENDING
+}
@@ -0,0 +1,131 @@
+package invoices
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_wallet"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/linked_list"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_records"
+	"time"
+)
+
+type InvoicesType struct {
+	*wallet_records.WalletRecords[*Invoice]
+	wallet         *wallet.Wallet
+	chain          *blockchain.Blockchain
+	updates        *linked_list.LinkedList[*blockchain_types.BlockchainUpdates] //chain updates which were not processed yet
+	updateReadyCn  chan struct{}
+	UpdateInvoices *multicast.MulticastChannel[*Invoice] //copy of the invoice which received a payment or changed the status
+}
+
+var Invoices *InvoicesType
+
+func copyInvoice(invoice *Invoice) *Invoice {
+	payments := make([]*InvoicePayment, len(invoice.Payments))
+	for i, payment := range invoice.Payments {
+		payments[i] = &InvoicePayment{payment.TxHash, payment.BlockHeight, payment.Amount}
+	}
+	return &Invoice{invoice.Id, invoice.PublicKey, invoice.PaymentID, invoice.Asset, invoice.Amount, invoice.Description, invoice.Address, invoice.Timestamp, invoice.Expires, invoice.Status, invoice.Received, payments}
+}
+
+// is locked before
+func (this *InvoicesType) paymentIDExists(publicKey, paymentID []byte) bool {
+	for _, invoice := range this.List {
+		if bytes.Equal(invoice.PublicKey, publicKey) && bytes.Equal(invoice.PaymentID, paymentID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Create generates an integrated address with a new PaymentID of the address. Zero amount accepts any amount and zero expiration uses the default one
+func (this *InvoicesType) Create(address string, asset []byte, amount uint64, description string, expiration time.Duration) (*Invoice, error) {
+
+	if len(asset) == 0 {
+		asset = config_coins.NATIVE_ASSET_FULL
+	}
+	if len(asset) != config_coins.ASSET_LENGTH {
+		return nil, errors.New("Invalid asset")
+	}
+	if expiration < 0 {
+		return nil, errors.New("Invalid expiration")
+	}
+	if expiration == 0 {
+		expiration = config_wallet.INVOICE_EXPIRATION
+	}
+
+	walletAddr, err := this.wallet.GetWalletAddressByEncodedAddress(address, true)
+	if err != nil {
+		return nil, err
+	}
+	if walletAddr.GetViewKey() == nil {
+		return nil, errors.New("The view key is required to detect the payments")
+	}
+
+	this.Lock.Lock()
+	paymentID := helpers.RandomBytes(8)
+	for this.paymentIDExists(walletAddr.PublicKey, paymentID) {
+		paymentID = helpers.RandomBytes(8)
+	}
+	this.Lock.Unlock()
+
+	//the native asset is implicit in the integrated address
+	var paymentAsset []byte
+	if !bytes.Equal(asset, config_coins.NATIVE_ASSET_FULL) {
+		paymentAsset = asset
+	}
+
+	addr, err := this.wallet.GenerateIntegratedAddress(walletAddr, paymentID, amount, paymentAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invoice := &Invoice{helpers.RandomBytes(config_wallet.INVOICE_ID_LENGTH), walletAddr.PublicKey, paymentID, asset, amount, description, addr.EncodeAddr(), now, now.Add(expiration), INVOICE_PENDING, 0, []*InvoicePayment{}}
+
+	return this.Insert(invoice, func() error {
+		if this.paymentIDExists(walletAddr.PublicKey, paymentID) {
+			return errors.New("PaymentID was used meanwhile")
+		}
+		return nil
+	})
+}
+
+// Initialize loads the invoices saved in the wallet store and starts detecting their payments
+func Initialize(wallet *wallet.Wallet, chain *blockchain.Blockchain) error {
+
+	Invoices.wallet = wallet
+	Invoices.chain = chain
+	//the updates queued while the wallet was locked are processed
+	Invoices.OnReady = Invoices.updateReady
+
+	if err := Invoices.WalletRecords.Initialize(wallet); err != nil {
+		return err
+	}
+
+	Invoices.initCLI()
+
+	recovery.SafeGo(Invoices.processUpdates)
+	recovery.SafeGo(Invoices.processInvoices)
+
+	return nil
+}
+
+// the subscriptions listen to the invoices updates before the invoices are initialized
+func init() {
+	Invoices = &InvoicesType{
+		wallet_records.NewWalletRecords[*Invoice]("invoices", "Invoice", config_wallet.INVOICES_MAX, copyInvoice),
+		nil,
+		nil,
+		linked_list.NewLinkedList[*blockchain_types.BlockchainUpdates](),
+		make(chan struct{}, 1),
+		multicast.NewMulticastChannel[*Invoice](),
+	}
+}
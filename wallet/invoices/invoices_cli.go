@@ -0,0 +1,61 @@
+package invoices
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"pandora-pay/gui"
+	"pandora-pay/wallet/wallet_records"
+	"time"
+)
+
+func (this *InvoicesType) cliListInvoices(cmd string, ctx context.Context) error {
+
+	list := this.GetList()
+
+	gui.GUI.OutputWrite(fmt.Sprintf("Invoices: %d", len(list)))
+	for i, invoice := range list {
+		gui.GUI.OutputWrite(fmt.Sprintf("%d) %s", i, base64.StdEncoding.EncodeToString(invoice.Id)))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Address", invoice.Address))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: %d %s", "Amount", invoice.Amount, base64.StdEncoding.EncodeToString(invoice.Asset)))
+		if invoice.Description != "" {
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Description", invoice.Description))
+		}
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s. Received %d in %d payments", "Status", invoice.Status, invoice.Received, len(invoice.Payments)))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Expires", invoice.Expires.Format(time.RFC3339)))
+	}
+
+	return nil
+}
+
+func (this *InvoicesType) cliCreateInvoice(cmd string, ctx context.Context) (err error) {
+
+	_, address, _, err := this.wallet.CliSelectAddress("Select Address to receive the payment", ctx)
+	if err != nil {
+		return
+	}
+
+	assetId, amount, err := wallet_records.CliReadAssetAmount("Amount. Leave empty for any amount", true)
+	if err != nil {
+		return
+	}
+
+	description := gui.GUI.OutputReadString("Description. Leave empty for none")
+
+	expiration := gui.GUI.OutputReadUint64("Expiration in seconds. Leave empty for the default", true, 0, nil)
+
+	invoice, err := this.Create(address, assetId, amount, description, time.Duration(expiration)*time.Second)
+	if err != nil {
+		return
+	}
+
+	gui.GUI.OutputWrite("Invoice created: " + base64.StdEncoding.EncodeToString(invoice.Id))
+	gui.GUI.OutputWrite("Address: " + invoice.Address)
+	return
+}
+
+func (this *InvoicesType) initCLI() {
+	gui.GUI.CommandDefineCallback("List Invoices", this.cliListInvoices, true)
+	gui.GUI.CommandDefineCallback("Create Invoice", this.cliCreateInvoice, true)
+	gui.GUI.CommandDefineCallback("Remove Invoice", this.CliRemove(this.cliListInvoices), true)
+}
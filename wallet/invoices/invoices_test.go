@@ -0,0 +1,125 @@
+package invoices
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_wallet"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_bunt"
+	"pandora-pay/wallet"
+	"testing"
+	"time"
+)
+
+func newTestInvoice(publicKey []byte, amount uint64, expires time.Time) *Invoice {
+	return &Invoice{helpers.RandomBytes(config_wallet.INVOICE_ID_LENGTH), publicKey, helpers.RandomBytes(8), config_coins.NATIVE_ASSET_FULL, amount, "", "", time.Now(), expires, INVOICE_PENDING, 0, []*InvoicePayment{}}
+}
+
+func TestInvoiceComputeStatus(t *testing.T) {
+
+	now := time.Now()
+
+	invoice := newTestInvoice(nil, 100, now.Add(time.Hour))
+	assert.Equal(t, INVOICE_PENDING, invoice.computeStatus(now))
+
+	invoice.addPayment(&InvoicePayment{[]byte{1}, 10, 40})
+	assert.Equal(t, INVOICE_PARTIALLY_PAID, invoice.computeStatus(now))
+	assert.Equal(t, INVOICE_EXPIRED, invoice.computeStatus(now.Add(2*time.Hour)))
+
+	//the same tx included again in a different block is not counted twice
+	invoice.addPayment(&InvoicePayment{[]byte{1}, 11, 40})
+	assert.Equal(t, uint64(40), invoice.Received)
+	assert.Equal(t, uint64(11), invoice.Payments[0].BlockHeight)
+
+	invoice.addPayment(&InvoicePayment{[]byte{2}, 12, 60})
+	assert.Equal(t, INVOICE_PAID, invoice.computeStatus(now.Add(2*time.Hour)))
+
+	assert.True(t, invoice.removePayments(map[string][]byte{string([]byte{2}): {2}}))
+	assert.Equal(t, uint64(40), invoice.Received)
+	assert.Equal(t, INVOICE_PARTIALLY_PAID, invoice.computeStatus(now))
+
+	//zero amount accepts any amount
+	invoice = newTestInvoice(nil, 0, time.Time{})
+	assert.Equal(t, INVOICE_PENDING, invoice.computeStatus(now))
+	invoice.addPayment(&InvoicePayment{[]byte{3}, 10, 1})
+	assert.Equal(t, INVOICE_PAID, invoice.computeStatus(now))
+}
+
+func TestInvoicesApply(t *testing.T) {
+
+	publicKey := helpers.RandomBytes(33)
+	now := time.Now()
+
+	first := newTestInvoice(publicKey, 100, now.Add(time.Hour))
+	second := newTestInvoice(publicKey, 100, now.Add(time.Hour))
+
+	Invoices.List = []*Invoice{first, second}
+	defer func() {
+		Invoices.List = []*Invoice{}
+	}()
+
+	received := []*invoiceReceived{
+		{publicKey, append(helpers.CloneBytes(first.PaymentID), []byte("extra")...), config_coins.NATIVE_ASSET_FULL, &InvoicePayment{[]byte{1}, 10, 100}},
+		{publicKey, second.PaymentID, helpers.RandomBytes(config_coins.ASSET_LENGTH), &InvoicePayment{[]byte{2}, 10, 100}}, //different asset
+		{helpers.RandomBytes(33), second.PaymentID, config_coins.NATIVE_ASSET_FULL, &InvoicePayment{[]byte{3}, 10, 100}},   //different address
+	}
+
+	changed := Invoices.apply(received, map[string][]byte{}, now)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, first.Id, changed[0].Id)
+	assert.Equal(t, INVOICE_PAID, changed[0].Status)
+	assert.Equal(t, INVOICE_PENDING, second.Status)
+
+	//the payment was removed by a fork
+	changed = Invoices.apply(nil, map[string][]byte{string([]byte{1}): {1}}, now)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, INVOICE_PENDING, changed[0].Status)
+	assert.Equal(t, uint64(0), first.Received)
+
+	changed = Invoices.apply(nil, map[string][]byte{}, now.Add(2*time.Hour))
+	assert.Len(t, changed, 2)
+	assert.Equal(t, INVOICE_EXPIRED, first.Status)
+	assert.Equal(t, INVOICE_EXPIRED, second.Status)
+}
+
+func TestInvoicesProcessQueuedWhileLocked(t *testing.T) {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive()
+
+	db, err := store_db_bunt.CreateStoreDBBunt("/wallet", true)
+	assert.NoError(t, err)
+	store.StoreWallet = &store.Store{Name: "/wallet", Opened: true, DB: db}
+	defer func() {
+		store.StoreWallet = nil
+	}()
+
+	forging, err := forging.CreateForging(nil, nil)
+	assert.NoError(t, err)
+
+	wallet, err := wallet.CreateWallet(forging, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, wallet.Encryption.Encrypt("password", 1))
+	assert.NoError(t, wallet.Encryption.Lock())
+
+	Invoices.wallet = wallet
+	defer func() {
+		Invoices.wallet = nil
+		Invoices.updates.Empty()
+	}()
+
+	Invoices.updates.Push(&blockchain_types.BlockchainUpdates{RemovedTxHashes: map[string][]byte{}})
+	Invoices.updates.Push(&blockchain_types.BlockchainUpdates{RemovedTxHashes: map[string][]byte{}})
+
+	//the updates are kept while the wallet is locked
+	Invoices.processQueued()
+	assert.Len(t, Invoices.updates.GetList(), 2)
+
+	assert.NoError(t, wallet.Encryption.Unlock("password", 0))
+	Invoices.processQueued()
+	assert.Empty(t, Invoices.updates.GetList())
+}
@@ -0,0 +1,216 @@
+package invoices
+
+import (
+	"bytes"
+	"encoding/base64"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"time"
+)
+
+type invoiceReceived struct {
+	publicKey []byte
+	message   []byte
+	asset     []byte
+	payment   *InvoicePayment
+}
+
+// received decrypts the zether payloads sent to the invoices addresses
+func (this *InvoicesType) received(update *blockchain_types.BlockchainUpdates, publicKeys map[string]bool) []*invoiceReceived {
+
+	out := []*invoiceReceived{}
+
+	for _, blkComplete := range update.InsertedBlocks {
+		for _, tx := range blkComplete.Txs {
+
+			if tx.Version != transaction_type.TX_ZETHER {
+				continue
+			}
+
+			txBase := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether)
+
+			decrypted := make(map[string]bool)
+			for t := range txBase.Payloads {
+				for _, publicKey := range txBase.Bloom.PublicKeyLists[t] {
+
+					if !publicKeys[string(publicKey)] || decrypted[string(publicKey)] {
+						continue
+					}
+					decrypted[string(publicKey)] = true
+
+					output, err := this.wallet.DecryptTx(tx, publicKey)
+					if err != nil {
+						gui.GUI.Error("Error decrypting transaction for invoices", err)
+						continue
+					}
+
+					for _, payload := range output.ZetherTx.Payloads {
+						if payload == nil || !payload.WhisperRecipientValid || payload.ReceivedAmount == 0 {
+							continue
+						}
+						out = append(out, &invoiceReceived{publicKey, payload.Message, payload.Asset, &InvoicePayment{tx.Bloom.Hash, blkComplete.Height, payload.ReceivedAmount}})
+					}
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// is locked before
+func (invoice *Invoice) removePayments(removedTxHashes map[string][]byte) (changed bool) {
+
+	payments := make([]*InvoicePayment, 0, len(invoice.Payments))
+	for _, payment := range invoice.Payments {
+		if removedTxHashes[string(payment.TxHash)] != nil {
+			invoice.Received -= payment.Amount
+			changed = true
+			continue
+		}
+		payments = append(payments, payment)
+	}
+	invoice.Payments = payments
+
+	return
+}
+
+// is locked before
+func (invoice *Invoice) addPayment(payment *InvoicePayment) {
+
+	for _, it := range invoice.Payments {
+		if bytes.Equal(it.TxHash, payment.TxHash) { //the tx was included again in a different block
+			it.BlockHeight = payment.BlockHeight
+			return
+		}
+	}
+
+	if err := helpers.SafeUint64Add(&invoice.Received, payment.Amount); err != nil {
+		return
+	}
+	invoice.Payments = append(invoice.Payments, payment)
+}
+
+// process returns false while the wallet is locked as the payments can't be decrypted and the invoices can't be saved. The update is processed again once the wallet is unlocked
+func (this *InvoicesType) process(update *blockchain_types.BlockchainUpdates) bool {
+
+	if this.wallet.Encryption.IsLocked() {
+		return false
+	}
+
+	this.Lock.Lock()
+	publicKeys := make(map[string]bool)
+	for _, invoice := range this.List {
+		publicKeys[string(invoice.PublicKey)] = true
+	}
+	this.Lock.Unlock()
+
+	var received []*invoiceReceived
+	if len(publicKeys) > 0 {
+		received = this.received(update, publicKeys)
+	}
+
+	//the wallet was locked meanwhile and some payments couldn't be decrypted
+	if this.wallet.Encryption.IsLocked() {
+		return false
+	}
+
+	changed := this.apply(received, update.RemovedTxHashes, time.Now())
+	if len(changed) == 0 {
+		return true
+	}
+
+	for _, invoice := range changed {
+		gui.GUI.Info("Invoice "+base64.StdEncoding.EncodeToString(invoice.Id), invoice.Status.String(), invoice.Received)
+		this.UpdateInvoices.Broadcast(invoice)
+	}
+
+	if err := this.Save(); err != nil {
+		gui.GUI.Error("Error saving invoices", err)
+	}
+
+	return true
+}
+
+// apply matches the received payments to the invoices and returns copies of the changed invoices
+func (this *InvoicesType) apply(received []*invoiceReceived, removedTxHashes map[string][]byte, now time.Time) []*Invoice {
+
+	changed := []*Invoice{}
+
+	this.Lock.Lock()
+	for _, invoice := range this.List {
+
+		paymentsChanged := invoice.removePayments(removedTxHashes)
+
+		for _, it := range received {
+			if bytes.Equal(it.publicKey, invoice.PublicKey) && bytes.Equal(it.asset, invoice.Asset) && bytes.HasPrefix(it.message, invoice.PaymentID) {
+				invoice.addPayment(it.payment)
+				paymentsChanged = true
+			}
+		}
+
+		status := invoice.computeStatus(now)
+		if paymentsChanged || status != invoice.Status {
+			invoice.Status = status
+			changed = append(changed, copyInvoice(invoice))
+		}
+	}
+	this.Lock.Unlock()
+
+	return changed
+}
+
+func (this *InvoicesType) updateReady() {
+	select {
+	case this.updateReadyCn <- struct{}{}:
+	default:
+	}
+}
+
+// processUpdates queues every chain update as the payments of the intermediary blocks can't be skipped.
+// The txs are decrypted by processInvoices to not block the other listeners
+func (this *InvoicesType) processUpdates() {
+
+	updateNewChainUpdateCn := this.chain.UpdateNewChainUpdate.AddListener()
+	defer this.chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainUpdateCn)
+
+	for {
+		update, ok := <-updateNewChainUpdateCn
+		if !ok {
+			return
+		}
+
+		this.Lock.Lock()
+		this.updates.Push(update)
+		this.Lock.Unlock()
+
+		this.updateReady()
+	}
+}
+
+// processQueued processes the queued updates in order. It stops at the first update which can't be processed while the wallet is locked and keeps it queued
+func (this *InvoicesType) processQueued() {
+	for {
+		this.Lock.Lock()
+		update, ok := this.updates.GetHead()
+		this.Lock.Unlock()
+
+		if !ok || !this.process(update) {
+			return
+		}
+
+		this.Lock.Lock()
+		this.updates.PopHead()
+		this.Lock.Unlock()
+	}
+}
+
+func (this *InvoicesType) processInvoices() {
+	for {
+		<-this.updateReadyCn
+		this.processQueued()
+	}
+}
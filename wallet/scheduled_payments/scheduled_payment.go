@@ -0,0 +1,62 @@
+package scheduled_payments
+
+import (
+	"pandora-pay/helpers"
+	"pandora-pay/txs_builder"
+	"time"
+)
+
+type ScheduledPaymentIntervalType byte
+
+const (
+	SCHEDULED_PAYMENT_INTERVAL_BLOCKS ScheduledPaymentIntervalType = iota
+	SCHEDULED_PAYMENT_INTERVAL_SECONDS
+)
+
+func (t ScheduledPaymentIntervalType) String() string {
+	switch t {
+	case SCHEDULED_PAYMENT_INTERVAL_BLOCKS:
+		return "blocks"
+	case SCHEDULED_PAYMENT_INTERVAL_SECONDS:
+		return "seconds"
+	default:
+		return "Unknown Interval Type"
+	}
+}
+
+type ScheduledPaymentStatus struct {
+	Payments      uint64         `json:"payments" msgpack:"payments"` //confirmed payments
+	Failed        uint64         `json:"failed" msgpack:"failed"`     //payments skipped after all the attempts
+	Attempts      uint32         `json:"attempts" msgpack:"attempts"` //failed attempts of the current payment
+	PendingTx     helpers.Base64 `json:"pendingTx,omitempty" msgpack:"pendingTx,omitempty"`
+	PendingHeight uint64         `json:"pendingHeight,omitempty" msgpack:"pendingHeight,omitempty"` //chain height when the pending tx was created
+	LastTx        helpers.Base64 `json:"lastTx,omitempty" msgpack:"lastTx,omitempty"`
+	LastPaid      time.Time      `json:"lastPaid,omitempty" msgpack:"lastPaid,omitempty"`
+	LastError     string         `json:"lastError,omitempty" msgpack:"lastError,omitempty"`
+	LastErrorTime time.Time      `json:"lastErrorTime,omitempty" msgpack:"lastErrorTime,omitempty"`
+}
+
+type ScheduledPayment struct {
+	Id                helpers.Base64                       `json:"id" msgpack:"id"`
+	Sender            string                               `json:"sender" msgpack:"sender"`
+	Recipient         string                               `json:"recipient" msgpack:"recipient"`
+	Asset             helpers.Base64                       `json:"asset" msgpack:"asset"`
+	Amount            uint64                               `json:"amount" msgpack:"amount"`
+	IntervalType      ScheduledPaymentIntervalType         `json:"intervalType" msgpack:"intervalType"`
+	Interval          uint64                               `json:"interval" msgpack:"interval"` //blocks or seconds
+	Next              uint64                               `json:"next" msgpack:"next"`         //block height or unix timestamp of the next payment
+	RingSize          int                                  `json:"ringSize" msgpack:"ringSize"`
+	RingConfiguration *txs_builder.ZetherRingConfiguration `json:"ringConfiguration" msgpack:"ringConfiguration"`
+	Timestamp         time.Time                            `json:"timestamp" msgpack:"timestamp"`
+	Status            *ScheduledPaymentStatus              `json:"status" msgpack:"status"`
+}
+
+func (payment *ScheduledPayment) GetId() []byte {
+	return payment.Id
+}
+
+// ScheduledPaymentAlert is notified to the subscribers of the scheduled payment when a payment is skipped after all the attempts
+type ScheduledPaymentAlert struct {
+	Id    helpers.Base64 `json:"id" msgpack:"id"`
+	Error string         `json:"error" msgpack:"error"`
+}
@@ -0,0 +1,127 @@
+package scheduled_payments
+
+import (
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_wallet"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/mempool"
+	"pandora-pay/txs_builder"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_records"
+	"time"
+)
+
+type ScheduledPaymentsType struct {
+	*wallet_records.WalletRecords[*ScheduledPayment]
+	wallet                  *wallet.Wallet
+	mempool                 *mempool.Mempool
+	chain                   *blockchain.Blockchain
+	update                  *blockchain_types.BlockchainUpdates //latest chain update which was not processed yet
+	updateReadyCn           chan struct{}
+	UpdateScheduledPayments *multicast.MulticastChannel[*ScheduledPaymentAlert] //payments skipped after all the attempts
+}
+
+var ScheduledPayments *ScheduledPaymentsType
+
+func copyScheduledPayment(payment *ScheduledPayment) *ScheduledPayment {
+	status := *payment.Status
+	return &ScheduledPayment{payment.Id, payment.Sender, payment.Recipient, payment.Asset, payment.Amount, payment.IntervalType, payment.Interval, payment.Next, payment.RingSize, payment.RingConfiguration, payment.Timestamp, &status}
+}
+
+func (this *ScheduledPaymentsType) validate(payment *ScheduledPayment) error {
+
+	addr, err := this.wallet.GetWalletAddressByEncodedAddress(payment.Sender, true)
+	if err != nil {
+		return err
+	}
+	if addr.PrivateKey == nil {
+		return errors.New("Sender private key is required to sign the payments")
+	}
+
+	if _, err = addresses.DecodeAddr(payment.Recipient); err != nil {
+		return errors.New("Invalid recipient")
+	}
+
+	if len(payment.Asset) != config_coins.ASSET_LENGTH {
+		return errors.New("Invalid asset")
+	}
+	if payment.Amount == 0 {
+		return errors.New("Amount must be positive")
+	}
+	if payment.IntervalType != SCHEDULED_PAYMENT_INTERVAL_BLOCKS && payment.IntervalType != SCHEDULED_PAYMENT_INTERVAL_SECONDS {
+		return errors.New("Invalid interval type")
+	}
+	if payment.Interval == 0 {
+		return errors.New("Interval must be positive")
+	}
+
+	switch payment.RingSize {
+	case -1, 2, 4, 8, 16, 32, 64, 128, 256:
+	default:
+		return errors.New("Invalid ring size")
+	}
+	if payment.RingConfiguration.SenderRingType == nil || payment.RingConfiguration.RecipientRingType == nil {
+		return errors.New("Invalid ring configuration")
+	}
+
+	return nil
+}
+
+// Add schedules a new payment. The first payment is made at Next or with the next block if Next is zero
+func (this *ScheduledPaymentsType) Add(sender, recipient string, asset []byte, amount uint64, intervalType ScheduledPaymentIntervalType, interval, next uint64, ringSize int, ringConfiguration *txs_builder.ZetherRingConfiguration) (*ScheduledPayment, error) {
+
+	if len(asset) == 0 {
+		asset = config_coins.NATIVE_ASSET_FULL
+	}
+	if ringConfiguration == nil {
+		ringConfiguration = &txs_builder.ZetherRingConfiguration{
+			SenderRingType:    &txs_builder.ZetherSenderRingType{IncludeMembers: []string{}},
+			RecipientRingType: &txs_builder.ZetherRecipientRingType{IncludeMembers: []string{}, NewAccounts: -1},
+		}
+	}
+
+	payment := &ScheduledPayment{helpers.RandomBytes(config_wallet.SCHEDULED_PAYMENT_ID_LENGTH), sender, recipient, asset, amount, intervalType, interval, next, ringSize, ringConfiguration, time.Now(), &ScheduledPaymentStatus{}}
+	if err := this.validate(payment); err != nil {
+		return nil, err
+	}
+
+	return this.Insert(payment, nil)
+}
+
+// Initialize loads the scheduled payments saved in the wallet store and starts paying them
+func Initialize(wallet *wallet.Wallet, mempool *mempool.Mempool, chain *blockchain.Blockchain) error {
+
+	ScheduledPayments.wallet = wallet
+	ScheduledPayments.mempool = mempool
+	ScheduledPayments.chain = chain
+
+	if err := ScheduledPayments.WalletRecords.Initialize(wallet); err != nil {
+		return err
+	}
+
+	ScheduledPayments.initCLI()
+
+	recovery.SafeGo(ScheduledPayments.processUpdates)
+	recovery.SafeGo(ScheduledPayments.processPayments)
+
+	return nil
+}
+
+// the subscriptions listen to the failed payments before the scheduled payments are initialized
+func init() {
+	ScheduledPayments = &ScheduledPaymentsType{
+		wallet_records.NewWalletRecords[*ScheduledPayment]("scheduledPayments", "Scheduled Payment", config_wallet.SCHEDULED_PAYMENTS_MAX, copyScheduledPayment),
+		nil,
+		nil,
+		nil,
+		nil,
+		make(chan struct{}, 1),
+		multicast.NewMulticastChannel[*ScheduledPaymentAlert](),
+	}
+}
@@ -0,0 +1,97 @@
+package scheduled_payments
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"pandora-pay/addresses"
+	"pandora-pay/gui"
+	"pandora-pay/txs_builder"
+	"pandora-pay/wallet/wallet_records"
+)
+
+func (this *ScheduledPaymentsType) cliListScheduledPayments(cmd string, ctx context.Context) error {
+
+	list := this.GetList()
+
+	gui.GUI.OutputWrite(fmt.Sprintf("Scheduled Payments: %d", len(list)))
+	for i, payment := range list {
+		gui.GUI.OutputWrite(fmt.Sprintf("%d) %s", i, base64.StdEncoding.EncodeToString(payment.Id)))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Sender", payment.Sender))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Recipient", payment.Recipient))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: %d %s", "Amount", payment.Amount, base64.StdEncoding.EncodeToString(payment.Asset)))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: every %d %s. Next at %d", "Interval", payment.Interval, payment.IntervalType, payment.Next))
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s: paid %d failed %d attempts %d", "Status", payment.Status.Payments, payment.Status.Failed, payment.Status.Attempts))
+		if payment.Status.PendingTx != nil {
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Pending Tx", base64.StdEncoding.EncodeToString(payment.Status.PendingTx)))
+		}
+		if payment.Status.LastError != "" {
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Last Error", payment.Status.LastError))
+		}
+	}
+
+	return nil
+}
+
+func (this *ScheduledPaymentsType) cliAddScheduledPayment(cmd string, ctx context.Context) (err error) {
+
+	_, sender, _, err := this.wallet.CliSelectAddress("Select Address to Pay from", ctx)
+	if err != nil {
+		return
+	}
+
+	assetId, amount, err := wallet_records.CliReadAssetAmount("Amount", false)
+	if err != nil {
+		return
+	}
+
+	var recipient string
+	for {
+		recipient = gui.GUI.OutputReadString("Recipient Address")
+		if _, err = addresses.DecodeAddr(recipient); err == nil {
+			break
+		}
+		gui.GUI.OutputWrite("Invalid Address")
+	}
+
+	intervalType := SCHEDULED_PAYMENT_INTERVAL_BLOCKS
+	if gui.GUI.OutputReadBool("Interval in seconds? y/n. Leave empty for blocks", true, false) {
+		intervalType = SCHEDULED_PAYMENT_INTERVAL_SECONDS
+	}
+
+	interval := gui.GUI.OutputReadUint64("Interval ("+intervalType.String()+")", false, 0, func(value uint64) bool {
+		return value > 0
+	})
+	next := gui.GUI.OutputReadUint64("First payment ("+intervalType.String()+" height or unix timestamp). Leave empty for the next block", true, 0, nil)
+
+	ringSize := gui.GUI.OutputReadInt("Ring Size (2,4,8,16,32,64,128,256). Leave empty for random", true, -1, func(value int) bool {
+		switch value {
+		case 2, 4, 8, 16, 32, 64, 128, 256:
+			return true
+		default:
+			return false
+		}
+	})
+
+	ringConfiguration := &txs_builder.ZetherRingConfiguration{
+		SenderRingType:    &txs_builder.ZetherSenderRingType{IncludeMembers: []string{}},
+		RecipientRingType: &txs_builder.ZetherRecipientRingType{IncludeMembers: []string{}, NewAccounts: -1},
+	}
+	ringConfiguration.SenderRingType.NewAccounts = gui.GUI.OutputReadInt("Ring New Accounts (0...n-2). Leave empty for none", true, 0, func(value int) bool {
+		return value >= 0
+	})
+
+	payment, err := this.Add(sender, recipient, assetId, amount, intervalType, interval, next, ringSize, ringConfiguration)
+	if err != nil {
+		return
+	}
+
+	gui.GUI.OutputWrite("Scheduled Payment added: " + base64.StdEncoding.EncodeToString(payment.Id))
+	return
+}
+
+func (this *ScheduledPaymentsType) initCLI() {
+	gui.GUI.CommandDefineCallback("List Scheduled Payments", this.cliListScheduledPayments, true)
+	gui.GUI.CommandDefineCallback("Add Scheduled Payment", this.cliAddScheduledPayment, true)
+	gui.GUI.CommandDefineCallback("Remove Scheduled Payment", this.CliRemove(this.cliListScheduledPayments), true)
+}
@@ -0,0 +1,87 @@
+package scheduled_payments
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config/config_wallet"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"testing"
+	"time"
+)
+
+func newTestScheduledPayment(intervalType ScheduledPaymentIntervalType, interval, next uint64) *ScheduledPayment {
+	return &ScheduledPayment{helpers.RandomBytes(config_wallet.SCHEDULED_PAYMENT_ID_LENGTH), "", "", nil, 1, intervalType, interval, next, -1, nil, time.Now(), &ScheduledPaymentStatus{}}
+}
+
+func TestScheduledPaymentScheduleNext(t *testing.T) {
+
+	payment := newTestScheduledPayment(SCHEDULED_PAYMENT_INTERVAL_BLOCKS, 10, 100)
+	assert.False(t, payment.isDue(99, 0))
+	assert.True(t, payment.isDue(100, 0))
+
+	payment.scheduleNext(100, 0)
+	assert.Equal(t, uint64(110), payment.Next)
+
+	//the payments missed while the wallet was offline are skipped
+	payment.scheduleNext(150, 0)
+	assert.Equal(t, uint64(160), payment.Next)
+
+	payment = newTestScheduledPayment(SCHEDULED_PAYMENT_INTERVAL_SECONDS, 60, 1000)
+	assert.False(t, payment.isDue(5000, 999))
+	assert.True(t, payment.isDue(0, 1000))
+	payment.scheduleNext(0, 1000)
+	assert.Equal(t, uint64(1060), payment.Next)
+}
+
+func TestScheduledPaymentUpdatePending(t *testing.T) {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive()
+
+	alertsCn := ScheduledPayments.UpdateScheduledPayments.AddListener()
+	defer ScheduledPayments.UpdateScheduledPayments.RemoveChannel(alertsCn)
+
+	payment := newTestScheduledPayment(SCHEDULED_PAYMENT_INTERVAL_BLOCKS, 10, 100)
+	payment.Status.PendingTx = helpers.RandomBytes(32)
+	payment.Status.PendingHeight = 100
+
+	//included but not confirmed yet
+	ScheduledPayments.updatePending(payment, 101, 0, true, 101, false)
+	assert.NotNil(t, payment.Status.PendingTx)
+
+	ScheduledPayments.updatePending(payment, 101+config_wallet.SCHEDULED_PAYMENTS_CONFIRMATIONS, 0, true, 101, false)
+	assert.Nil(t, payment.Status.PendingTx)
+	assert.Equal(t, uint64(1), payment.Status.Payments)
+	assert.Equal(t, uint64(110), payment.Next)
+
+	//waiting in the mempool
+	payment.Status.PendingTx = helpers.RandomBytes(32)
+	payment.Status.PendingHeight = 110
+	ScheduledPayments.updatePending(payment, 110+config_wallet.SCHEDULED_PAYMENTS_PENDING_MAX-1, 0, false, 0, true)
+	assert.NotNil(t, payment.Status.PendingTx)
+
+	//stuck in the mempool for too long
+	ScheduledPayments.updatePending(payment, 110+config_wallet.SCHEDULED_PAYMENTS_PENDING_MAX, 0, false, 0, true)
+	assert.Nil(t, payment.Status.PendingTx)
+	assert.Equal(t, uint32(1), payment.Status.Attempts)
+	assert.Equal(t, uint64(110), payment.Next, "the payment is created again")
+
+	//dropped from the mempool until the payment is skipped
+	for i := 1; i < config_wallet.SCHEDULED_PAYMENTS_MAX_ATTEMPTS; i++ {
+		payment.Status.PendingTx = helpers.RandomBytes(32)
+		payment.Status.PendingHeight = 130
+		ScheduledPayments.updatePending(payment, 130, 0, false, 0, false)
+	}
+	assert.Nil(t, payment.Status.PendingTx)
+	assert.Equal(t, uint32(0), payment.Status.Attempts)
+	assert.Equal(t, uint64(1), payment.Status.Failed)
+	assert.Equal(t, uint64(140), payment.Next)
+
+	select {
+	case alert := <-alertsCn:
+		assert.Equal(t, payment.Id, alert.Id)
+		assert.NotEmpty(t, alert.Error)
+	case <-time.After(time.Second):
+		t.Fatal("skipped payment was not notified")
+	}
+}
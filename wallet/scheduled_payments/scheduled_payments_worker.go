@@ -0,0 +1,255 @@
+package scheduled_payments
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/exp/slices"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_wallet"
+	"pandora-pay/gui"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder"
+	"pandora-pay/txs_builder/txs_builder_zether_helper"
+	"pandora-pay/txs_builder/wizard"
+	"strconv"
+	"time"
+)
+
+func (payment *ScheduledPayment) isDue(height, now uint64) bool {
+	if payment.IntervalType == SCHEDULED_PAYMENT_INTERVAL_BLOCKS {
+		return height >= payment.Next
+	}
+	return now >= payment.Next
+}
+
+// scheduleNext skips the payments that were missed while the wallet was offline instead of paying them all at once
+func (payment *ScheduledPayment) scheduleNext(height, now uint64) {
+
+	current := now
+	if payment.IntervalType == SCHEDULED_PAYMENT_INTERVAL_BLOCKS {
+		current = height
+	}
+
+	payment.Next += payment.Interval
+	if payment.Next <= current {
+		payment.Next = current + payment.Interval
+	}
+}
+
+// is locked before
+func (this *ScheduledPaymentsType) paid(payment *ScheduledPayment, height, now uint64) {
+
+	payment.Status.Payments++
+	payment.Status.LastTx = payment.Status.PendingTx
+	payment.Status.LastPaid = time.Now()
+	payment.Status.PendingTx = nil
+	payment.Status.PendingHeight = 0
+	payment.Status.Attempts = 0
+	payment.scheduleNext(height, now)
+
+	gui.GUI.Info("Scheduled payment confirmed", base64.StdEncoding.EncodeToString(payment.Id), base64.StdEncoding.EncodeToString(payment.Status.LastTx))
+}
+
+// is locked before
+func (this *ScheduledPaymentsType) failed(payment *ScheduledPayment, height, now uint64, err error) {
+
+	payment.Status.PendingTx = nil
+	payment.Status.PendingHeight = 0
+	payment.Status.Attempts++
+	payment.Status.LastError = err.Error()
+	payment.Status.LastErrorTime = time.Now()
+
+	gui.GUI.Error("Scheduled payment "+base64.StdEncoding.EncodeToString(payment.Id)+" failed", err)
+
+	if payment.Status.Attempts >= config_wallet.SCHEDULED_PAYMENTS_MAX_ATTEMPTS {
+		payment.Status.Failed++
+		payment.Status.Attempts = 0
+		payment.scheduleNext(height, now)
+		this.UpdateScheduledPayments.Broadcast(&ScheduledPaymentAlert{payment.Id, err.Error()})
+	}
+}
+
+// is locked before
+func (this *ScheduledPaymentsType) updatePending(payment *ScheduledPayment, height, now uint64, included bool, txHeight uint64, inMempool bool) {
+
+	if included {
+		if height >= txHeight+config_wallet.SCHEDULED_PAYMENTS_CONFIRMATIONS {
+			this.paid(payment, height, now)
+		}
+		return
+	}
+
+	//a tx removed by a fork is inserted back in the mempool
+	if !inMempool {
+		this.failed(payment, height, now, errors.New("Transaction was dropped from the mempool"))
+		return
+	}
+
+	//the payment is created again. The stuck tx becomes invalid once the new tx spends the same balance
+	if height >= payment.Status.PendingHeight+config_wallet.SCHEDULED_PAYMENTS_PENDING_MAX {
+		this.failed(payment, height, now, errors.New("Transaction was not included in "+strconv.Itoa(config_wallet.SCHEDULED_PAYMENTS_PENDING_MAX)+" blocks"))
+	}
+}
+
+// is locked before
+func (this *ScheduledPaymentsType) checkPending(payment *ScheduledPayment, height, now uint64) (err error) {
+
+	txHash := string(payment.Status.PendingTx)
+
+	var txHeight uint64
+	var included bool
+
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		if data := reader.Get("txBlock:" + txHash); data != nil {
+			txHeight, _ = binary.Uvarint(data)
+			included = true
+		}
+		return nil
+	}); err != nil {
+		return
+	}
+
+	this.updatePending(payment, height, now, included, txHeight, this.mempool.Txs.Exists(txHash))
+	return
+}
+
+func (this *ScheduledPaymentsType) createTx(payment *ScheduledPayment, ctx context.Context) (*transaction.Transaction, error) {
+
+	//the builder changes the ring configuration
+	senderRingType := *payment.RingConfiguration.SenderRingType
+	senderRingType.IncludeMembers = slices.Clone(senderRingType.IncludeMembers)
+	recipientRingType := *payment.RingConfiguration.RecipientRingType
+	recipientRingType.IncludeMembers = slices.Clone(recipientRingType.IncludeMembers)
+
+	txData := &txs_builder.TxBuilderCreateZetherTxData{
+		Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+			TxsBuilderZetherTxPayloadBase: txs_builder_zether_helper.TxsBuilderZetherTxPayloadBase{
+				Sender:    payment.Sender,
+				Recipient: payment.Recipient,
+				RingSize:  payment.RingSize,
+			},
+			Asset:  payment.Asset,
+			Amount: payment.Amount,
+			RingConfiguration: &txs_builder.ZetherRingConfiguration{
+				SenderRingType:    &senderRingType,
+				RecipientRingType: &recipientRingType,
+			},
+			Fee: &wizard.WizardZetherTransactionFee{
+				WizardTransactionFee: &wizard.WizardTransactionFee{PerByteAuto: true},
+				Auto:                 true,
+			},
+		}},
+	}
+
+	return txs_builder.TxsBuilder.CreateZetherTx(txData, nil, true, true, false, false, ctx, func(string) {})
+}
+
+func (this *ScheduledPaymentsType) process(update *blockchain_types.BlockchainUpdates, ctx context.Context) {
+
+	now := uint64(time.Now().Unix())
+	height := update.BlockHeight
+
+	if len(update.InsertedBlocks) > 0 && update.InsertedBlocks[len(update.InsertedBlocks)-1].Block.Timestamp+uint64(config_wallet.SCHEDULED_PAYMENTS_CHAIN_DELAY/time.Second) < now {
+		return
+	}
+
+	//the payments are created once the wallet is unlocked again
+	if this.wallet.Encryption.IsLocked() {
+		return
+	}
+
+	//a sender has at most one pending payment as a new zether tx would spend the same balance
+	busy := make(map[string]bool)
+	due := []*ScheduledPayment{}
+	changed := false
+
+	this.Lock.Lock()
+	for _, payment := range this.List {
+		if payment.Status.PendingTx != nil {
+			if err := this.checkPending(payment, height, now); err != nil {
+				gui.GUI.Error("Error checking scheduled payment", err)
+			}
+			changed = changed || payment.Status.PendingTx == nil
+		}
+		if payment.Status.PendingTx != nil {
+			busy[payment.Sender] = true
+		}
+	}
+	for _, payment := range this.List {
+		if payment.Status.PendingTx == nil && !busy[payment.Sender] && payment.isDue(height, now) {
+			busy[payment.Sender] = true
+			due = append(due, copyScheduledPayment(payment))
+		}
+	}
+	this.Lock.Unlock()
+
+	for _, it := range due {
+
+		tx, err := this.createTx(it, ctx)
+
+		this.Lock.Lock()
+		if index := this.Find(it.Id); index != -1 { //it may have been removed meanwhile
+			payment := this.List[index]
+			if err != nil {
+				this.failed(payment, height, now, err)
+			} else {
+				payment.Status.PendingTx = tx.Bloom.Hash
+				payment.Status.PendingHeight = height
+				gui.GUI.Info("Scheduled payment created", base64.StdEncoding.EncodeToString(payment.Id), base64.StdEncoding.EncodeToString(tx.Bloom.Hash))
+			}
+			changed = true
+		}
+		this.Lock.Unlock()
+	}
+
+	if changed {
+		if err := this.Save(); err != nil {
+			gui.GUI.Error("Error saving scheduled payments", err)
+		}
+	}
+}
+
+// processUpdates queues only the latest chain update to not block the other listeners while the txs are created
+func (this *ScheduledPaymentsType) processUpdates() {
+
+	updateNewChainUpdateCn := this.chain.UpdateNewChainUpdate.AddListener()
+	defer this.chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainUpdateCn)
+
+	for {
+		update, ok := <-updateNewChainUpdateCn
+		if !ok {
+			return
+		}
+
+		this.Lock.Lock()
+		this.update = update
+		this.Lock.Unlock()
+
+		select {
+		case this.updateReadyCn <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (this *ScheduledPaymentsType) processPayments() {
+
+	ctx := context.Background()
+
+	for {
+		<-this.updateReadyCn
+
+		this.Lock.Lock()
+		update := this.update
+		this.update = nil
+		this.Lock.Unlock()
+
+		if update != nil {
+			this.process(update, ctx)
+		}
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
 	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_address/external_signer"
 	"sync"
+	"time"
 )
 
 type Wallet struct {
@@ -27,8 +29,10 @@ type Wallet struct {
 	mempool                 *mempool.Mempool
 	addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor
 	updateNewChainUpdate    *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]
-	nonHardening            bool         `json:"nonHardening" msgpack:"nonHardening"`
-	Lock                    sync.RWMutex `json:"-" msgpack:"-"`
+	signerDaemon            *external_signer.SignerDaemon
+	autoLockTimeout         time.Duration //inactivity after which the secrets of the encrypted wallet are wiped
+	nonHardening            bool          `json:"nonHardening" msgpack:"nonHardening"`
+	Lock                    sync.RWMutex  `json:"-" msgpack:"-"`
 }
 
 func createWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (wallet *Wallet) {
@@ -91,4 +95,6 @@ func (wallet *Wallet) InitializeWallet(updateNewChainUpdate *multicast.Multicast
 	if config.NODE_CONSENSUS == config.NODE_CONSENSUS_TYPE_FULL {
 		wallet.processRefreshWallets()
 	}
+
+	wallet.processAutoLock()
 }
@@ -0,0 +1,49 @@
+package external_signer
+
+import (
+	"errors"
+)
+
+// Signer holds the private keys outside the wallet. It only returns signatures of the hashes it receives
+type Signer interface {
+	Sign(publicKey, message []byte) ([]byte, error)
+}
+
+type ExternalSignerType byte
+
+const (
+	EXTERNAL_SIGNER_UNIX_SOCKET ExternalSignerType = iota
+)
+
+func (e ExternalSignerType) String() string {
+	switch e {
+	case EXTERNAL_SIGNER_UNIX_SOCKET:
+		return "EXTERNAL_SIGNER_UNIX_SOCKET"
+	default:
+		return "Unknown External Signer Type"
+	}
+}
+
+type ExternalSigner struct {
+	Type ExternalSignerType `json:"type" msgpack:"type"`
+	Path string             `json:"path" msgpack:"path"`
+}
+
+func (signer *ExternalSigner) GetSigner() (Signer, error) {
+	switch signer.Type {
+	case EXTERNAL_SIGNER_UNIX_SOCKET:
+		return &UnixSocketSigner{signer.Path}, nil
+	default:
+		return nil, errors.New("Invalid External Signer Type")
+	}
+}
+
+type ExternalSignerRequest struct {
+	PublicKey []byte `json:"publicKey" msgpack:"publicKey"`
+	Message   []byte `json:"message" msgpack:"message"`
+}
+
+type ExternalSignerAnswer struct {
+	Signature []byte `json:"signature,omitempty" msgpack:"signature,omitempty"`
+	Error     string `json:"error,omitempty" msgpack:"error,omitempty"`
+}
@@ -0,0 +1,148 @@
+package external_signer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/recovery"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// SignerDaemon serves the signatures over a Unix socket to the wallets that keep the addresses watch-only. It signs only for the configured public keys
+type SignerDaemon struct {
+	path          string
+	listener      *net.UnixListener
+	publicKeys    map[string]bool
+	getPrivateKey func(publicKey []byte) (*addresses.PrivateKey, error)
+}
+
+func (daemon *SignerDaemon) sign(request *ExternalSignerRequest) ([]byte, error) {
+
+	if !daemon.publicKeys[string(request.PublicKey)] {
+		return nil, errors.New("Public Key is not allowed by the signer daemon")
+	}
+
+	//only hashes are signed to avoid leaking any parts of the private key
+	if len(request.Message) != cryptography.HashSize {
+		return nil, errors.New("Message must be a hash")
+	}
+
+	privateKey, err := daemon.getPrivateKey(request.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return privateKey.Sign(request.Message)
+}
+
+func (daemon *SignerDaemon) processConnection(conn net.Conn) {
+
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(EXTERNAL_SIGNER_TIMEOUT)); err != nil {
+		return
+	}
+
+	answer := &ExternalSignerAnswer{}
+
+	request := &ExternalSignerRequest{}
+	if err := json.NewDecoder(conn).Decode(request); err != nil {
+		answer.Error = err.Error()
+		gui.GUI.Warning("External Signer received an invalid request: " + answer.Error)
+	} else if answer.Signature, err = daemon.sign(request); err != nil {
+		answer.Error = err.Error()
+		gui.GUI.Warning("External Signer rejected " + base64.StdEncoding.EncodeToString(request.Message) + " for " + base64.StdEncoding.EncodeToString(request.PublicKey) + ": " + answer.Error)
+	} else {
+		gui.GUI.Info("External Signer signed " + base64.StdEncoding.EncodeToString(request.Message) + " for " + base64.StdEncoding.EncodeToString(request.PublicKey))
+	}
+
+	json.NewEncoder(conn).Encode(answer)
+}
+
+func (daemon *SignerDaemon) Close() error {
+	err := daemon.listener.Close()
+	os.Remove(daemon.path)
+	return err
+}
+
+// listen creates the socket in a private directory and moves it to path only after its permissions were restricted to the owner.
+// This way, no other user can connect in between
+func listen(path string) (*net.UnixListener, error) {
+
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".signer-daemon-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err = os.Chmod(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	tmpPath := filepath.Join(dir, "socket")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmpPath, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	listener.SetUnlinkOnClose(false)
+
+	if err = os.Chmod(tmpPath, 0600); err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+func StartSignerDaemon(path string, publicKeys [][]byte, getPrivateKey func(publicKey []byte) (*addresses.PrivateKey, error)) (*SignerDaemon, error) {
+
+	if len(publicKeys) == 0 {
+		return nil, errors.New("Signer daemon requires the public keys it signs for")
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	listener, err := listen(path)
+	if err != nil {
+		return nil, err
+	}
+
+	daemon := &SignerDaemon{
+		path,
+		listener,
+		make(map[string]bool),
+		getPrivateKey,
+	}
+
+	for _, publicKey := range publicKeys {
+		daemon.publicKeys[string(publicKey)] = true
+	}
+
+	recovery.SafeGo(func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			recovery.SafeGo(func() {
+				daemon.processConnection(conn)
+			})
+		}
+	})
+
+	gui.GUI.Info("External Signer listening on " + path + " for " + strconv.Itoa(len(daemon.publicKeys)) + " public keys")
+
+	return daemon, nil
+}
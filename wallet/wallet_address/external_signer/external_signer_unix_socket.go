@@ -0,0 +1,49 @@
+package external_signer
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"time"
+)
+
+// the signer may ask the owner to confirm the signature
+const EXTERNAL_SIGNER_TIMEOUT = 2 * time.Minute
+
+// UnixSocketSigner requests the signatures from a local signer daemon. Every request is a JSON ExternalSignerRequest answered by a JSON ExternalSignerAnswer on a new connection
+type UnixSocketSigner struct {
+	Path string
+}
+
+func (signer *UnixSocketSigner) Sign(publicKey, message []byte) ([]byte, error) {
+
+	conn, err := net.DialTimeout("unix", signer.Path, EXTERNAL_SIGNER_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = conn.SetDeadline(time.Now().Add(EXTERNAL_SIGNER_TIMEOUT)); err != nil {
+		return nil, err
+	}
+
+	if err = json.NewEncoder(conn).Encode(&ExternalSignerRequest{publicKey, message}); err != nil {
+		return nil, err
+	}
+
+	answer := &ExternalSignerAnswer{}
+	if err = json.NewDecoder(conn).Decode(answer); err != nil {
+		return nil, err
+	}
+
+	if answer.Error != "" {
+		return nil, errors.New(answer.Error)
+	}
+	if len(answer.Signature) != cryptography.SignatureSize || !crypto.VerifySignature(message, answer.Signature, publicKey) {
+		return nil, errors.New("External signer returned an invalid signature")
+	}
+
+	return answer.Signature, nil
+}
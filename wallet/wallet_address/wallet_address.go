@@ -1,8 +1,10 @@
 package wallet_address
 
 import (
+	"bytes"
 	"errors"
 	"pandora-pay/addresses"
+	"pandora-pay/wallet/wallet_address/external_signer"
 	"pandora-pay/wallet/wallet_address/shared_staked"
 )
 
@@ -15,6 +17,7 @@ type WalletAddress struct {
 	SecretKey                  []byte                                   `json:"secretKey" msgpack:"secretKey"`
 	PrivateKey                 *addresses.PrivateKey                    `json:"privateKey" msgpack:"privateKey"`
 	SpendPrivateKey            *addresses.PrivateKey                    `json:"spendPrivateKey" msgpack:"spendPrivateKey"`
+	ViewKey                    *addresses.PrivateKey                    `json:"viewKey,omitempty" msgpack:"viewKey,omitempty"` //decryption key of the watch-only addresses. It is the private key, so the address must require the spend key
 	Registration               []byte                                   `json:"registration" msgpack:"registration"`
 	PublicKey                  []byte                                   `json:"publicKey" msgpack:"publicKey"`
 	Staked                     bool                                     `json:"staked" msgpack:"staked"`
@@ -22,6 +25,7 @@ type WalletAddress struct {
 	SpendPublicKey             []byte                                   `json:"spendPublicKey" msgpack:"spendPublicKey"`
 	IsSharedStaked             bool                                     `json:"isSharedStaked,omitempty" msgpack:"isSharedStaked,omitempty"`
 	SharedStaked               *shared_staked.WalletAddressSharedStaked `json:"sharedStaked,omitempty" msgpack:"sharedStaked,omitempty"`
+	ExternalSigner             *external_signer.ExternalSigner          `json:"externalSigner,omitempty" msgpack:"externalSigner,omitempty"`
 	AddressEncoded             string                                   `json:"addressEncoded" msgpack:"addressEncoded"`
 	AddressRegistrationEncoded string                                   `json:"addressRegistrationEncoded" msgpack:"addressRegistrationEncoded"`
 }
@@ -39,6 +43,36 @@ func (addr *WalletAddress) DeriveSharedStaked() (*shared_staked.WalletAddressSha
 
 }
 
+// GetSigner returns the signer of the public key. Keys missing from the wallet are signed by the external signer
+func (addr *WalletAddress) GetSigner(publicKey []byte) (func(message []byte) ([]byte, error), error) {
+
+	var privateKey *addresses.PrivateKey
+	if bytes.Equal(publicKey, addr.PublicKey) {
+		privateKey = addr.PrivateKey
+	} else if len(addr.SpendPublicKey) > 0 && bytes.Equal(publicKey, addr.SpendPublicKey) {
+		privateKey = addr.SpendPrivateKey
+	} else {
+		return nil, errors.New("Public Key doesn't belong to the address")
+	}
+
+	if privateKey != nil {
+		return privateKey.Sign, nil
+	}
+
+	if addr.ExternalSigner == nil {
+		return nil, errors.New("Private Key is missing")
+	}
+
+	signer, err := addr.ExternalSigner.GetSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(message []byte) ([]byte, error) {
+		return signer.Sign(publicKey, message)
+	}, nil
+}
+
 func (addr *WalletAddress) GetAddress(registered bool) string {
 	if registered {
 		return addr.AddressEncoded
@@ -46,11 +80,25 @@ func (addr *WalletAddress) GetAddress(registered bool) string {
 	return addr.AddressRegistrationEncoded
 }
 
+// GetViewKey returns the key used to decrypt the balances. Watch-only addresses only have the view key, and they require a spend key they don't have
+func (addr *WalletAddress) GetViewKey() *addresses.PrivateKey {
+	if addr.PrivateKey != nil {
+		return addr.PrivateKey
+	}
+	return addr.ViewKey
+}
+
+// IsWatchOnly returns true if the address can't sign transactions
+func (addr *WalletAddress) IsWatchOnly() bool {
+	return addr.PrivateKey == nil && addr.ExternalSigner == nil
+}
+
 func (addr *WalletAddress) DecryptMessage(message []byte) ([]byte, error) {
-	if addr.PrivateKey == nil {
+	viewKey := addr.GetViewKey()
+	if viewKey == nil {
 		return nil, errors.New("Private Key is missing")
 	}
-	return addr.PrivateKey.Decrypt(message)
+	return viewKey.Decrypt(message)
 }
 
 func (addr *WalletAddress) SignMessage(message []byte) ([]byte, error) {
@@ -79,6 +127,11 @@ func (addr *WalletAddress) Clone() *WalletAddress {
 		sharedStaked = &shared_staked.WalletAddressSharedStaked{addr.SharedStaked.PrivateKey, addr.SharedStaked.PublicKey}
 	}
 
+	var externalSigner *external_signer.ExternalSigner
+	if addr.ExternalSigner != nil {
+		externalSigner = &external_signer.ExternalSigner{addr.ExternalSigner.Type, addr.ExternalSigner.Path}
+	}
+
 	return &WalletAddress{
 		addr.Version,
 		addr.Name,
@@ -88,6 +141,7 @@ func (addr *WalletAddress) Clone() *WalletAddress {
 		addr.SecretKey,
 		addr.PrivateKey,
 		addr.SpendPrivateKey,
+		addr.ViewKey,
 		addr.Registration,
 		addr.PublicKey,
 		addr.Staked,
@@ -95,6 +149,7 @@ func (addr *WalletAddress) Clone() *WalletAddress {
 		addr.SpendPublicKey,
 		addr.IsSharedStaked,
 		sharedStaked,
+		externalSigner,
 		addr.AddressEncoded,
 		addr.AddressRegistrationEncoded,
 	}
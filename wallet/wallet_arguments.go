@@ -5,8 +5,10 @@ import (
 	"errors"
 	"pandora-pay/config/arguments"
 	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_address/external_signer"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func (wallet *Wallet) ProcessWalletArguments() (err error) {
@@ -46,6 +48,16 @@ func (wallet *Wallet) ProcessWalletArguments() (err error) {
 		}
 	}
 
+	if str := arguments.Arguments["--wallet-auto-lock"]; str != nil {
+		var seconds uint64
+		if seconds, err = strconv.ParseUint(str.(string), 10, 64); err != nil {
+			return
+		}
+		wallet.Lock.Lock()
+		wallet.autoLockTimeout = time.Duration(seconds) * time.Second
+		wallet.Lock.Unlock()
+	}
+
 	if arguments.Arguments["--wallet-remove-encryption"] == true {
 		if err = wallet.Encryption.RemoveEncryption(); err != nil {
 			return
@@ -86,5 +98,26 @@ func (wallet *Wallet) ProcessWalletArguments() (err error) {
 
 	}
 
+	if path := arguments.Arguments["--wallet-signer-daemon"]; path != nil {
+
+		publicKeys := [][]byte{}
+		if keys := arguments.Arguments["--wallet-signer-daemon-keys"]; keys != nil {
+			for _, key := range strings.Split(keys.(string), ",") {
+				var publicKey []byte
+				if publicKey, err = base64.StdEncoding.DecodeString(strings.TrimSpace(key)); err != nil {
+					return
+				}
+				publicKeys = append(publicKeys, publicKey)
+			}
+		}
+		if len(publicKeys) == 0 {
+			return errors.New("--wallet-signer-daemon requires --wallet-signer-daemon-keys")
+		}
+
+		if wallet.signerDaemon, err = external_signer.StartSignerDaemon(path.(string), publicKeys, wallet.getSignerPrivateKey); err != nil {
+			return
+		}
+	}
+
 	return
 }
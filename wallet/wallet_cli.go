@@ -27,8 +27,10 @@ import (
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_address/external_signer"
 	"pandora-pay/wallet/wallet_address/shared_staked"
 	"strconv"
+	"time"
 )
 
 func (wallet *Wallet) exportSharedStakedAddress(addr *wallet_address.WalletAddress, path string, print bool) (*shared_staked.WalletAddressSharedStakedAddressExported, error) {
@@ -126,6 +128,8 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 		name                    string
 		addressString           string
 		addressRegisteredString string
+		watchOnly               bool
+		decryptable             bool
 	}
 
 	wallet.Lock.RLock()
@@ -139,7 +143,7 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 	addresses := make([]*Address, len(wallet.Addresses))
 
 	for i, walletAddress := range wallet.Addresses {
-		addresses[i] = &Address{publicKey: helpers.CloneBytes(walletAddress.PublicKey), name: walletAddress.Name, addressString: walletAddress.GetAddress(false), addressRegisteredString: walletAddress.GetAddress(true)}
+		addresses[i] = &Address{publicKey: helpers.CloneBytes(walletAddress.PublicKey), name: walletAddress.Name, addressString: walletAddress.GetAddress(false), addressRegisteredString: walletAddress.GetAddress(true), watchOnly: walletAddress.IsWatchOnly(), decryptable: walletAddress.GetViewKey() != nil}
 	}
 	wallet.Lock.RUnlock()
 
@@ -207,6 +211,10 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 			gui.GUI.OutputWrite(fmt.Sprintf("%d) %s :: %s", i, address.name, address.addressString))
 		}
 
+		if address.watchOnly {
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s", "WATCH-ONLY"))
+		}
+
 		if len(addresses[i].assetsList) == 0 && addresses[i].plainAcc == nil {
 			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "", "EMPTY"))
 			continue
@@ -238,6 +246,11 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 				gui.GUI.OutputWrite(fmt.Sprintf("%18s: %64s", data.ast.Name, base64.StdEncoding.EncodeToString(data.balance.Serialize())))
 			}
 
+			if !address.decryptable {
+				gui.GUI.OutputWrite(fmt.Sprintf("%18s", "View Key is missing"))
+				continue
+			}
+
 			gui.GUI.OutputWrite(fmt.Sprintf("%18s", "Decrypting...."))
 
 			for _, data := range addresses[i].assetsList {
@@ -558,6 +571,26 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliShowAddressViewKey := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address to show the view key", ctx)
+		if err != nil {
+			return
+		}
+
+		viewKey := addr.GetViewKey()
+		if viewKey == nil {
+			return errors.New("View Key is missing")
+		}
+		if !addr.SpendRequired {
+			gui.GUI.OutputWrite("WARNING!!! The address doesn't require a spend key. The view key is enough to spend its funds!")
+		}
+
+		gui.GUI.OutputWrite("View Key", viewKey.Key)
+
+		return
+	}
+
 	cliImportAddressSecretKey := func(cmd string, ctx context.Context) (err error) {
 
 		secretKey := gui.GUI.OutputReadBytes("Write Secret key", func(input []byte) bool {
@@ -578,6 +611,66 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliImportExternalSignerAddress := func(cmd string, ctx context.Context) (err error) {
+
+		address, err := addresses.DecodeAddr(gui.GUI.OutputReadString("Address"))
+		if err != nil {
+			return
+		}
+
+		path := gui.GUI.OutputReadString("Path of the External Signer Unix socket")
+
+		var privateKey *addresses.PrivateKey
+		if key := gui.GUI.OutputReadBytes("Private Key required for Zether transactions. Leave empty to keep the address watch-only", func(value []byte) bool {
+			return len(value) == 0 || len(value) == cryptography.PrivateKeySize
+		}); len(key) > 0 {
+			if privateKey, err = addresses.NewPrivateKey(key); err != nil {
+				return
+			}
+		}
+
+		name := gui.GUI.OutputReadString("Write Name of the newly imported address")
+
+		var adr *wallet_address.WalletAddress
+		if adr, err = wallet.ImportExternalSignerAddress(name, address, privateKey, &external_signer.ExternalSigner{external_signer.EXTERNAL_SIGNER_UNIX_SOCKET, path}); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Address was imported: " + adr.AddressEncoded)
+
+		return
+	}
+
+	cliImportWatchOnlyAddress := func(cmd string, ctx context.Context) (err error) {
+
+		address, err := addresses.DecodeAddr(gui.GUI.OutputReadString("Address"))
+		if err != nil {
+			return
+		}
+
+		if len(address.SpendPublicKey) == 0 {
+			return errors.New("The address doesn't require a spend key. The view key is enough to spend its funds, so it can't be imported as watch-only")
+		}
+
+		viewKey, err := addresses.NewPrivateKey(gui.GUI.OutputReadBytes("View Key", func(value []byte) bool {
+			return len(value) == cryptography.PrivateKeySize
+		}))
+		if err != nil {
+			return
+		}
+
+		name := gui.GUI.OutputReadString("Write Name of the newly imported address")
+
+		var adr *wallet_address.WalletAddress
+		if adr, err = wallet.ImportWatchOnlyAddress(name, address, viewKey); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Watch-only address was imported: " + adr.AddressEncoded)
+
+		return
+	}
+
 	cliEncryptWallet := func(cmd string, ctx context.Context) (err error) {
 
 		password := gui.GUI.OutputReadString("Password for encrypting wallet")
@@ -605,6 +698,24 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliLockWallet := func(cmd string, ctx context.Context) (err error) {
+		if err = wallet.Encryption.Lock(); err == nil {
+			gui.GUI.OutputWrite("Wallet locked successfully")
+		}
+		return
+	}
+
+	cliUnlockWallet := func(cmd string, ctx context.Context) (err error) {
+
+		password := gui.GUI.OutputReadString("Password for unlocking wallet")
+		ttl := gui.GUI.OutputReadUint64("Seconds of inactivity until the wallet is locked again. Leave empty to use --wallet-auto-lock", true, 0, nil)
+
+		if err = wallet.Encryption.Unlock(password, time.Duration(ttl)*time.Second); err == nil {
+			gui.GUI.OutputWrite("Wallet unlocked successfully")
+		}
+		return
+	}
+
 	cliRemoveEncryption := func(cmd string, ctx context.Context) (err error) {
 		gui.GUI.OutputWrite("Wallet removing encryption...")
 		if err = wallet.Encryption.RemoveEncryption(); err == nil {
@@ -720,6 +831,9 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("Import Entropy", cliImportEntropy, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Show Address Secret Key", cliShowAddressSecretKey, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Address Secret Key", cliImportAddressSecretKey, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Show Address View Key", cliShowAddressViewKey, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Import Watch-Only Address", cliImportWatchOnlyAddress, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Import Address with External Signer", cliImportExternalSignerAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Address", cliRemoveAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Staked Staked Address", cliExportSharedStakedAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Addresses", cliExportAddresses, wallet.Loaded)
@@ -730,6 +844,8 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("Encrypt Wallet", cliEncryptWallet, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Encryption", cliRemoveEncryption, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Decrypt Wallet", cliDecryptWallet, !wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Lock Wallet", cliLockWallet, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Unlock Wallet", cliUnlockWallet, wallet.Loaded)
 
 	gui.GUI.CommandDefineCallback("Create (PublicKey, PrivateKey) pair", cliCreatePair, true)
 	gui.GUI.CommandDefineCallback("Sign message using PrivateKey", cliSignMessage, true)
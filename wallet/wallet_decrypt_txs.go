@@ -64,6 +64,11 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 
 				if addr := w.GetWalletAddressByPublicKey(publicKey, true); addr != nil {
 
+					viewKey := addr.GetViewKey()
+					if viewKey == nil {
+						continue
+					}
+
 					decyptedZetherPayload := &DecryptZetherPayloadOutput{
 						RecipientIndex: -1,
 						Asset:          payload.Asset,
@@ -71,7 +76,7 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 					output.ZetherTx.Payloads[t] = decyptedZetherPayload
 
 					echanges := crypto.ConstructElGamal(payload.Statement.C[i], payload.Statement.D)
-					secretPoint := new(crypto.BNRed).SetBytes(addr.PrivateKey.Key)
+					secretPoint := new(crypto.BNRed).SetBytes(viewKey.Key)
 
 					//check sender whisper
 					v2Computed := crypto.ReducedHash(new(bn256.G1).ScalarMult(payload.Statement.D, secretPoint.BigInt()).EncodeCompressed())
@@ -82,7 +87,7 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 						amount := v2Value.Uint64()
 						if err := helpers.SafeUint64Add(&amount, payload.Statement.Fee); err == nil {
 							if err := helpers.SafeUint64Add(&amount, payload.BurnValue); err == nil {
-								if addr.PrivateKey.TryDecryptBalance(echanges.Neg(), amount) {
+								if viewKey.TryDecryptBalance(echanges.Neg(), amount) {
 									decyptedZetherPayload.WhisperSenderValid = true
 									decyptedZetherPayload.SentAmount = amount
 								}
@@ -97,7 +102,7 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 
 					if v1Value.IsUint64() {
 						amount := v1Value.Uint64()
-						if addr.PrivateKey.TryDecryptBalance(echanges, amount) {
+						if viewKey.TryDecryptBalance(echanges, amount) {
 							decyptedZetherPayload.WhisperRecipientValid = true
 							decyptedZetherPayload.ReceivedAmount = amount
 						}
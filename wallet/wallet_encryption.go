@@ -1,10 +1,12 @@
 package wallet
 
 import (
+	"crypto/subtle"
 	"errors"
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography/encryption"
 	"pandora-pay/helpers"
+	"time"
 )
 
 type WalletEncryption struct {
@@ -14,12 +16,16 @@ type WalletEncryption struct {
 	Difficulty       int              `json:"difficulty" msgpack:"difficulty"`
 	password         string
 	encryptionCipher *encryption.EncryptionCipher
+	locked           bool          //the secrets were wiped from the memory
+	lockTimeout      time.Duration //inactivity of the unlocked session. Zero uses the auto lock of the wallet
+	lastActivity     time.Time
 }
 
 func createEncryption(wallet *Wallet) *WalletEncryption {
 	return &WalletEncryption{
-		wallet:    wallet,
-		Encrypted: ENCRYPTED_VERSION_PLAIN_TEXT,
+		wallet:       wallet,
+		Encrypted:    ENCRYPTED_VERSION_PLAIN_TEXT,
+		lastActivity: time.Now(),
 	}
 }
 
@@ -43,6 +49,7 @@ func (self *WalletEncryption) Encrypt(newPassword string, difficulty int) (err e
 	self.password = newPassword
 	self.Salt = helpers.RandomBytes(32)
 	self.Difficulty = difficulty
+	self.lastActivity = time.Now()
 
 	if err = self.createEncryptionCipher(); err != nil {
 		return
@@ -58,6 +65,9 @@ func (self *WalletEncryption) Encrypt(newPassword string, difficulty int) (err e
 
 func (self *WalletEncryption) encryptData(input []byte) ([]byte, error) {
 	if self.Encrypted == ENCRYPTED_VERSION_ENCRYPTION_ARGON2 {
+		if self.encryptionCipher == nil {
+			return nil, errors.New("Wallet is locked")
+		}
 		return self.encryptionCipher.Encrypt(input)
 	}
 	return input, nil
@@ -76,6 +86,9 @@ func (self *WalletEncryption) Decrypt(password string) (err error) {
 
 func (self *WalletEncryption) decryptData(input []byte) ([]byte, error) {
 	if self.Encrypted == ENCRYPTED_VERSION_ENCRYPTION_ARGON2 {
+		if self.encryptionCipher == nil {
+			return nil, errors.New("Wallet is locked")
+		}
 		return self.encryptionCipher.Decrypt(input)
 	}
 	return input, nil
@@ -98,7 +111,7 @@ func (self *WalletEncryption) CheckPassword(password string, requirePassword boo
 		}
 	}
 
-	if self.password != password {
+	if subtle.ConstantTimeCompare([]byte(self.password), []byte(password)) != 1 {
 		return errors.New("Password is not matching")
 	}
 
@@ -115,6 +128,9 @@ func (self *WalletEncryption) RemoveEncryption() (err error) {
 	if self.Encrypted == ENCRYPTED_VERSION_PLAIN_TEXT {
 		return errors.New("Wallet is not encrypted!")
 	}
+	if self.locked {
+		return errors.New("Wallet is locked")
+	}
 
 	self.Encrypted = ENCRYPTED_VERSION_PLAIN_TEXT
 	self.password = ""
@@ -0,0 +1,238 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/config/config_wallet"
+	"pandora-pay/config/globals"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet/wallet_address"
+	"strconv"
+	"time"
+)
+
+// must be locked before
+func (self *WalletEncryption) isLocked() bool {
+	return self.Encrypted != ENCRYPTED_VERSION_PLAIN_TEXT && (!self.wallet.Loaded || self.locked)
+}
+
+// must be locked before
+func (self *WalletEncryption) getLockTimeout() time.Duration {
+	if self.lockTimeout > 0 {
+		return self.lockTimeout
+	}
+	return self.wallet.autoLockTimeout
+}
+
+// must be locked before
+// isExpired returns true when the unlocked session was inactive for longer than its timeout
+func (self *WalletEncryption) isExpired() bool {
+	timeout := self.getLockTimeout()
+	return self.Encrypted != ENCRYPTED_VERSION_PLAIN_TEXT && !self.isLocked() && timeout > 0 && time.Since(self.lastActivity) >= timeout
+}
+
+// IsLocked returns true while the encrypted wallet can't sign. The wallet is locked also when it was not decrypted
+func (self *WalletEncryption) IsLocked() bool {
+	self.wallet.Lock.RLock()
+	defer self.wallet.Lock.RUnlock()
+	return self.isLocked()
+}
+
+// CheckUnlocked is called by the spend operations. They fail while the wallet is locked and they extend the unlocked session
+func (self *WalletEncryption) CheckUnlocked() error {
+	self.wallet.Lock.Lock()
+	defer self.wallet.Lock.Unlock()
+
+	if self.isLocked() {
+		return errors.New("Wallet is locked")
+	}
+
+	self.lastActivity = time.Now()
+	return nil
+}
+
+// must be locked before
+// lock wipes the secrets of the addresses, the seed and the password. The addresses remain loaded, but the balances can't be decrypted until the wallet is unlocked.
+// The delegated staking addresses are kept as they are required by the forging
+func (self *WalletEncryption) lock() {
+
+	for _, addr := range self.wallet.Addresses {
+		if addr.IsSharedStaked {
+			continue
+		}
+		addr.SecretKey = nil
+		addr.PrivateKey = nil
+		addr.SpendPrivateKey = nil
+		addr.ViewKey = nil
+		addr.SharedStaked = nil
+	}
+
+	self.wallet.Mnemonic = ""
+	self.wallet.Seed = nil
+	self.password = ""
+	self.encryptionCipher = nil
+	self.locked = true
+}
+
+// must be locked before
+// restoreSecrets decrypts again the secrets of the stored addresses. The wallet is not saved while it is locked, so the stored addresses are the loaded ones
+func (self *WalletEncryption) restoreSecrets(password string) error {
+
+	encryption := &WalletEncryption{
+		wallet:     self.wallet,
+		Encrypted:  self.Encrypted,
+		Salt:       self.Salt,
+		Difficulty: self.Difficulty,
+		password:   password,
+	}
+	if err := encryption.createEncryptionCipher(); err != nil {
+		return err
+	}
+
+	return store.StoreWallet.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		var unmarshal []byte
+		if unmarshal, err = encryption.decryptData(reader.Get("wallet")); err != nil {
+			return errors.New("Password is not matching")
+		}
+
+		stored := &Wallet{}
+		if err = msgpack.Unmarshal(unmarshal, stored); err != nil {
+			return
+		}
+
+		addresses := make([]*wallet_address.WalletAddress, len(self.wallet.Addresses))
+		for i := range self.wallet.Addresses {
+
+			if unmarshal, err = encryption.decryptData(reader.Get("wallet-address-" + strconv.Itoa(i))); err != nil {
+				return
+			}
+
+			addresses[i] = &wallet_address.WalletAddress{}
+			if err = msgpack.Unmarshal(unmarshal, addresses[i]); err != nil {
+				return
+			}
+
+			if !bytes.Equal(addresses[i].PublicKey, self.wallet.Addresses[i].PublicKey) {
+				return errors.New("Stored addresses are not matching!")
+			}
+			if addresses[i].PrivateKey != nil && !bytes.Equal(addresses[i].PrivateKey.GeneratePublicKey(), addresses[i].PublicKey) {
+				return errors.New("Public Keys are not matching!")
+			}
+		}
+
+		for i, addr := range self.wallet.Addresses {
+			addr.SecretKey = addresses[i].SecretKey
+			addr.PrivateKey = addresses[i].PrivateKey
+			addr.SpendPrivateKey = addresses[i].SpendPrivateKey
+			addr.ViewKey = addresses[i].ViewKey
+			addr.SharedStaked = addresses[i].SharedStaked
+		}
+
+		self.wallet.Mnemonic = stored.Mnemonic
+		self.wallet.Seed = stored.Seed
+		self.password = password
+		self.encryptionCipher = encryption.encryptionCipher
+		self.locked = false
+
+		return
+	})
+}
+
+// Lock wipes the secrets of the encrypted wallet from the memory
+func (self *WalletEncryption) Lock() error {
+	self.wallet.Lock.Lock()
+	defer self.wallet.Lock.Unlock()
+
+	if !self.wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+	if self.Encrypted == ENCRYPTED_VERSION_PLAIN_TEXT {
+		return errors.New("Wallet is not encrypted!")
+	}
+
+	if !self.locked {
+		self.lock()
+		globals.MainEvents.BroadcastEvent("wallet/locked", true)
+	}
+
+	return nil
+}
+
+// Unlock decrypts the secrets of the wallet. The wallet is locked again after ttl of inactivity. A zero ttl uses the auto lock of the wallet
+func (self *WalletEncryption) Unlock(password string, ttl time.Duration) (err error) {
+
+	if ttl < 0 || ttl > config_wallet.WALLET_UNLOCK_MAX_TTL {
+		return errors.New("Invalid unlock ttl")
+	}
+
+	wallet := self.wallet
+
+	wallet.Lock.RLock()
+	loaded := wallet.Loaded
+	wallet.Lock.RUnlock()
+
+	//the wallet was never decrypted
+	if !loaded {
+		if err = wallet.loadWallet(password, false); err != nil {
+			return
+		}
+	}
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	encryption := wallet.Encryption
+	if encryption.Encrypted == ENCRYPTED_VERSION_PLAIN_TEXT {
+		return errors.New("Wallet is not encrypted!")
+	}
+
+	if encryption.locked {
+		if err = encryption.restoreSecrets(password); err != nil {
+			return
+		}
+	} else if subtle.ConstantTimeCompare([]byte(encryption.password), []byte(password)) != 1 {
+		return errors.New("Password is not matching")
+	}
+
+	encryption.lockTimeout = ttl
+	encryption.lastActivity = time.Now()
+
+	globals.MainEvents.BroadcastEvent("wallet/unlocked", true)
+	return
+}
+
+// processAutoLock locks the encrypted wallet once the unlocked session was inactive for longer than its timeout
+func (wallet *Wallet) processAutoLock() {
+	recovery.SafeGo(func() {
+		for {
+
+			time.Sleep(time.Second)
+
+			wallet.Lock.RLock()
+			expired := wallet.Encryption.isExpired()
+			wallet.Lock.RUnlock()
+
+			if !expired {
+				continue
+			}
+
+			//the session could have been extended or locked meanwhile
+			wallet.Lock.Lock()
+			if expired = wallet.Encryption.isExpired(); expired {
+				wallet.Encryption.lock()
+			}
+			wallet.Lock.Unlock()
+
+			if expired {
+				gui.GUI.Log("Wallet was locked after inactivity")
+				globals.MainEvents.BroadcastEvent("wallet/locked", true)
+			}
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package wallet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_bunt"
+	"pandora-pay/wallet/wallet_address"
+	"testing"
+	"time"
+)
+
+func createTestEncryptedWallet(t *testing.T) *Wallet {
+
+	gui.GUI, _ = gui_non_interactive.CreateGUINonInteractive()
+
+	db, err := store_db_bunt.CreateStoreDBBunt("/wallet", true)
+	assert.NoError(t, err)
+	store.StoreWallet = &store.Store{Name: "/wallet", Opened: true, DB: db}
+	t.Cleanup(func() {
+		store.StoreWallet = nil
+	})
+
+	forging, err := forging.CreateForging(nil, nil)
+	assert.NoError(t, err)
+
+	wallet := createWallet(forging, nil, nil, nil)
+	assert.NoError(t, wallet.CreateEmptyWallet())
+
+	//a watch-only key which is also wiped
+	wallet.Addresses[0].ViewKey = addresses.GenerateNewPrivateKey()
+
+	assert.NoError(t, wallet.Encryption.Encrypt("password", 1))
+	return wallet
+}
+
+func TestWalletEncryptionLockUnlock(t *testing.T) {
+
+	wallet := createTestEncryptedWallet(t)
+	addr := wallet.Addresses[0]
+	privateKey, viewKey, seed := addr.PrivateKey.Key, addr.ViewKey.Key, wallet.Seed
+
+	assert.False(t, wallet.Encryption.IsLocked())
+	assert.NoError(t, wallet.Encryption.CheckUnlocked())
+
+	assert.NoError(t, wallet.Encryption.Lock())
+	assert.True(t, wallet.Encryption.IsLocked())
+	assert.Nil(t, addr.PrivateKey)
+	assert.Nil(t, addr.ViewKey)
+	assert.Nil(t, wallet.Seed)
+	assert.Empty(t, wallet.Encryption.password)
+
+	//the spend operations are rejected
+	assert.EqualError(t, wallet.Encryption.CheckUnlocked(), "Wallet is locked")
+
+	assert.EqualError(t, wallet.Encryption.Unlock("wrong", 0), "Password is not matching")
+	assert.True(t, wallet.Encryption.IsLocked())
+
+	assert.NoError(t, wallet.Encryption.Unlock("password", time.Minute))
+	assert.False(t, wallet.Encryption.IsLocked())
+	assert.NoError(t, wallet.Encryption.CheckUnlocked())
+	assert.Equal(t, privateKey, addr.PrivateKey.Key)
+	assert.Equal(t, viewKey, addr.ViewKey.Key)
+	assert.Equal(t, seed, wallet.Seed)
+
+	//an unlocked wallet checks the password again
+	assert.EqualError(t, wallet.Encryption.Unlock("wrong", 0), "Password is not matching")
+	assert.NoError(t, wallet.Encryption.Unlock("password", 0))
+
+	assert.Error(t, wallet.Encryption.Unlock("password", -time.Second))
+}
+
+func TestWalletEncryptionLockExpired(t *testing.T) {
+
+	wallet := createTestEncryptedWallet(t)
+
+	assert.NoError(t, wallet.Encryption.Unlock("password", time.Minute))
+
+	wallet.Lock.Lock()
+	assert.False(t, wallet.Encryption.isExpired())
+
+	//the spend operations extend the session
+	wallet.Encryption.lastActivity = time.Now().Add(-2 * time.Minute)
+	assert.True(t, wallet.Encryption.isExpired())
+	wallet.Lock.Unlock()
+
+	assert.NoError(t, wallet.Encryption.CheckUnlocked())
+
+	wallet.Lock.Lock()
+	assert.False(t, wallet.Encryption.isExpired())
+	wallet.Encryption.lockTimeout = 10 * time.Millisecond
+	wallet.Lock.Unlock()
+
+	wallet.processAutoLock()
+	assert.Eventually(t, wallet.Encryption.IsLocked, 3*time.Second, 10*time.Millisecond)
+	assert.EqualError(t, wallet.Encryption.CheckUnlocked(), "Wallet is locked")
+
+	//a locked wallet doesn't expire
+	wallet.Lock.Lock()
+	assert.False(t, wallet.Encryption.isExpired())
+	wallet.Lock.Unlock()
+}
+
+func TestWalletEncryptionLockRejectsChanges(t *testing.T) {
+
+	wallet := createTestEncryptedWallet(t)
+
+	secret, privateKey, _, err := wallet.GenerateKeys(5, true)
+	assert.NoError(t, err)
+	privKey, err := addresses.NewPrivateKey(privateKey)
+	assert.NoError(t, err)
+
+	assert.NoError(t, wallet.Encryption.Lock())
+
+	addr := wallet.Addresses[0]
+	name, count, seedIndex := addr.Name, wallet.Count, wallet.SeedIndex
+
+	_, err = wallet.ImportSecretKey("imported", secret, false, false)
+	assert.EqualError(t, err, "Wallet is locked")
+	assert.EqualError(t, wallet.AddAddress(&wallet_address.WalletAddress{Name: "added", PrivateKey: privKey, IsMine: true}, false, false, true, true, false, true), "Wallet is locked")
+
+	_, err = wallet.RemoveAddressByIndex(0, true)
+	assert.EqualError(t, err, "Wallet is locked")
+
+	_, err = wallet.RenameAddressByPublicKey(addr.PublicKey, "renamed", true)
+	assert.EqualError(t, err, "Wallet is locked")
+
+	//the memory is not changed
+	assert.Len(t, wallet.Addresses, 1)
+	assert.Len(t, wallet.addressesMap, 1)
+	assert.Equal(t, count, wallet.Count)
+	assert.Equal(t, seedIndex, wallet.SeedIndex)
+	assert.Equal(t, name, addr.Name)
+
+	assert.NoError(t, wallet.Encryption.Unlock("password", 0))
+
+	renamed, err := wallet.RenameAddressByPublicKey(addr.PublicKey, "renamed", true)
+	assert.NoError(t, err)
+	assert.True(t, renamed)
+	assert.Equal(t, "renamed", addr.Name)
+}
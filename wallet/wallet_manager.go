@@ -9,11 +9,16 @@ import (
 	"github.com/tyler-smith/go-bip32"
 	"math/rand"
 	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/forging"
 	"pandora-pay/config/config_nodes"
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_address/external_signer"
 	"pandora-pay/wallet/wallet_address/shared_staked"
 	"strconv"
 )
@@ -105,6 +110,10 @@ func (wallet *Wallet) GetWalletAddressByPublicKey(publicKey []byte, lock bool) *
 
 func (wallet *Wallet) ImportSecretKey(name string, secret []byte, staked, spendRequired bool) (*wallet_address.WalletAddress, error) {
 
+	if wallet.Encryption.IsLocked() {
+		return nil, errors.New("Wallet is locked")
+	}
+
 	secretChild, err := bip32.Deserialize(secret)
 	if err != nil {
 		return nil, err
@@ -162,6 +171,9 @@ func (wallet *Wallet) AddSharedStakedAddress(addr *wallet_address.WalletAddress,
 	if !wallet.Loaded {
 		return errors.New("Wallet was not loaded!")
 	}
+	if wallet.Encryption.isLocked() {
+		return errors.New("Wallet is locked")
+	}
 
 	if wallet.Count > config_nodes.DELEGATES_MAXIMUM {
 		return errors.New("DELEGATES_MAXIMUM exceeded")
@@ -195,6 +207,166 @@ func (wallet *Wallet) AddSharedStakedAddress(addr *wallet_address.WalletAddress,
 	return
 }
 
+// AddRemoteSignerAddress stakes a delegated address whose private key is kept by the remote signer. It is not stored in the wallet
+func (wallet *Wallet) AddRemoteSignerAddress(publicKey []byte, remoteSigner forging.ForgingRemoteSigner) error {
+	return wallet.forging.Wallet.AddRemoteWallet(publicKey, remoteSigner)
+}
+
+func (wallet *Wallet) RemoveRemoteSignerAddress(publicKey []byte) {
+	wallet.forging.Wallet.RemoveWallet(publicKey, false, nil, nil, 0)
+}
+
+// addPublicAddress adds an address whose spend secret is not known by the wallet
+func (wallet *Wallet) addPublicAddress(addr *wallet_address.WalletAddress, address *addresses.Address, lock bool) (err error) {
+
+	if lock {
+		wallet.Lock.Lock()
+		defer wallet.Lock.Unlock()
+	}
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+	if wallet.Encryption.isLocked() {
+		return errors.New("Wallet is locked")
+	}
+
+	if viewKey := addr.GetViewKey(); viewKey != nil && !bytes.Equal(viewKey.GeneratePublicKey(), address.PublicKey) {
+		return errors.New("Private Key is not matching the address")
+	}
+
+	//the view key is the private key of the address, so it is watch-only only when the spend key is also required
+	if addr.PrivateKey == nil && addr.ViewKey != nil && addr.ExternalSigner == nil {
+		if len(address.SpendPublicKey) != cryptography.PublicKeySize {
+			return errors.New("View Key can spend the funds of an address which doesn't require a spend key. Import the address with its private key instead")
+		}
+		if addr.SpendPrivateKey != nil {
+			return errors.New("View Key and Spend Private Key can spend the funds. Import the address with its private key instead")
+		}
+	}
+
+	addr1, err := addresses.CreateAddr(address.PublicKey, address.Staked, address.SpendPublicKey, nil, nil, 0, nil)
+	if err != nil {
+		return
+	}
+
+	//the registration can only be signed by the private key
+	addr2 := addr1
+	if addr.PrivateKey != nil {
+		if addr2, err = addr.PrivateKey.GenerateAddress(address.Staked, address.SpendPublicKey, true, nil, 0, nil); err != nil {
+			return
+		}
+	} else if len(address.Registration) > 0 {
+		if addr2, err = addresses.CreateAddr(address.PublicKey, address.Staked, address.SpendPublicKey, address.Registration, nil, 0, nil); err != nil {
+			return
+		}
+	}
+
+	if wallet.addressesMap[string(address.PublicKey)] != nil {
+		return errors.New("Address exists")
+	}
+
+	addr.IsImported = true
+	addr.IsMine = true
+	addr.Registration = addr2.Registration
+	addr.PublicKey = address.PublicKey
+	addr.Staked = address.Staked
+	addr.SpendRequired = len(address.SpendPublicKey) > 0
+	addr.SpendPublicKey = address.SpendPublicKey
+	addr.AddressEncoded = addr1.EncodeAddr()
+	addr.AddressRegistrationEncoded = addr2.EncodeAddr()
+
+	if addr.Name == "" {
+		addr.Name = "Imported Address " + strconv.Itoa(wallet.CountImportedIndex)
+		wallet.CountImportedIndex += 1
+	}
+
+	if addr.PrivateKey != nil {
+		if addr.SharedStaked, err = addr.DeriveSharedStaked(); err != nil {
+			return
+		}
+	}
+
+	wallet.Addresses = append(wallet.Addresses, addr)
+	wallet.addressesMap[string(addr.PublicKey)] = addr
+
+	wallet.Count += 1
+
+	if err = wallet.forging.Wallet.AddWallet(addr.PublicKey, addr.SharedStaked, false, nil, nil, 0); err != nil {
+		return
+	}
+
+	wallet.updateWallet()
+	if err = wallet.saveWallet(len(wallet.Addresses)-1, len(wallet.Addresses), -1, false); err != nil {
+		return
+	}
+	globals.MainEvents.BroadcastEvent("wallet/added", addr)
+
+	return
+}
+
+// ImportExternalSignerAddress adds an address whose keys are kept by an external signer. The private key is optional, it is required only for Zether transactions in which case the external signer keeps only the spend private key
+func (wallet *Wallet) ImportExternalSignerAddress(name string, address *addresses.Address, privateKey *addresses.PrivateKey, externalSigner *external_signer.ExternalSigner) (*wallet_address.WalletAddress, error) {
+
+	if _, err := externalSigner.GetSigner(); err != nil {
+		return nil, err
+	}
+
+	addr := &wallet_address.WalletAddress{
+		Name:           name,
+		PrivateKey:     privateKey,
+		ExternalSigner: externalSigner,
+	}
+
+	if err := wallet.addPublicAddress(addr, address, true); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// ImportWatchOnlyAddress adds an address which can only decrypt its balances and transactions. The wallet refuses to use it as sender.
+// The view key is the private key of the address, so the address must require a spend key to make sure the view key alone can't spend
+func (wallet *Wallet) ImportWatchOnlyAddress(name string, address *addresses.Address, viewKey *addresses.PrivateKey) (*wallet_address.WalletAddress, error) {
+
+	if viewKey == nil {
+		return nil, errors.New("View Key is missing")
+	}
+
+	addr := &wallet_address.WalletAddress{
+		Name:    name,
+		ViewKey: viewKey,
+	}
+
+	if err := wallet.addPublicAddress(addr, address, true); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// getSignerPrivateKey returns the private key or the spend private key requested by the wallets using this wallet as external signer
+func (wallet *Wallet) getSignerPrivateKey(publicKey []byte) (*addresses.PrivateKey, error) {
+
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if wallet.Encryption.isLocked() {
+		return nil, errors.New("Wallet is locked")
+	}
+
+	for _, addr := range wallet.Addresses {
+		if addr.PrivateKey != nil && bytes.Equal(addr.PublicKey, publicKey) {
+			return addr.PrivateKey, nil
+		}
+		if addr.SpendPrivateKey != nil && bytes.Equal(addr.SpendPublicKey, publicKey) {
+			return addr.SpendPrivateKey, nil
+		}
+	}
+
+	return nil, errors.New("Private Key was not found")
+}
+
 func (wallet *Wallet) AddAddress(addr *wallet_address.WalletAddress, staked, spendRequired, lock bool, incrementSeedIndex, incrementImportedCountIndex, save bool) (err error) {
 
 	if lock {
@@ -205,6 +377,9 @@ func (wallet *Wallet) AddAddress(addr *wallet_address.WalletAddress, staked, spe
 	if !wallet.Loaded {
 		return errors.New("Wallet was not loaded!")
 	}
+	if wallet.Encryption.isLocked() {
+		return errors.New("Wallet is locked")
+	}
 
 	if addr.SpendPrivateKey != nil {
 		addr.SpendPublicKey = addr.SpendPrivateKey.GeneratePublicKey()
@@ -411,6 +586,9 @@ func (wallet *Wallet) RemoveAddressByIndex(index int, lock bool) (bool, error) {
 	if !wallet.Loaded {
 		return false, errors.New("Wallet was not loaded!")
 	}
+	if wallet.Encryption.isLocked() {
+		return false, errors.New("Wallet is locked")
+	}
 
 	if index < 0 || index > len(wallet.Addresses) {
 		return false, errors.New("Invalid Address Index")
@@ -477,8 +655,12 @@ func (wallet *Wallet) RenameAddressByPublicKey(publicKey []byte, newName string,
 	if !wallet.Loaded {
 		return false, errors.New("Wallet was not loaded!")
 	}
+	if wallet.Encryption.isLocked() {
+		return false, errors.New("Wallet is locked")
+	}
 
-	addr := wallet.GetWalletAddressByPublicKey(publicKey, false)
+	//the address of the wallet is renamed, not a clone of it
+	addr := wallet.addressesMap[string(publicKey)]
 	if addr == nil {
 		return false, nil
 	}
@@ -509,9 +691,40 @@ func (wallet *Wallet) GetAddressSecretKey(index int) ([]byte, error) { //32 byte
 	if index < 0 || index > len(wallet.Addresses) {
 		return nil, errors.New("Invalid Address Index")
 	}
+	if wallet.Encryption.isLocked() {
+		return nil, errors.New("Wallet is locked")
+	}
 	return wallet.Addresses[index].SecretKey, nil
 }
 
+// GenerateIntegratedAddress returns the address including the payment details. The registration is included while the account is not registered
+func (wallet *Wallet) GenerateIntegratedAddress(walletAddr *wallet_address.WalletAddress, paymentID []byte, paymentAmount uint64, paymentAsset []byte) (addr *addresses.Address, err error) {
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		var isReg bool
+		if isReg, err = dataStorage.Regs.Exists(string(walletAddr.PublicKey)); err != nil {
+			return
+		}
+
+		if walletAddr.PrivateKey == nil { //the registration of the addresses without private key was stored when they were imported
+			if !isReg {
+				addr, err = addresses.CreateAddr(walletAddr.PublicKey, walletAddr.Staked, walletAddr.SpendPublicKey, walletAddr.Registration, paymentID, paymentAmount, paymentAsset)
+			} else {
+				addr, err = addresses.CreateAddr(walletAddr.PublicKey, false, nil, nil, paymentID, paymentAmount, paymentAsset)
+			}
+		} else if !isReg {
+			addr, err = walletAddr.PrivateKey.GenerateAddress(walletAddr.Staked, walletAddr.SpendPublicKey, true, paymentID, paymentAmount, paymentAsset)
+		} else {
+			addr, err = walletAddr.PrivateKey.GenerateAddress(false, nil, false, paymentID, paymentAmount, paymentAsset)
+		}
+		return
+	})
+	return
+}
+
 func (wallet *Wallet) ImportWalletAddressJSON(data []byte) (*wallet_address.WalletAddress, error) {
 
 	addr := &wallet_address.WalletAddress{}
@@ -520,7 +733,20 @@ func (wallet *Wallet) ImportWalletAddressJSON(data []byte) (*wallet_address.Wall
 	}
 
 	if addr.PrivateKey == nil {
-		return nil, errors.New("Private Key is missing")
+
+		if addr.ViewKey == nil && addr.ExternalSigner == nil {
+			return nil, errors.New("Private Key is missing")
+		}
+
+		address, err := addresses.CreateAddr(addr.PublicKey, addr.Staked, addr.SpendPublicKey, addr.Registration, nil, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = wallet.addPublicAddress(addr, address, true); err != nil {
+			return nil, err
+		}
+		return addr, nil
 	}
 
 	wallet.Lock.RLock()
@@ -553,7 +779,12 @@ func (wallet *Wallet) DecryptBalance(addr *wallet_address.WalletAddress, encrypt
 		return 0, errors.New("Encrypted Balance is nil")
 	}
 
-	return wallet.addressBalanceDecryptor.DecryptBalance("wallet", addr.PublicKey, addr.PrivateKey.Key, encryptedBalance, asset, useNewPreviousValue, newPreviousValue, store, ctx, statusCallback)
+	viewKey := addr.GetViewKey()
+	if viewKey == nil {
+		return 0, errors.New("Private Key is missing")
+	}
+
+	return wallet.addressBalanceDecryptor.DecryptBalance("wallet", addr.PublicKey, viewKey.Key, encryptedBalance, asset, useNewPreviousValue, newPreviousValue, store, ctx, statusCallback)
 }
 
 func (wallet *Wallet) DecryptBalanceByPublicKey(publicKey []byte, encryptedBalance, asset []byte, useNewPreviousValue bool, newPreviousValue uint64, store, lock bool, ctx context.Context, statusCallback func(string)) (uint64, error) {
@@ -586,7 +817,12 @@ func (wallet *Wallet) TryDecryptBalance(addr *wallet_address.WalletAddress, encr
 		return false, err
 	}
 
-	return addr.PrivateKey.TryDecryptBalance(balance, matchValue), nil
+	viewKey := addr.GetViewKey()
+	if viewKey == nil {
+		return false, errors.New("Private Key is missing")
+	}
+
+	return viewKey.TryDecryptBalance(balance, matchValue), nil
 }
 
 func (wallet *Wallet) ImportWalletJSON(data []byte) (err error) {
@@ -629,5 +865,7 @@ func (wallet *Wallet) SetNonHardening(value bool) {
 }
 
 func (wallet *Wallet) Close() {
-
+	if wallet.signerDaemon != nil {
+		wallet.signerDaemon.Close()
+	}
 }
@@ -0,0 +1,226 @@
+package wallet_records
+
+import (
+	"bytes"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/config/globals"
+	"pandora-pay/gui"
+	"pandora-pay/helpers/recovery"
+	"pandora-pay/wallet"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WalletRecord is an element of the wallet modules which is identified by its Id
+type WalletRecord interface {
+	GetId() []byte
+}
+
+// WalletRecords is the list of records of a wallet module like the invoices or the scheduled payments.
+// The records are saved encrypted in the wallet store, reloaded with the wallet and encrypted again when the wallet password is changed
+type WalletRecords[T WalletRecord] struct {
+	wallet   *wallet.Wallet
+	key      string //key of the encrypted data in the wallet store
+	title    string //singular name used by the logs and the CLI
+	max      int
+	copy     func(T) T
+	List     []T         //sorted by the moment they were added
+	Lock     *sync.Mutex //List and the records in it
+	saveLock *sync.Mutex
+	OnReady  func() //optional. It is called once the records can be processed again: after they were reloaded with the wallet and when the wallet is unlocked
+}
+
+func (this *WalletRecords[T]) plural() string {
+	return this.title + "s"
+}
+
+// GetList returns copies of the records sorted by the moment they were added
+func (this *WalletRecords[T]) GetList() []T {
+
+	this.Lock.Lock()
+	defer this.Lock.Unlock()
+
+	list := make([]T, len(this.List))
+	for i, record := range this.List {
+		list[i] = this.copy(record)
+	}
+	return list
+}
+
+// Get returns a copy of the record or the zero value if it was not found
+func (this *WalletRecords[T]) Get(id []byte) (out T) {
+
+	this.Lock.Lock()
+	defer this.Lock.Unlock()
+
+	if index := this.Find(id); index != -1 {
+		out = this.copy(this.List[index])
+	}
+	return
+}
+
+// Find returns the index of the record or -1. It is locked before
+func (this *WalletRecords[T]) Find(id []byte) int {
+	for i, record := range this.List {
+		if bytes.Equal(record.GetId(), id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Insert appends the record and returns a copy of it. validate is called locked before the record is appended
+func (this *WalletRecords[T]) Insert(record T, validate func() error) (out T, err error) {
+
+	this.Lock.Lock()
+	if len(this.List) >= this.max {
+		this.Lock.Unlock()
+		return out, errors.New("Too many " + strings.ToLower(this.plural()))
+	}
+	if validate != nil {
+		if err = validate(); err != nil {
+			this.Lock.Unlock()
+			return
+		}
+	}
+	this.List = append(this.List, record)
+	out = this.copy(record)
+	this.Lock.Unlock()
+
+	if err = this.Save(); err != nil {
+		var empty T
+		return empty, err
+	}
+
+	return
+}
+
+func (this *WalletRecords[T]) Remove(id []byte) (bool, error) {
+
+	this.Lock.Lock()
+	index := this.Find(id)
+	if index != -1 {
+		this.List = append(this.List[:index], this.List[index+1:]...)
+	}
+	this.Lock.Unlock()
+
+	if index == -1 {
+		return false, nil
+	}
+
+	return true, this.Save()
+}
+
+func (this *WalletRecords[T]) Save() error {
+
+	this.saveLock.Lock()
+	defer this.saveLock.Unlock()
+
+	this.Lock.Lock()
+	marshal, err := msgpack.Marshal(this.List)
+	this.Lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return this.wallet.SaveEncryptedData(this.key, marshal)
+}
+
+func (this *WalletRecords[T]) load() error {
+
+	this.wallet.Lock.RLock()
+	loaded := this.wallet.Loaded
+	this.wallet.Lock.RUnlock()
+
+	list := []T{}
+
+	//encrypted wallets are loaded once they are decrypted
+	if loaded {
+
+		data, err := this.wallet.LoadEncryptedData(this.key)
+		if err != nil {
+			return err
+		}
+
+		if data != nil {
+			if err = msgpack.Unmarshal(data, &list); err != nil {
+				return err
+			}
+		}
+	}
+
+	this.Lock.Lock()
+	this.List = list
+	this.Lock.Unlock()
+
+	return nil
+}
+
+// processWalletEvents reloads the records with the wallet and encrypts them again when the wallet password is changed
+func (this *WalletRecords[T]) processWalletEvents() {
+
+	eventsCn := globals.MainEvents.AddListener()
+	defer globals.MainEvents.RemoveChannel(eventsCn)
+
+	for {
+		event, ok := <-eventsCn
+		if !ok {
+			return
+		}
+
+		switch event.Name {
+		case "wallet/loaded", "wallet/logged-out":
+			if err := this.load(); err != nil {
+				gui.GUI.Error("Error loading "+strings.ToLower(this.plural()), err)
+			} else if this.OnReady != nil {
+				this.OnReady()
+			}
+		case "wallet/unlocked":
+			if this.OnReady != nil {
+				this.OnReady()
+			}
+		case "wallet/encrypted", "wallet/removed-encryption":
+			if err := this.Save(); err != nil {
+				gui.GUI.Error("Error saving "+strings.ToLower(this.plural()), err)
+			}
+		}
+	}
+}
+
+// Initialize loads the records saved in the wallet store and keeps them in sync with the wallet
+func (this *WalletRecords[T]) Initialize(wallet *wallet.Wallet) error {
+
+	this.wallet = wallet
+
+	if err := this.load(); err != nil {
+		return err
+	}
+
+	this.Lock.Lock()
+	count := len(this.List)
+	this.Lock.Unlock()
+
+	gui.GUI.Log(this.plural() + " loaded " + strconv.Itoa(count))
+
+	recovery.SafeGo(this.processWalletEvents)
+
+	return nil
+}
+
+// NewWalletRecords creates the records saved under key. The wallet is set once it is initialized
+func NewWalletRecords[T WalletRecord](key, title string, max int, copy func(T) T) *WalletRecords[T] {
+	return &WalletRecords[T]{
+		nil,
+		key,
+		title,
+		max,
+		copy,
+		[]T{},
+		&sync.Mutex{},
+		&sync.Mutex{},
+		nil,
+	}
+}
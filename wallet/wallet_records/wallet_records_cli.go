@@ -0,0 +1,69 @@
+package wallet_records
+
+import (
+	"context"
+	"errors"
+	"pandora-pay/blockchain/data_storage/assets"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/gui"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strings"
+)
+
+// CliReadAssetAmount reads the asset and converts the amount to the units of the asset
+func CliReadAssetAmount(amountText string, allowEmpty bool) (assetId []byte, amount uint64, err error) {
+
+	assetId = gui.GUI.OutputReadBytes("Asset. Leave empty for Native Asset", func(input []byte) bool {
+		return len(input) == 0 || len(input) == config_coins.ASSET_LENGTH
+	})
+	if len(assetId) == 0 {
+		assetId = config_coins.NATIVE_ASSET_FULL
+	}
+
+	amountFloat := gui.GUI.OutputReadFloat64(amountText, allowEmpty, 0, nil)
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		var ast *asset.Asset
+		if ast, err = assets.NewAssets(reader).Get(string(assetId)); err != nil {
+			return
+		}
+		if ast == nil {
+			return errors.New("Asset was not found")
+		}
+		amount, err = ast.ConvertToUnits(amountFloat)
+		return
+	})
+	return
+}
+
+// CliRemove returns the CLI command which lists the records with cliList and removes the selected one
+func (this *WalletRecords[T]) CliRemove(cliList func(cmd string, ctx context.Context) error) func(cmd string, ctx context.Context) error {
+	return func(cmd string, ctx context.Context) (err error) {
+
+		list := this.GetList()
+		if len(list) == 0 {
+			return errors.New("There are no " + strings.ToLower(this.plural()))
+		}
+
+		if err = cliList(cmd, ctx); err != nil {
+			return
+		}
+
+		index := gui.GUI.OutputReadInt("Select "+this.title+" to remove", false, 0, func(value int) bool {
+			return value >= 0 && value < len(list)
+		})
+
+		removed, err := this.Remove(list[index].GetId())
+		if err != nil {
+			return
+		}
+		if !removed {
+			return errors.New(this.title + " was not found")
+		}
+
+		gui.GUI.OutputWrite(this.title + " removed")
+		return
+	}
+}
@@ -0,0 +1,47 @@
+package wallet_records
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testRecord struct {
+	Id    []byte
+	Value int
+}
+
+func (record *testRecord) GetId() []byte {
+	return record.Id
+}
+
+func TestWalletRecordsCopies(t *testing.T) {
+
+	records := NewWalletRecords[*testRecord]("test", "Test", 2, func(record *testRecord) *testRecord {
+		return &testRecord{record.Id, record.Value}
+	})
+	records.List = []*testRecord{{[]byte{1}, 1}, {[]byte{2}, 2}}
+
+	assert.Equal(t, 1, records.Find([]byte{2}))
+	assert.Equal(t, -1, records.Find([]byte{3}))
+	assert.Nil(t, records.Get([]byte{3}))
+
+	record := records.Get([]byte{1})
+	record.Value = 10
+	assert.Equal(t, 1, records.List[0].Value, "Get returns a copy")
+
+	list := records.GetList()
+	assert.Len(t, list, 2)
+	list[1].Value = 20
+	assert.Equal(t, 2, records.List[1].Value, "GetList returns copies")
+
+	_, err := records.Insert(&testRecord{[]byte{3}, 3}, nil)
+	assert.EqualError(t, err, "Too many tests")
+
+	records.max = 3
+	_, err = records.Insert(&testRecord{[]byte{3}, 3}, func() error {
+		return errors.New("rejected")
+	})
+	assert.EqualError(t, err, "rejected")
+	assert.Len(t, records.List, 2, "rejected record is not appended")
+}
@@ -199,6 +199,46 @@ func (wallet *Wallet) walletLoaded(firstTime bool) error {
 	return nil
 }
 
+// SaveEncryptedData stores data of the wallet modules encrypted with the wallet password like the addresses
+func (wallet *Wallet) SaveEncryptedData(key string, data []byte) error {
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+
+	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		if data, err = wallet.Encryption.encryptData(data); err != nil {
+			return
+		}
+		writer.Put("wallet-data-"+key, data)
+		return
+	})
+}
+
+// LoadEncryptedData returns nil if the data was never saved
+func (wallet *Wallet) LoadEncryptedData(key string) (data []byte, err error) {
+	wallet.Lock.RLock()
+	defer wallet.Lock.RUnlock()
+
+	if !wallet.Loaded {
+		return nil, errors.New("Wallet was not loaded!")
+	}
+
+	err = store.StoreWallet.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		if data = reader.Get("wallet-data-" + key); data == nil {
+			return
+		}
+		if data, err = wallet.Encryption.decryptData(data); err != nil {
+			return
+		}
+		data = helpers.CloneBytes(data)
+		return
+	})
+	return
+}
+
 func (wallet *Wallet) StartWallet() error {
 
 	wallet.Lock.Lock()